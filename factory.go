@@ -3,16 +3,22 @@ package tracesimulationreceiver
 import (
 	"context"
 	"fmt"
+	"github.com/k4ji/tracesimulationreceiver/internal/clock"
 	"github.com/k4ji/tracesimulationreceiver/internal/config"
 	"github.com/k4ji/tracesimulationreceiver/internal/config/blueprint"
 	"github.com/k4ji/tracesimulationreceiver/internal/config/global"
+	"github.com/k4ji/tracesimulationreceiver/internal/config/scenario"
 	"github.com/k4ji/tracesimulationreceiver/internal/metadata"
+	"github.com/k4ji/tracesimulationreceiver/internal/ratecontrol"
 	simulator "github.com/k4ji/tracesimulationreceiver/internal/tracesimulator"
 	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/adapter/opentelemetry"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/random"
+	tracesimulatorScenario "github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/scenario"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/receiver"
+	"time"
 )
 
 // createDefaultConfig creates the default configuration for the Trace Simulation receiver.
@@ -26,18 +32,67 @@ func createDefaultConfig() component.Config {
 func createTracesReceiver(_ context.Context, params receiver.Settings, baseCfg component.Config, consumer consumer.Traces) (receiver.Traces, error) {
 	logger := params.Logger
 	cfg := baseCfg.(*config.Config)
-	bp, err := cfg.Blueprint.To()
+	seed := time.Now().UnixNano()
+	if cfg.Global.Seed != nil {
+		seed = *cfg.Global.Seed
+	}
+	// all the randomness in the simulation is drawn from the source owned by the simulator
+	sim := simulator.New[[]ptrace.Traces](opentelemetry.NewAdapter(), random.NewSource(seed))
+	bp, err := cfg.Blueprint.To(sim.Randomness())
 	if err != nil {
 		return nil, fmt.Errorf("failed to convert blueprint: %w", err)
 	}
 
+	var rateController *ratecontrol.Controller
+	if cfg.Global.Rate != nil {
+		rateController, err = cfg.Global.Rate.To(sim.Randomness())
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert rate: %w", err)
+		}
+	}
+
+	var timeline *tracesimulatorScenario.Timeline
+	if len(cfg.Scenarios) > 0 {
+		timeline, err = scenario.To(cfg.Scenarios, sim.Randomness())
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert scenarios: %w", err)
+		}
+	}
+
+	var bf *backfill
+	if cfg.Global.Backfill != nil {
+		start, end, err := cfg.Global.Backfill.Range()
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert backfill: %w", err)
+		}
+		bf = &backfill{
+			start: start,
+			end:   end,
+			live:  cfg.Global.Backfill.ThenOrDefault() == global.BackfillThenLive,
+		}
+	}
+
+	var ds deliverySettings
+	if cfg.Global.Delivery != nil {
+		ds = deliverySettings{
+			realtime:      cfg.Global.Delivery.ModeOrDefault() == global.DeliveryModeRealtime,
+			batchInterval: cfg.Global.Delivery.BatchInterval,
+			shaper:        cfg.Global.Delivery.Shaper(sim.Randomness()),
+		}
+	}
+
 	rcvr := traceSimReceiver{
-		logger:        logger,
-		nextConsumer:  consumer,
-		simulator:     simulator.New[[]ptrace.Traces](opentelemetry.NewAdapter()),
-		interval:      cfg.Global.Interval,
-		endTimeOffset: cfg.Global.EndTimeOffset,
-		blueprint:     bp,
+		logger:         logger,
+		nextConsumer:   consumer,
+		simulator:      sim,
+		clock:          clock.NewReal(),
+		interval:       cfg.Global.Interval,
+		endTimeOffset:  cfg.Global.EndTimeOffset,
+		blueprint:      bp,
+		rateController: rateController,
+		timeline:       timeline,
+		delivery:       ds,
+		backfill:       bf,
 	}
 
 	return &rcvr, nil
@@ -0,0 +1,29 @@
+package clock
+
+import "time"
+
+// Clock tells the current time and waits for time to pass, so that the time can be simulated.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// After returns a channel that receives the current time once the duration has elapsed.
+	After(d time.Duration) <-chan time.Time
+}
+
+var _ Clock = (*Real)(nil)
+
+// Real is the clock of the system.
+type Real struct{}
+
+// NewReal creates a new clock of the system.
+func NewReal() *Real {
+	return &Real{}
+}
+
+func (c *Real) Now() time.Time {
+	return time.Now()
+}
+
+func (c *Real) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
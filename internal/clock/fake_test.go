@@ -0,0 +1,41 @@
+package clock
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestFake(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("time passes only when advanced", func(t *testing.T) {
+		c := NewFake(start)
+		assert.Equal(t, start, c.Now())
+		c.Advance(time.Minute)
+		assert.Equal(t, start.Add(time.Minute), c.Now())
+	})
+
+	t.Run("waiters are notified once their time has come", func(t *testing.T) {
+		c := NewFake(start)
+		early := c.After(time.Second)
+		late := c.After(time.Minute)
+		assert.Equal(t, 2, c.Waiters())
+
+		c.Advance(30 * time.Second)
+		assert.Equal(t, start.Add(30*time.Second), <-early)
+		assert.Len(t, late, 0)
+		assert.Equal(t, 1, c.Waiters())
+
+		c.Advance(30 * time.Second)
+		assert.Equal(t, start.Add(time.Minute), <-late)
+		assert.Equal(t, 0, c.Waiters())
+	})
+
+	t.Run("waiting for no time is notified immediately", func(t *testing.T) {
+		c := NewFake(start)
+		assert.Equal(t, start, <-c.After(0))
+		assert.Equal(t, start, <-c.After(-time.Second))
+		assert.Equal(t, 0, c.Waiters())
+	})
+}
@@ -27,13 +27,13 @@ func Validate(bp *Blueprint) error {
 	return nil
 }
 
-func (bp *Blueprint) To() (blueprint.Blueprint, error) {
+func (bp *Blueprint) To(randomness func() float64) (blueprint.Blueprint, error) {
 	switch bp.Type {
 	case "service":
 		if bp.ServiceBlueprint == nil {
 			return nil, fmt.Errorf("type is 'service' but service blueprint is nil")
 		}
-		return bp.ServiceBlueprint.To()
+		return bp.ServiceBlueprint.To(randomness)
 	}
 	return nil, fmt.Errorf("unknown blueprint type: %s", bp.Type)
 }
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"fmt"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/attribute"
+	"math"
+	"strconv"
+)
+
+// Attributes represents attributes whose value types are inferred from YAML.
+// A value can also be given in the explicit typed form, e.g. {type: string, value: 1.0},
+// or as a generator that yields a different value for each span, e.g. {generator: random_int, min: 1, max: 100}.
+// Strings whose types are inferred can reference trace-scoped variables as ${trace.<name>}.
+type Attributes map[string]any
+
+// To converts the attributes to a domain model.
+func (a Attributes) To(randomness func() float64) (map[string]attribute.Expression, error) {
+	if a == nil {
+		return nil, nil
+	}
+	attributes := make(map[string]attribute.Expression, len(a))
+	for k, v := range a {
+		if g, ok := v.(map[string]any); ok {
+			if _, isGenerator := g["generator"]; isGenerator {
+				generator, err := toGenerator(g, randomness)
+				if err != nil {
+					return nil, fmt.Errorf("invalid generator for attribute %s: %w", k, err)
+				}
+				attributes[k] = generator
+				continue
+			}
+		}
+		if str, ok := v.(string); ok {
+			expr, err := attribute.ParseTemplate(str)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for attribute %s: %w", k, err)
+			}
+			attributes[k] = expr
+			continue
+		}
+		value, err := toAttributeValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for attribute %s: %w", k, err)
+		}
+		attributes[k] = *value
+	}
+	return attributes, nil
+}
+
+// referencedVariables returns the names of the trace-scoped variables referenced by the attributes
+func (a Attributes) referencedVariables() ([]string, error) {
+	var names []string
+	for k, v := range a {
+		str, ok := v.(string)
+		if !ok {
+			continue
+		}
+		expr, err := attribute.ParseTemplate(str)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for attribute %s: %w", k, err)
+		}
+		if t, ok := expr.(attribute.Template); ok {
+			names = append(names, t.Variables()...)
+		}
+	}
+	return names, nil
+}
+
+func toAttributeValue(v any) (*attribute.Value, error) {
+	var value attribute.Value
+	switch v := v.(type) {
+	case nil:
+		return nil, fmt.Errorf("value must not be empty")
+	case string:
+		value = attribute.String(v)
+	case bool:
+		value = attribute.Bool(v)
+	case float32:
+		value = attribute.Double(float64(v))
+	case float64:
+		value = attribute.Double(v)
+	case []any:
+		return toSliceAttributeValue(v)
+	case []string:
+		value = attribute.StringSlice(v)
+	case map[string]any:
+		return toTypedAttributeValue(v)
+	default:
+		i, ok := toInt(v)
+		if !ok {
+			return nil, fmt.Errorf("unsupported value type %T", v)
+		}
+		value = attribute.Int(i)
+	}
+	return &value, nil
+}
+
+// infers the element type of the slice, which must be either all strings or all integers
+func toSliceAttributeValue(v []any) (*attribute.Value, error) {
+	if len(v) > 0 {
+		if _, ok := toInt(v[0]); ok {
+			ints, err := toInts(v)
+			if err != nil {
+				return nil, err
+			}
+			value := attribute.IntSlice(ints)
+			return &value, nil
+		}
+	}
+	strs := make([]string, 0, len(v))
+	for _, e := range v {
+		s, ok := e.(string)
+		if !ok {
+			return nil, fmt.Errorf("array must contain either only strings or only integers")
+		}
+		strs = append(strs, s)
+	}
+	value := attribute.StringSlice(strs)
+	return &value, nil
+}
+
+// converts the explicit typed form, which consists of the fields 'type' and 'value'
+func toTypedAttributeValue(t map[string]any) (*attribute.Value, error) {
+	typ, hasType := t["type"].(string)
+	v, hasValue := t["value"]
+	if len(t) != 2 || !hasType || !hasValue || v == nil {
+		return nil, fmt.Errorf("typed value must have exactly the fields 'type' and 'value'")
+	}
+	var value attribute.Value
+	switch typ {
+	case "string":
+		switch v := v.(type) {
+		case string:
+			value = attribute.String(v)
+		case []any, map[string]any:
+			return nil, fmt.Errorf("string value must be a scalar")
+		default:
+			value = attribute.String(fmt.Sprint(v))
+		}
+	case "int":
+		i, ok := toInt(v)
+		if !ok {
+			s, isString := v.(string)
+			if !isString {
+				return nil, fmt.Errorf("int value must be an integer, got %v", v)
+			}
+			parsed, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("int value must be an integer: %w", err)
+			}
+			i = parsed
+		}
+		value = attribute.Int(i)
+	case "double":
+		switch v := v.(type) {
+		case float32:
+			value = attribute.Double(float64(v))
+		case float64:
+			value = attribute.Double(v)
+		case string:
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, fmt.Errorf("double value must be a number: %w", err)
+			}
+			value = attribute.Double(f)
+		default:
+			i, ok := toInt(v)
+			if !ok {
+				return nil, fmt.Errorf("double value must be a number, got %v", v)
+			}
+			value = attribute.Double(float64(i))
+		}
+	case "bool":
+		switch v := v.(type) {
+		case bool:
+			value = attribute.Bool(v)
+		case string:
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, fmt.Errorf("bool value must be a boolean: %w", err)
+			}
+			value = attribute.Bool(b)
+		default:
+			return nil, fmt.Errorf("bool value must be a boolean, got %v", v)
+		}
+	case "string_array":
+		elems, ok := v.([]any)
+		if !ok {
+			return nil, fmt.Errorf("string_array value must be an array")
+		}
+		strs := make([]string, 0, len(elems))
+		for _, e := range elems {
+			switch e := e.(type) {
+			case []any, map[string]any, nil:
+				return nil, fmt.Errorf("string_array value must contain only scalars")
+			default:
+				strs = append(strs, fmt.Sprint(e))
+			}
+		}
+		value = attribute.StringSlice(strs)
+	case "int_array":
+		elems, ok := v.([]any)
+		if !ok {
+			return nil, fmt.Errorf("int_array value must be an array")
+		}
+		ints, err := toInts(elems)
+		if err != nil {
+			return nil, err
+		}
+		value = attribute.IntSlice(ints)
+	default:
+		return nil, fmt.Errorf("unknown attribute type: %s", typ)
+	}
+	return &value, nil
+}
+
+func toInts(v []any) ([]int64, error) {
+	ints := make([]int64, 0, len(v))
+	for _, e := range v {
+		i, ok := toInt(e)
+		if !ok {
+			return nil, fmt.Errorf("array must contain either only strings or only integers")
+		}
+		ints = append(ints, i)
+	}
+	return ints, nil
+}
+
+func toInt(v any) (int64, bool) {
+	switch v := v.(type) {
+	case int:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint:
+		return toIntFromUint(uint64(v))
+	case uint8:
+		return int64(v), true
+	case uint16:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	case uint64:
+		return toIntFromUint(v)
+	default:
+		return 0, false
+	}
+}
+
+func toIntFromUint(v uint64) (int64, bool) {
+	if v > math.MaxInt64 {
+		return 0, false
+	}
+	return int64(v), true
+}
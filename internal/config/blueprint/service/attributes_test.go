@@ -0,0 +1,143 @@
+package service
+
+import (
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/attribute"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+func TestAttributes_To(t *testing.T) {
+	t.Run("infer types from values", func(t *testing.T) {
+		attributes := Attributes{
+			"http.request.method":       "GET",
+			"http.response.status_code": 200,
+			"sampling.ratio":            0.5,
+			"feature.enabled":           true,
+			"tags":                      []any{"a", "b"},
+			"codes":                     []any{1, 2},
+		}
+		result, err := attributes.To(rand.Float64)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]attribute.Expression{
+			"http.request.method":       attribute.String("GET"),
+			"http.response.status_code": attribute.Int(200),
+			"sampling.ratio":            attribute.Double(0.5),
+			"feature.enabled":           attribute.Bool(true),
+			"tags":                      attribute.StringSlice([]string{"a", "b"}),
+			"codes":                     attribute.IntSlice([]int64{1, 2}),
+		}, result)
+	})
+
+	t.Run("explicit typed values", func(t *testing.T) {
+		attributes := Attributes{
+			"service.version": map[string]any{"type": "string", "value": 2},
+			"retry.count":     map[string]any{"type": "int", "value": "3"},
+			"sampling.ratio":  map[string]any{"type": "double", "value": 1},
+			"feature.enabled": map[string]any{"type": "bool", "value": "false"},
+			"tags":            map[string]any{"type": "string_array", "value": []any{"a", 1}},
+			"codes":           map[string]any{"type": "int_array", "value": []any{1, 2}},
+		}
+		result, err := attributes.To(rand.Float64)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]attribute.Expression{
+			"service.version": attribute.String("2"),
+			"retry.count":     attribute.Int(3),
+			"sampling.ratio":  attribute.Double(1),
+			"feature.enabled": attribute.Bool(false),
+			"tags":            attribute.StringSlice([]string{"a", "1"}),
+			"codes":           attribute.IntSlice([]int64{1, 2}),
+		}, result)
+	})
+
+	t.Run("nil attributes", func(t *testing.T) {
+		var attributes Attributes
+		result, err := attributes.To(rand.Float64)
+		assert.NoError(t, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		testCases := []struct {
+			name     string
+			value    any
+			expected string
+		}{
+			{name: "empty value", value: nil, expected: "invalid value for attribute key: value must not be empty"},
+			{name: "mixed array", value: []any{"a", 1}, expected: "invalid value for attribute key: array must contain either only strings or only integers"},
+			{name: "unsupported array element", value: []any{0.5}, expected: "invalid value for attribute key: array must contain either only strings or only integers"},
+			{name: "map without type", value: map[string]any{"value": 1}, expected: "invalid value for attribute key: typed value must have exactly the fields 'type' and 'value'"},
+			{name: "unknown type", value: map[string]any{"type": "float", "value": 1}, expected: "invalid value for attribute key: unknown attribute type: float"},
+			{name: "non-integer int", value: map[string]any{"type": "int", "value": 0.5}, expected: "invalid value for attribute key: int value must be an integer, got 0.5"},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				_, err := Attributes{"key": tc.value}.To(rand.Float64)
+				assert.EqualError(t, err, tc.expected)
+			})
+		}
+	})
+
+	t.Run("generators", func(t *testing.T) {
+		attributes := Attributes{
+			"http.response.status_code": map[string]any{"generator": "random_int", "min": 200, "max": 599},
+			"http.request.method": map[string]any{"generator": "weighted_enum", "values": []any{
+				map[string]any{"value": "GET", "weight": 3},
+				map[string]any{"value": "POST"},
+			}},
+			"request.id":          map[string]any{"generator": "uuid"},
+			"order.id":            map[string]any{"generator": "sequence", "start": 1000},
+			"client.address":      map[string]any{"generator": "random_ip", "cidr": "192.168.0.0/16"},
+			"enduser.id":          map[string]any{"generator": "user_id", "pool_size": 100},
+			"not.generator.typed": map[string]any{"type": "int", "value": 1},
+		}
+		result, err := attributes.To(rand.Float64)
+		assert.NoError(t, err)
+		assert.IsType(t, attribute.RandomInt{}, result["http.response.status_code"])
+		assert.IsType(t, attribute.WeightedEnum{}, result["http.request.method"])
+		assert.IsType(t, attribute.UUID{}, result["request.id"])
+		assert.IsType(t, attribute.RandomIP{}, result["client.address"])
+		assert.IsType(t, attribute.UserID{}, result["enduser.id"])
+		assert.Equal(t, attribute.Int(1), result["not.generator.typed"])
+
+		orderID, err := result["order.id"].Resolve(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, attribute.Int(1000), *orderID)
+		orderID, err = result["order.id"].Resolve(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, attribute.Int(1001), *orderID)
+
+		for i := 0; i < 100; i++ {
+			statusCode, err := result["http.response.status_code"].Resolve(nil)
+			assert.NoError(t, err)
+			assert.GreaterOrEqual(t, statusCode.AsInt(), int64(200))
+			assert.LessOrEqual(t, statusCode.AsInt(), int64(599))
+		}
+	})
+
+	t.Run("invalid generators", func(t *testing.T) {
+		testCases := []struct {
+			name     string
+			value    map[string]any
+			expected string
+		}{
+			{name: "unknown generator", value: map[string]any{"generator": "random_float"}, expected: "unknown generator: random_float"},
+			{name: "unknown field", value: map[string]any{"generator": "uuid", "version": 7}, expected: "unknown fields: version"},
+			{name: "missing max", value: map[string]any{"generator": "random_int", "min": 1}, expected: "max is required"},
+			{name: "min greater than max", value: map[string]any{"generator": "random_int", "min": 2, "max": 1}, expected: "random_int generator must have min less than or equal to max"},
+			{name: "no values", value: map[string]any{"generator": "weighted_enum", "values": []any{}}, expected: "weighted_enum generator must have at least one value"},
+			{name: "non-positive weight", value: map[string]any{"generator": "weighted_enum", "values": []any{map[string]any{"value": "a", "weight": 0}}}, expected: "value 0 of weighted_enum generator must have a weight greater than 0"},
+			{name: "invalid cidr", value: map[string]any{"generator": "random_ip", "cidr": "10.0.0.0"}, expected: "random_ip generator has an invalid cidr: invalid CIDR address: 10.0.0.0"},
+			{name: "IPv6 cidr", value: map[string]any{"generator": "random_ip", "cidr": "2001:db8::/32"}, expected: "random_ip generator supports only IPv4 networks"},
+			{name: "non-positive pool size", value: map[string]any{"generator": "user_id", "pool_size": 0}, expected: "user_id generator must have pool_size greater than 0"},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				_, err := Attributes{"key": tc.value}.To(rand.Float64)
+				assert.EqualError(t, err, "invalid generator for attribute key: "+tc.expected)
+			})
+		}
+	})
+}
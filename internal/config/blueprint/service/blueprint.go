@@ -5,6 +5,7 @@ import (
 	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/blueprint"
 	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/blueprint/service"
 	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/blueprint/service/model"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/attribute"
 )
 
 type Blueprint struct {
@@ -12,6 +13,9 @@ type Blueprint struct {
 	Default DefaultValues `mapstructure:"default"`
 	// Services is a list of services to simulate.
 	Services []Service `mapstructure:"services"`
+	// Variables are trace-scoped variables, which are generated once per trace
+	// and can be referenced from attributes as ${trace.<name>}.
+	Variables Attributes `mapstructure:"variables"`
 }
 
 // DefaultValues defines default values for span parameters.
@@ -24,10 +28,19 @@ type DefaultValues struct {
 
 	// ConditionalEffects specifies the default conditional effects for spans.
 	ConditionalEffects []ConditionalEffect `mapstructure:"conditional_effects"`
+
+	// ErrorPropagation specifies the default error propagation for spans.
+	ErrorPropagation *ErrorPropagation `mapstructure:"error_propagation"`
 }
 
 // Validate checks the configuration for errors.
 func (bp *Blueprint) Validate() error {
+	if err := bp.validateVariables(); err != nil {
+		return err
+	}
+	if err := bp.validateFlows(); err != nil {
+		return err
+	}
 	refs := make(map[string]struct{})
 	for _, s := range bp.Services {
 		if s.Name == "" {
@@ -44,6 +57,10 @@ func (bp *Blueprint) Validate() error {
 					refs[*sd.Ref] = struct{}{}
 				}
 			}
+			// the attempts of a root span would be separate roots, which a trace cannot have
+			if sd.Parent == nil && sd.Retry != nil {
+				return fmt.Errorf("span %s is the root of a trace, so it cannot have retry", sd.Name)
+			}
 			delay := sd.Delay.WithDefault(bp.Default.Delay)
 			if delay == nil {
 				return fmt.Errorf("span %s must have a delay value or global default", sd.Name)
@@ -63,33 +80,204 @@ func (bp *Blueprint) Validate() error {
 	return nil
 }
 
+// validateFlows checks that only the roots of flows have a weight or a rate, and that the mix of flows can be picked from.
+func (bp *Blueprint) validateFlows() error {
+	var checkNotRoot func(spanDefinitions []SpanDefinition) error
+	checkNotRoot = func(spanDefinitions []SpanDefinition) error {
+		for _, sd := range spanDefinitions {
+			if sd.Weight != nil || sd.Rate != nil {
+				return fmt.Errorf("span %s is not the root of a flow, so it cannot have weight or rate", sd.Name)
+			}
+			if err := checkNotRoot(sd.Children); err != nil {
+				return err
+			}
+			for _, v := range sd.Variants {
+				if err := checkNotRoot(v.Children); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	weighted := false
+	total := 0.0
+	for _, s := range bp.Services {
+		for _, sd := range s.SpanDefinitions {
+			if sd.Parent != nil {
+				if err := checkNotRoot([]SpanDefinition{sd}); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := checkNotRoot(sd.Children); err != nil {
+				return err
+			}
+			for _, v := range sd.Variants {
+				if err := checkNotRoot(v.Children); err != nil {
+					return err
+				}
+			}
+			if sd.Rate != nil {
+				if err := sd.Rate.Validate(); err != nil {
+					return fmt.Errorf("span %s has invalid rate: %w", sd.Name, err)
+				}
+				continue
+			}
+			weight := 1.0
+			if sd.Weight != nil {
+				weighted = true
+				weight = *sd.Weight
+			}
+			if weight < 0 {
+				return fmt.Errorf("span %s has invalid weight: must be greater than or equal to 0", sd.Name)
+			}
+			total += weight
+		}
+	}
+	if weighted && total <= 0 {
+		return fmt.Errorf("weights of the flows must sum to a value greater than 0")
+	}
+	return nil
+}
+
+// validateVariables checks the names of the variables and that all the references to them are defined.
+func (bp *Blueprint) validateVariables() error {
+	for name := range bp.Variables {
+		if !attribute.VariableNamePattern.MatchString(name) {
+			return fmt.Errorf("trace variable name %s must match the regex %s", name, attribute.VariableNamePattern)
+		}
+	}
+	names, err := bp.Variables.referencedVariables()
+	if err != nil {
+		return fmt.Errorf("invalid trace variables: %w", err)
+	}
+	if len(names) > 0 {
+		return fmt.Errorf("trace variables cannot reference other variables")
+	}
+
+	traceVariables := make(map[string]struct{}, len(bp.Variables))
+	for name := range bp.Variables {
+		traceVariables[attribute.Qualify(attribute.ScopeTrace, name)] = struct{}{}
+	}
+	check := func(attributes Attributes, defined map[string]struct{}, location string) error {
+		names, err := attributes.referencedVariables()
+		if err != nil {
+			return fmt.Errorf("%s has invalid attributes: %w", location, err)
+		}
+		for _, name := range names {
+			if _, exists := defined[name]; !exists {
+				return fmt.Errorf("%s references undefined variable %s", location, name)
+			}
+		}
+		return nil
+	}
+	checkEffects := func(conditionalEffects []ConditionalEffect, defined map[string]struct{}, location string) error {
+		for _, ce := range conditionalEffects {
+			for _, e := range ce.Effects {
+				if err := check(e.Annotate.Attributes, defined, location); err != nil {
+					return err
+				}
+				if err := check(e.RecordEvent.Event.Attributes, defined, location); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	var checkSpans func(spanDefinitions []SpanDefinition, defined map[string]struct{}) error
+	checkSpans = func(spanDefinitions []SpanDefinition, defined map[string]struct{}) error {
+		for _, sd := range spanDefinitions {
+			location := fmt.Sprintf("span %s", sd.Name)
+			// the index variable of a repeated span is available in the span and its descendants
+			inScope := defined
+			if sd.Repeat != nil && sd.Repeat.Index != nil {
+				inScope = make(map[string]struct{}, len(defined)+1)
+				for name := range defined {
+					inScope[name] = struct{}{}
+				}
+				inScope[attribute.Qualify(attribute.ScopeRepeat, *sd.Repeat.Index)] = struct{}{}
+			}
+			if err := check(sd.Attributes, inScope, location); err != nil {
+				return err
+			}
+			for _, e := range sd.Events {
+				if err := check(e.Attributes, inScope, location); err != nil {
+					return err
+				}
+			}
+			if err := checkEffects(sd.ConditionalEffects, inScope, location); err != nil {
+				return err
+			}
+			if err := checkSpans(sd.Children, inScope); err != nil {
+				return err
+			}
+			for i, v := range sd.Variants {
+				if err := check(v.Attributes, inScope, fmt.Sprintf("variant %d of span %s", i, sd.Name)); err != nil {
+					return err
+				}
+				if err := checkSpans(v.Children, inScope); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	if err := checkEffects(bp.Default.ConditionalEffects, traceVariables, "default"); err != nil {
+		return err
+	}
+	for _, s := range bp.Services {
+		if err := check(s.Resource, traceVariables, fmt.Sprintf("service %s", s.Name)); err != nil {
+			return err
+		}
+		if err := checkSpans(s.SpanDefinitions, traceVariables); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // To converts the Blueprint to a blueprint.
-func (bp *Blueprint) To() (blueprint.Blueprint, error) {
+func (bp *Blueprint) To(randomness func() float64) (blueprint.Blueprint, error) {
 	bp.prepare()
 	services := make([]model.Service, len(bp.Services))
 	for _, s := range bp.Services {
-		s, err := s.To()
+		s, err := s.To(randomness)
 		if err != nil {
 			return nil, err
 		}
 		services = append(services, *s)
 	}
-	sbp := service.NewServiceBlueprint(services)
+	variables, err := bp.Variables.To(randomness)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trace variables: %w", err)
+	}
+	sbp := service.NewServiceBlueprint(services, variables, randomness)
 	return &sbp, nil
 }
 
 // sets default values for span definitions if they are not specified.
 func (bp *Blueprint) prepare() {
-	var applyDefaults func(spanDefinitions []*SpanDefinition)
-	applyDefaults = func(spanDefinitions []*SpanDefinition) {
+	var applyDefaults func(spanDefinitions []*SpanDefinition, dataQuality *DataQuality)
+	applyDefaults = func(spanDefinitions []*SpanDefinition, dataQuality *DataQuality) {
 		for _, sd := range spanDefinitions {
 			sd.Delay = sd.Delay.WithDefault(bp.Default.Delay)
 			sd.Duration = sd.Duration.WithDefault(bp.Default.Duration)
-			childSpans := make([]*SpanDefinition, len(sd.Children))
+			if sd.ErrorPropagation == nil {
+				sd.ErrorPropagation = bp.Default.ErrorPropagation
+			}
+			sd.DataQuality = sd.DataQuality.WithDefault(dataQuality)
+			childSpans := make([]*SpanDefinition, 0, len(sd.Children))
 			for i := range sd.Children {
-				childSpans[i] = &sd.Children[i]
+				childSpans = append(childSpans, &sd.Children[i])
+			}
+			for i := range sd.Variants {
+				for j := range sd.Variants[i].Children {
+					childSpans = append(childSpans, &sd.Variants[i].Children[j])
+				}
 			}
-			applyDefaults(childSpans)
+			applyDefaults(childSpans, dataQuality)
 		}
 	}
 
@@ -99,7 +287,12 @@ func (bp *Blueprint) prepare() {
 		for j := range bp.Services[i].SpanDefinitions {
 			sds[j] = &bp.Services[i].SpanDefinitions[j]
 		}
-		applyDefaults(sds)
+		// the data quality of the service is the default for its spans
+		var dataQuality *DataQuality
+		if bp.Services[i].DataQuality != nil {
+			dataQuality = &bp.Services[i].DataQuality.DataQuality
+		}
+		applyDefaults(sds, dataQuality)
 	}
 }
 
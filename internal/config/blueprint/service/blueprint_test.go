@@ -1,9 +1,12 @@
 package service
 
 import (
+	"github.com/k4ji/tracesimulationreceiver/internal/config/global"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/attribute"
 	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
 	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task/taskduration"
 	"github.com/stretchr/testify/assert"
+	"math/rand"
 	"testing"
 	"time"
 )
@@ -182,6 +185,146 @@ func TestValidate(t *testing.T) {
 	})
 }
 
+func TestValidateVariables(t *testing.T) {
+	newBlueprint := func(variables Attributes, attributes Attributes) *Blueprint {
+		return &Blueprint{
+			Default: DefaultValues{
+				Delay:    &Delay{Value: ptrString("0ms"), Mode: ptrString("absolute")},
+				Duration: &Duration{Value: ptrString("1s"), Mode: ptrString("absolute")},
+			},
+			Services: []Service{
+				{
+					Name: "service1",
+					SpanDefinitions: []SpanDefinition{
+						{
+							Name: "span1",
+							Children: []SpanDefinition{
+								{
+									Name:       "span1-child",
+									Attributes: attributes,
+								},
+							},
+						},
+					},
+				},
+			},
+			Variables: variables,
+		}
+	}
+
+	t.Run("references to defined variables", func(t *testing.T) {
+		bp := newBlueprint(
+			Attributes{"user_id": map[string]any{"generator": "user_id", "pool_size": 10}},
+			Attributes{"enduser.id": "${trace.user_id}"},
+		)
+		assert.NoError(t, bp.Validate())
+	})
+
+	testCases := []struct {
+		name       string
+		variables  Attributes
+		attributes Attributes
+		expected   string
+	}{
+		{
+			name:       "undefined variable",
+			variables:  Attributes{"user_id": "user-1"},
+			attributes: Attributes{"tenant.id": "${trace.tenant_id}"},
+			expected:   "span span1-child references undefined variable trace.tenant_id",
+		},
+		{
+			name:      "invalid variable name",
+			variables: Attributes{"user.id": "user-1"},
+			expected:  "trace variable name user.id must match the regex ^[a-zA-Z_][a-zA-Z0-9_]*$",
+		},
+		{
+			name:      "variable referencing another variable",
+			variables: Attributes{"user_id": "user-1", "session_id": "${trace.user_id}"},
+			expected:  "trace variables cannot reference other variables",
+		},
+		{
+			name:       "unterminated reference",
+			attributes: Attributes{"enduser.id": "${trace.user_id"},
+			expected:   `span span1-child has invalid attributes: invalid value for attribute enduser.id: unterminated variable reference in "${trace.user_id"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := newBlueprint(tc.variables, tc.attributes).Validate()
+			assert.EqualError(t, err, tc.expected)
+		})
+	}
+}
+
+func TestValidateFlows(t *testing.T) {
+	newBlueprint := func(roots []SpanDefinition) *Blueprint {
+		return &Blueprint{
+			Default: DefaultValues{
+				Delay:    &Delay{Value: ptrString("0ms"), Mode: ptrString("absolute")},
+				Duration: &Duration{Value: ptrString("1s"), Mode: ptrString("absolute")},
+			},
+			Services: []Service{
+				{
+					Name:            "service1",
+					SpanDefinitions: roots,
+				},
+			},
+		}
+	}
+
+	t.Run("weights and rates on the roots of flows", func(t *testing.T) {
+		bp := newBlueprint([]SpanDefinition{
+			{Name: "read_timeline", Ref: ptrString("read_timeline"), Weight: ptrFloat64(8)},
+			{Name: "send_message"},
+			{Name: "health_check", Rate: &global.Rate{TracesPerSecond: 1}},
+			{Name: "load_timeline", Parent: ptrString("read_timeline")},
+		})
+		assert.NoError(t, bp.Validate())
+	})
+
+	testCases := []struct {
+		name     string
+		roots    []SpanDefinition
+		expected string
+	}{
+		{
+			name:     "weight on a child span",
+			roots:    []SpanDefinition{{Name: "read_timeline", Children: []SpanDefinition{{Name: "load_timeline", Weight: ptrFloat64(1)}}}},
+			expected: "span load_timeline is not the root of a flow, so it cannot have weight or rate",
+		},
+		{
+			name: "rate on a span with a parent",
+			roots: []SpanDefinition{
+				{Name: "read_timeline", Ref: ptrString("read_timeline")},
+				{Name: "load_timeline", Parent: ptrString("read_timeline"), Rate: &global.Rate{TracesPerSecond: 1}},
+			},
+			expected: "span load_timeline is not the root of a flow, so it cannot have weight or rate",
+		},
+		{
+			name:     "negative weight",
+			roots:    []SpanDefinition{{Name: "read_timeline", Weight: ptrFloat64(-1)}},
+			expected: "span read_timeline has invalid weight: must be greater than or equal to 0",
+		},
+		{
+			name:     "zero total weight",
+			roots:    []SpanDefinition{{Name: "read_timeline", Weight: ptrFloat64(0)}, {Name: "send_message", Weight: ptrFloat64(0)}},
+			expected: "weights of the flows must sum to a value greater than 0",
+		},
+		{
+			name:     "invalid rate",
+			roots:    []SpanDefinition{{Name: "health_check", Rate: &global.Rate{TracesPerSecond: 0}}},
+			expected: "span health_check has invalid rate: rate traces_per_second must be greater than 0",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.EqualError(t, newBlueprint(tc.roots).Validate(), tc.expected)
+		})
+	}
+}
+
 func TestTo(t *testing.T) {
 	t.Run("convert config to blueprint", func(t *testing.T) {
 		bp := &Blueprint{
@@ -211,7 +354,7 @@ func TestTo(t *testing.T) {
 								Mode:  ptrString("absolute"),
 							},
 							Kind: "client",
-							Attributes: map[string]string{
+							Attributes: Attributes{
 								"key1": "value1",
 							},
 							Children: []SpanDefinition{
@@ -226,7 +369,7 @@ func TestTo(t *testing.T) {
 										Mode:  ptrString("absolute"),
 									},
 									Kind: "internal",
-									Attributes: map[string]string{
+									Attributes: Attributes{
 										"key2": "value2",
 									},
 								},
@@ -276,7 +419,7 @@ func TestTo(t *testing.T) {
 					},
 				},
 			}}
-		sbp, err := bp.To()
+		sbp, err := bp.To(rand.Float64)
 		assert.NoError(t, err)
 		result, err := sbp.Interpret()
 		assert.NoError(t, err)
@@ -285,13 +428,13 @@ func TestTo(t *testing.T) {
 		// check the first span tree
 		span1 := result[0]
 		assert.Equal(t, "span1", span1.Definition().Name())
-		assert.Equal(t, "value1", span1.Definition().Attributes()["key1"])
+		assert.Equal(t, attribute.String("value1"), span1.Definition().Attributes()["key1"])
 		assert.Equal(t, task.KindClient, span1.Definition().Kind())
 		assert.Equal(t, NewDelayAsAbsoluteDuration(1*time.Millisecond), span1.Definition().Delay())
 		assert.Equal(t, NewDurationAsAbsoluteDuration(time.Duration(2)*time.Millisecond), span1.Definition().Duration())
 
 		assert.Equal(t, "span1-child", span1.Children()[0].Definition().Name())
-		assert.Equal(t, "value2", span1.Children()[0].Definition().Attributes()["key2"])
+		assert.Equal(t, attribute.String("value2"), span1.Children()[0].Definition().Attributes()["key2"])
 		assert.Equal(t, task.KindInternal, span1.Children()[0].Definition().Kind())
 		assert.Equal(t, NewDelayAsAbsoluteDuration(time.Duration(3)*time.Millisecond), span1.Children()[0].Definition().Delay())
 		assert.Equal(t, NewDurationAsAbsoluteDuration(time.Duration(4)*time.Millisecond), span1.Children()[0].Definition().Duration())
@@ -341,7 +484,7 @@ func TestTo(t *testing.T) {
 				},
 			},
 		}
-		_, err := bp.To()
+		_, err := bp.To(rand.Float64)
 		assert.Error(t, err)
 		assert.EqualError(t, err, "invalid external ref: ^invalid$")
 	})
@@ -392,7 +535,7 @@ func TestConvertConfigWithRelativeAndAbsoluteDelayModes(t *testing.T) {
 						},
 					},
 				}}
-			sbp, err := bp.To()
+			sbp, err := bp.To(rand.Float64)
 			assert.NoError(t, err)
 			result, err := sbp.Interpret()
 			assert.NoError(t, err)
@@ -471,7 +614,7 @@ func TestConvertConfigWithRelativeAndAbsoluteDelayModes(t *testing.T) {
 					},
 				},
 			}}
-		sbp, err := bp.To()
+		sbp, err := bp.To(rand.Float64)
 		assert.NoError(t, err)
 		result, err := sbp.Interpret()
 		assert.NoError(t, err)
@@ -569,7 +712,7 @@ func TestConvertConfigWithRelativeAndAbsoluteDelayModes(t *testing.T) {
 					},
 				},
 			}}
-		sbp, err := bp.To()
+		sbp, err := bp.To(rand.Float64)
 		assert.NoError(t, err)
 		result, err := sbp.Interpret()
 		assert.NoError(t, err)
@@ -660,7 +803,7 @@ func TestConvertConfigWithRelativeAndAbsoluteDelayModes(t *testing.T) {
 					},
 				},
 			}}
-		sbp, err := bp.To()
+		sbp, err := bp.To(rand.Float64)
 		assert.NoError(t, err)
 		result, err := sbp.Interpret()
 		assert.NoError(t, err)
@@ -742,7 +885,7 @@ func TestConvertConfigWithRelativeAndAbsoluteDelayModes(t *testing.T) {
 					},
 				},
 			}}
-		sbp, err := bp.To()
+		sbp, err := bp.To(rand.Float64)
 		assert.NoError(t, err)
 		result, err := sbp.Interpret()
 		assert.NoError(t, err)
@@ -861,7 +1004,7 @@ func TestConvertConfigWithRelativeAndAbsoluteDelayModes(t *testing.T) {
 
 		for _, tc := range testCases {
 			t.Run(tc.name, func(t *testing.T) {
-				_, err := tc.blueprint.To()
+				_, err := tc.blueprint.To(rand.Float64)
 				assert.Error(t, err)
 				assert.ErrorContains(t, err, tc.errorMsg)
 			})
@@ -914,7 +1057,7 @@ func TestConvertConfigWithRelativeAndAbsoluteDurationModes(t *testing.T) {
 						},
 					},
 				}}
-			sbp, err := bp.To()
+			sbp, err := bp.To(rand.Float64)
 			assert.NoError(t, err)
 			result, err := sbp.Interpret()
 			assert.NoError(t, err)
@@ -993,7 +1136,7 @@ func TestConvertConfigWithRelativeAndAbsoluteDurationModes(t *testing.T) {
 					},
 				},
 			}}
-		sbp, err := bp.To()
+		sbp, err := bp.To(rand.Float64)
 		assert.NoError(t, err)
 		result, err := sbp.Interpret()
 		assert.NoError(t, err)
@@ -1091,7 +1234,7 @@ func TestConvertConfigWithRelativeAndAbsoluteDurationModes(t *testing.T) {
 					},
 				},
 			}}
-		sbp, err := bp.To()
+		sbp, err := bp.To(rand.Float64)
 		assert.NoError(t, err)
 		result, err := sbp.Interpret()
 		assert.NoError(t, err)
@@ -1182,7 +1325,7 @@ func TestConvertConfigWithRelativeAndAbsoluteDurationModes(t *testing.T) {
 					},
 				},
 			}}
-		sbp, err := bp.To()
+		sbp, err := bp.To(rand.Float64)
 		assert.NoError(t, err)
 		result, err := sbp.Interpret()
 		assert.NoError(t, err)
@@ -1264,7 +1407,7 @@ func TestConvertConfigWithRelativeAndAbsoluteDurationModes(t *testing.T) {
 					},
 				},
 			}}
-		sbp, err := bp.To()
+		sbp, err := bp.To(rand.Float64)
 		assert.NoError(t, err)
 		result, err := sbp.Interpret()
 		assert.NoError(t, err)
@@ -1399,7 +1542,7 @@ func TestConvertConfigWithRelativeAndAbsoluteDurationModes(t *testing.T) {
 
 		for _, tc := range testCases {
 			t.Run(tc.name, func(t *testing.T) {
-				_, err := tc.blueprint.To()
+				_, err := tc.blueprint.To(rand.Float64)
 				assert.Error(t, err)
 				assert.ErrorContains(t, err, tc.errorMsg)
 			})
@@ -1407,6 +1550,220 @@ func TestConvertConfigWithRelativeAndAbsoluteDurationModes(t *testing.T) {
 	})
 }
 
+func TestConvertConfigWithDistributionModes(t *testing.T) {
+	t.Run("sample durations and delays from distributions", func(t *testing.T) {
+		bp := &Blueprint{
+			Services: []Service{
+				{
+					Name: "service",
+					SpanDefinitions: []SpanDefinition{
+						{
+							Name: "root",
+							Delay: &Delay{
+								Mode: ptrString("uniform"),
+								Distributions: Distributions{
+									Uniform: &UniformDistribution{Min: time.Millisecond, Max: 2 * time.Millisecond},
+								},
+							},
+							Duration: &Duration{
+								Mode: ptrString("empirical"),
+								Distributions: Distributions{
+									Empirical: &EmpiricalDistribution{
+										Percentiles: []Percentile{
+											{Percentile: 0, Value: 10 * time.Millisecond},
+											{Percentile: 100, Value: 20 * time.Millisecond},
+										},
+									},
+								},
+							},
+							Kind: "internal",
+							Children: []SpanDefinition{
+								{
+									Name: "normal",
+									Delay: &Delay{
+										Mode: ptrString("exponential"),
+										Distributions: Distributions{
+											Exponential: &ExponentialDistribution{Mean: time.Millisecond},
+										},
+									},
+									Duration: &Duration{
+										Mode: ptrString("normal"),
+										Distributions: Distributions{
+											Normal: &NormalDistribution{Mean: 5 * time.Millisecond, StdDev: time.Millisecond},
+										},
+									},
+									Kind: "internal",
+								},
+								{
+									Name: "lognormal",
+									Delay: &Delay{
+										Value: ptrString("0"),
+										Mode:  ptrString("absolute"),
+									},
+									Duration: &Duration{
+										Mode: ptrString("lognormal"),
+										Distributions: Distributions{
+											LogNormal: &LogNormalDistribution{Median: 5 * time.Millisecond, Sigma: 0.5},
+										},
+									},
+									Kind: "internal",
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		assert.NoError(t, bp.Validate())
+		sbp, err := bp.To(rand.Float64)
+		assert.NoError(t, err)
+		result, err := sbp.Interpret()
+		assert.NoError(t, err)
+		assert.Len(t, result, 1)
+
+		root := result[0]
+		for i := 0; i < 100; i++ {
+			delay, err := root.Definition().Delay().Resolve(nil)
+			assert.NoError(t, err)
+			assert.GreaterOrEqual(t, *delay, time.Millisecond)
+			assert.LessOrEqual(t, *delay, 2*time.Millisecond)
+			duration, err := root.Definition().Duration().Resolve(nil)
+			assert.NoError(t, err)
+			assert.GreaterOrEqual(t, *duration, 10*time.Millisecond)
+			assert.LessOrEqual(t, *duration, 20*time.Millisecond)
+			for _, child := range root.Children() {
+				delay, err := child.Definition().Delay().Resolve(duration)
+				assert.NoError(t, err)
+				assert.GreaterOrEqual(t, *delay, time.Duration(0))
+				duration, err := child.Definition().Duration().Resolve(duration)
+				assert.NoError(t, err)
+				assert.Greater(t, *duration, time.Duration(0))
+			}
+		}
+	})
+
+	t.Run("inherit distribution parameters from default", func(t *testing.T) {
+		bp := &Blueprint{
+			Default: DefaultValues{
+				Delay: &Delay{
+					Value: ptrString("0"),
+					Mode:  ptrString("absolute"),
+				},
+				Duration: &Duration{
+					Mode: ptrString("uniform"),
+					Distributions: Distributions{
+						Uniform: &UniformDistribution{Min: time.Millisecond, Max: time.Millisecond},
+					},
+				},
+			},
+			Services: []Service{
+				{
+					Name: "service",
+					SpanDefinitions: []SpanDefinition{
+						{
+							Name: "root",
+							Kind: "internal",
+						},
+					},
+				},
+			},
+		}
+		assert.NoError(t, bp.Validate())
+		sbp, err := bp.To(rand.Float64)
+		assert.NoError(t, err)
+		result, err := sbp.Interpret()
+		assert.NoError(t, err)
+		duration, err := result[0].Definition().Duration().Resolve(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Millisecond, *duration)
+	})
+
+	t.Run("clamp normal delays at zero", func(t *testing.T) {
+		delay := Delay{
+			Mode:          ptrString("normal"),
+			Distributions: Distributions{Normal: &NormalDistribution{Mean: 0, StdDev: time.Second}},
+		}
+		assert.NoError(t, delay.ValidateAfterDefaults())
+		// 0.5 for both uniform values gives a negative sample of the standard normal distribution
+		d, err := delay.To(func() float64 { return 0.5 })
+		assert.NoError(t, err)
+		resolved, err := d.Resolve(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Duration(0), *resolved)
+	})
+
+	t.Run("invalid distribution parameters", func(t *testing.T) {
+		testCases := []struct {
+			name     string
+			duration Duration
+			errorMsg string
+		}{
+			{
+				name:     "missing parameters",
+				duration: Duration{Mode: ptrString("normal")},
+				errorMsg: "missing required field: duration.normal",
+			},
+			{
+				name: "zero mean of normal duration",
+				duration: Duration{
+					Mode:          ptrString("normal"),
+					Distributions: Distributions{Normal: &NormalDistribution{Mean: 0, StdDev: time.Millisecond}},
+				},
+				errorMsg: "normal duration mean must be greater than 0",
+			},
+			{
+				name: "mean of normal duration too small for its stddev",
+				duration: Duration{
+					Mode:          ptrString("normal"),
+					Distributions: Distributions{Normal: &NormalDistribution{Mean: time.Millisecond, StdDev: time.Second}},
+				},
+				errorMsg: "normal duration mean is too small for its stddev, which makes non-positive samples too likely",
+			},
+			{
+				name: "negative sigma of lognormal duration",
+				duration: Duration{
+					Mode:          ptrString("lognormal"),
+					Distributions: Distributions{LogNormal: &LogNormalDistribution{Median: time.Millisecond, Sigma: -1}},
+				},
+				errorMsg: "lognormal duration sigma must be non-negative",
+			},
+			{
+				name: "zero mean of exponential duration",
+				duration: Duration{
+					Mode:          ptrString("exponential"),
+					Distributions: Distributions{Exponential: &ExponentialDistribution{}},
+				},
+				errorMsg: "exponential duration mean must be greater than 0",
+			},
+			{
+				name: "inverted bounds of uniform duration",
+				duration: Duration{
+					Mode:          ptrString("uniform"),
+					Distributions: Distributions{Uniform: &UniformDistribution{Min: 2 * time.Millisecond, Max: time.Millisecond}},
+				},
+				errorMsg: "uniform duration max must be greater than or equal to min",
+			},
+			{
+				name: "unordered percentiles of empirical duration",
+				duration: Duration{
+					Mode: ptrString("empirical"),
+					Distributions: Distributions{Empirical: &EmpiricalDistribution{Percentiles: []Percentile{
+						{Percentile: 90, Value: time.Millisecond},
+						{Percentile: 50, Value: 2 * time.Millisecond},
+					}}},
+				},
+				errorMsg: "empirical duration percentiles must be in strictly increasing order",
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				assert.EqualError(t, tc.duration.ValidateAfterDefaults(), tc.errorMsg)
+			})
+		}
+	})
+}
+
 func ptrString(s string) *string {
 	return &s
 }
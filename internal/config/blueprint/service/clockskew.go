@@ -0,0 +1,40 @@
+package service
+
+import (
+	"fmt"
+	domaintask "github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
+	"time"
+)
+
+// ClockSkew represents how far the clock of a service is off from the true time.
+type ClockSkew struct {
+	// Offset is the fixed offset of the clock. Negative values put the clock behind.
+	Offset *time.Duration `mapstructure:"offset"`
+
+	// Min is the minimum offset when the offset is drawn at random for each trace.
+	Min *time.Duration `mapstructure:"min"`
+
+	// Max is the maximum offset when the offset is drawn at random for each trace.
+	Max *time.Duration `mapstructure:"max"`
+}
+
+// To converts the clock skew to a domain model.
+func (c *ClockSkew) To(randomness func() float64) (domaintask.ClockSkew, error) {
+	if c == nil {
+		return domaintask.ClockSkew{}, nil
+	}
+	var minimum, maximum time.Duration
+	switch {
+	case c.Offset != nil && c.Min == nil && c.Max == nil:
+		minimum, maximum = *c.Offset, *c.Offset
+	case c.Offset == nil && c.Min != nil && c.Max != nil:
+		minimum, maximum = *c.Min, *c.Max
+	default:
+		return domaintask.ClockSkew{}, fmt.Errorf("clock skew must have either offset or both min and max")
+	}
+	clockSkew, err := domaintask.NewClockSkew(minimum, maximum, randomness)
+	if err != nil {
+		return domaintask.ClockSkew{}, err
+	}
+	return *clockSkew, nil
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestClockSkew_To(t *testing.T) {
+	duration := func(d time.Duration) *time.Duration {
+		return &d
+	}
+	testCases := []struct {
+		name          string
+		clockSkew     *ClockSkew
+		expectedMin   time.Duration
+		expectedMax   time.Duration
+		expectedError string
+	}{
+		{
+			name:      "no clock skew",
+			clockSkew: nil,
+		},
+		{
+			name:        "fixed offset",
+			clockSkew:   &ClockSkew{Offset: duration(-200 * time.Millisecond)},
+			expectedMin: -200 * time.Millisecond,
+			expectedMax: -200 * time.Millisecond,
+		},
+		{
+			name:        "random drift range",
+			clockSkew:   &ClockSkew{Min: duration(-time.Second), Max: duration(time.Second)},
+			expectedMin: -time.Second,
+			expectedMax: time.Second,
+		},
+		{
+			name:          "offset with range",
+			clockSkew:     &ClockSkew{Offset: duration(time.Second), Min: duration(0), Max: duration(time.Second)},
+			expectedError: "clock skew must have either offset or both min and max",
+		},
+		{
+			name:          "missing max",
+			clockSkew:     &ClockSkew{Min: duration(0)},
+			expectedError: "clock skew must have either offset or both min and max",
+		},
+		{
+			name:          "min greater than max",
+			clockSkew:     &ClockSkew{Min: duration(time.Second), Max: duration(0)},
+			expectedError: "clock skew min must be less than or equal to max",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			clockSkew, err := tc.clockSkew.To(func() float64 { return 0.5 })
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedMin, clockSkew.Min())
+			assert.Equal(t, tc.expectedMax, clockSkew.Max())
+			assert.Equal(t, tc.expectedMin+(tc.expectedMax-tc.expectedMin)/2, clockSkew.Resolve())
+		})
+	}
+}
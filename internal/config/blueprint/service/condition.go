@@ -3,8 +3,7 @@ package service
 import (
 	"fmt"
 	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
-	"math/rand"
-	"time"
+	"regexp"
 )
 
 // Condition represents a condition that determines whether an effect should be applied.
@@ -13,6 +12,24 @@ type Condition struct {
 	Kind string `mapstructure:"kind"`
 	// Probabilistic is the probability of the condition being met.
 	Probabilistic *Probabilistic `mapstructure:"probabilistic"`
+	// AtLeast is the condition that requires at least a certain number of nodes to meet the inner condition.
+	AtLeast *AtLeast `mapstructure:"at_least"`
+	// Child is the condition that evaluates the inner condition against each child.
+	Child *Child `mapstructure:"child"`
+	// HasAttribute is the condition that checks if an attribute is present.
+	HasAttribute *HasAttribute `mapstructure:"has_attribute"`
+	// AttributeEquals is the condition that checks if an attribute equals a value.
+	AttributeEquals *AttributeEquals `mapstructure:"attribute_equals"`
+	// AttributeMatches is the condition that checks if an attribute matches a regular expression.
+	AttributeMatches *AttributeMatches `mapstructure:"attribute_matches"`
+	// AttributeCompare is the condition that compares a numeric attribute with a value.
+	AttributeCompare *AttributeCompare `mapstructure:"attribute_compare"`
+	// AllOf is the condition that requires all inner conditions to be met.
+	AllOf *AllOf `mapstructure:"all_of"`
+	// AnyOf is the condition that requires at least one of the inner conditions to be met.
+	AnyOf *AnyOf `mapstructure:"any_of"`
+	// Not is the condition that negates the inner condition.
+	Not *Not `mapstructure:"not"`
 }
 
 // Probabilistic represents a probabilistic condition.
@@ -21,8 +38,106 @@ type Probabilistic struct {
 	Threshold float64 `mapstructure:"threshold"`
 }
 
+// AtLeast represents a condition that requires at least a certain number of nodes to meet the inner condition.
+type AtLeast struct {
+	// Threshold is the minimum number of nodes that must meet the inner condition.
+	Threshold int `mapstructure:"threshold"`
+	// Inner is the condition evaluated against the nodes.
+	Inner Condition `mapstructure:"inner"`
+}
+
+// Child represents a condition that evaluates the inner condition against each child.
+// Since it yields one result per child, it must be wrapped by an aggregating condition such as at_least.
+type Child struct {
+	// Inner is the condition evaluated against each child.
+	Inner Condition `mapstructure:"inner"`
+}
+
+// HasAttribute represents a condition that checks if an attribute is present.
+type HasAttribute struct {
+	// Key is the key of the attribute.
+	Key string `mapstructure:"key"`
+}
+
+// AttributeEquals represents a condition that checks if an attribute equals a value.
+type AttributeEquals struct {
+	// Key is the key of the attribute.
+	Key string `mapstructure:"key"`
+	// Value is the value the attribute must be equal to.
+	Value string `mapstructure:"value"`
+}
+
+// AttributeMatches represents a condition that checks if an attribute matches a regular expression.
+type AttributeMatches struct {
+	// Key is the key of the attribute.
+	Key string `mapstructure:"key"`
+	// Pattern is the regular expression the attribute must match.
+	Pattern string `mapstructure:"pattern"`
+}
+
+// AttributeCompare represents a condition that compares a numeric attribute with a value.
+type AttributeCompare struct {
+	// Key is the key of the attribute.
+	Key string `mapstructure:"key"`
+	// Operator is the comparison operator (gt, gte, lt or lte).
+	Operator string `mapstructure:"operator"`
+	// Value is the value the attribute is compared with.
+	Value float64 `mapstructure:"value"`
+}
+
+// AllOf represents a condition that requires all inner conditions to be met.
+type AllOf struct {
+	// Conditions is the list of conditions that must all be met.
+	Conditions []Condition `mapstructure:"conditions"`
+}
+
+// AnyOf represents a condition that requires at least one of the inner conditions to be met.
+type AnyOf struct {
+	// Conditions is the list of conditions of which at least one must be met.
+	Conditions []Condition `mapstructure:"conditions"`
+}
+
+// Not represents a condition that negates the inner condition.
+type Not struct {
+	// Inner is the condition to be negated.
+	Inner Condition `mapstructure:"inner"`
+}
+
 // To converts the condition to a domain model.
-func (c *Condition) To() (*task.Condition, error) {
+func (c *Condition) To(randomness func() float64) (*task.Condition, error) {
+	if c.yieldsResultPerChild() {
+		return nil, fmt.Errorf("%s condition must be wrapped by an at_least condition", c.Kind)
+	}
+	return c.to(randomness)
+}
+
+// yieldsResultPerChild returns true if the condition yields one result per child, which must be aggregated by at_least.
+func (c *Condition) yieldsResultPerChild() bool {
+	switch c.Kind {
+	case "child":
+		return true
+	case "not":
+		return c.Not != nil && c.Not.Inner.yieldsResultPerChild()
+	case "all_of":
+		return c.AllOf != nil && anyYieldsResultPerChild(c.AllOf.Conditions)
+	case "any_of":
+		return c.AnyOf != nil && anyYieldsResultPerChild(c.AnyOf.Conditions)
+	default:
+		return false
+	}
+}
+
+func anyYieldsResultPerChild(conditions []Condition) bool {
+	for _, c := range conditions {
+		if c.yieldsResultPerChild() {
+			return true
+		}
+	}
+	return false
+}
+
+// to converts the condition to a domain model without checking if the result needs to be aggregated.
+func (c *Condition) to(randomness func() float64) (*task.Condition, error) {
 	switch c.Kind {
 	case "probabilistic":
 		if c.Probabilistic == nil {
@@ -31,7 +146,112 @@ func (c *Condition) To() (*task.Condition, error) {
 		if c.Probabilistic.Threshold < 0 || c.Probabilistic.Threshold > 1 {
 			return nil, fmt.Errorf("probabilistic condition threshold must be between 0 and 1")
 		}
-		condition := task.NewProbabilisticCondition(c.Probabilistic.Threshold, rand.New(rand.NewSource(time.Now().UnixNano())).Float64)
+		condition := task.NewProbabilisticCondition(c.Probabilistic.Threshold, randomness)
+		return &condition, nil
+	case "at_least":
+		if c.AtLeast == nil {
+			return nil, fmt.Errorf("at_least condition requires at_least configuration")
+		}
+		if c.AtLeast.Threshold < 1 {
+			return nil, fmt.Errorf("at_least condition threshold must be greater than 0")
+		}
+		inner, err := c.AtLeast.Inner.to(randomness)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert inner condition of at_least: %w", err)
+		}
+		condition := task.NewAtLeastCondition(c.AtLeast.Threshold, *inner)
+		return &condition, nil
+	case "child":
+		if c.Child == nil {
+			return nil, fmt.Errorf("child condition requires child configuration")
+		}
+		inner, err := c.Child.Inner.to(randomness)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert inner condition of child: %w", err)
+		}
+		condition := task.NewChildCondition(*inner)
+		return &condition, nil
+	case "has_attribute":
+		if c.HasAttribute == nil {
+			return nil, fmt.Errorf("has_attribute condition requires has_attribute configuration")
+		}
+		if c.HasAttribute.Key == "" {
+			return nil, fmt.Errorf("has_attribute condition requires a key")
+		}
+		condition := task.NewHasAttributeCondition(c.HasAttribute.Key)
+		return &condition, nil
+	case "attribute_equals":
+		if c.AttributeEquals == nil {
+			return nil, fmt.Errorf("attribute_equals condition requires attribute_equals configuration")
+		}
+		if c.AttributeEquals.Key == "" {
+			return nil, fmt.Errorf("attribute_equals condition requires a key")
+		}
+		condition := task.NewAttributeEqualsCondition(c.AttributeEquals.Key, c.AttributeEquals.Value)
+		return &condition, nil
+	case "attribute_matches":
+		if c.AttributeMatches == nil {
+			return nil, fmt.Errorf("attribute_matches condition requires attribute_matches configuration")
+		}
+		if c.AttributeMatches.Key == "" {
+			return nil, fmt.Errorf("attribute_matches condition requires a key")
+		}
+		pattern, err := regexp.Compile(c.AttributeMatches.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("attribute_matches condition has an invalid pattern: %w", err)
+		}
+		condition := task.NewAttributeMatchesCondition(c.AttributeMatches.Key, pattern)
+		return &condition, nil
+	case "attribute_compare":
+		if c.AttributeCompare == nil {
+			return nil, fmt.Errorf("attribute_compare condition requires attribute_compare configuration")
+		}
+		if c.AttributeCompare.Key == "" {
+			return nil, fmt.Errorf("attribute_compare condition requires a key")
+		}
+		operator := task.ComparisonOperator(c.AttributeCompare.Operator)
+		switch operator {
+		case task.ComparisonOperatorGreaterThan,
+			task.ComparisonOperatorGreaterThanOrEqual,
+			task.ComparisonOperatorLessThan,
+			task.ComparisonOperatorLessThanOrEqual:
+		default:
+			return nil, fmt.Errorf("attribute_compare condition has an unsupported operator: %s", c.AttributeCompare.Operator)
+		}
+		condition := task.NewAttributeCompareCondition(c.AttributeCompare.Key, operator, c.AttributeCompare.Value)
+		return &condition, nil
+	case "marked_as_failed":
+		condition := task.NewMarkedAsFailedCondition()
+		return &condition, nil
+	case "all_of":
+		if c.AllOf == nil || len(c.AllOf.Conditions) == 0 {
+			return nil, fmt.Errorf("all_of condition requires at least one condition")
+		}
+		conditions, err := toConditions(c.AllOf.Conditions, randomness)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert inner condition of all_of: %w", err)
+		}
+		condition := task.NewAllOfCondition(conditions)
+		return &condition, nil
+	case "any_of":
+		if c.AnyOf == nil || len(c.AnyOf.Conditions) == 0 {
+			return nil, fmt.Errorf("any_of condition requires at least one condition")
+		}
+		conditions, err := toConditions(c.AnyOf.Conditions, randomness)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert inner condition of any_of: %w", err)
+		}
+		condition := task.NewAnyOfCondition(conditions)
+		return &condition, nil
+	case "not":
+		if c.Not == nil {
+			return nil, fmt.Errorf("not condition requires not configuration")
+		}
+		inner, err := c.Not.Inner.to(randomness)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert inner condition of not: %w", err)
+		}
+		condition := task.NewNotCondition(*inner)
 		return &condition, nil
 	case "child_marked_as_failed":
 		condition := task.NewAtLeastCondition(1, task.NewChildCondition(task.NewMarkedAsFailedCondition()))
@@ -40,3 +260,15 @@ func (c *Condition) To() (*task.Condition, error) {
 		return nil, fmt.Errorf("unknown condition type: %s", c.Kind)
 	}
 }
+
+func toConditions(cs []Condition, randomness func() float64) ([]task.Condition, error) {
+	conditions := make([]task.Condition, 0, len(cs))
+	for _, c := range cs {
+		condition, err := c.to(randomness)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, *condition)
+	}
+	return conditions, nil
+}
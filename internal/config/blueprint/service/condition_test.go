@@ -0,0 +1,269 @@
+package service
+
+import (
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/span"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestCondition_To(t *testing.T) {
+	t.Run("convert nested conditions", func(t *testing.T) {
+		c := Condition{
+			Kind: "at_least",
+			AtLeast: &AtLeast{
+				Threshold: 2,
+				Inner: Condition{
+					Kind: "child",
+					Child: &Child{
+						Inner: Condition{
+							Kind:         "has_attribute",
+							HasAttribute: &HasAttribute{Key: "db.error"},
+						},
+					},
+				},
+			},
+		}
+		condition, err := c.To(rand.Float64)
+		assert.NoError(t, err)
+		assert.Equal(t, task.ConditionKindAtLeast, condition.Kind())
+		assert.Equal(t, 2, condition.AtLeast().Threshold())
+		child := condition.AtLeast().Inner()
+		assert.Equal(t, task.ConditionKindChild, child.Kind())
+		hasAttribute := child.Child().Inner()
+		assert.Equal(t, task.ConditionKindHasAttribute, hasAttribute.Kind())
+		assert.Equal(t, "db.error", hasAttribute.HasAttribute().Key())
+	})
+
+	t.Run("convert marked_as_failed condition", func(t *testing.T) {
+		c := Condition{Kind: "marked_as_failed"}
+		condition, err := c.To(rand.Float64)
+		assert.NoError(t, err)
+		assert.Equal(t, task.ConditionKindMarkedAsFailed, condition.Kind())
+	})
+
+	t.Run("convert combinators", func(t *testing.T) {
+		c := Condition{
+			Kind: "all_of",
+			AllOf: &AllOf{
+				Conditions: []Condition{
+					{Kind: "probabilistic", Probabilistic: &Probabilistic{Threshold: 0.2}},
+					{Kind: "not", Not: &Not{Inner: Condition{Kind: "marked_as_failed"}}},
+					{Kind: "any_of", AnyOf: &AnyOf{Conditions: []Condition{{Kind: "child_marked_as_failed"}}}},
+				},
+			},
+		}
+		condition, err := c.To(rand.Float64)
+		assert.NoError(t, err)
+		assert.Equal(t, task.ConditionKindAllOf, condition.Kind())
+		inner := condition.AllOf().Conditions()
+		assert.Len(t, inner, 3)
+		assert.Equal(t, task.ConditionKindProbabilistic, inner[0].Kind())
+		assert.Equal(t, task.ConditionKindNot, inner[1].Kind())
+		assert.Equal(t, task.ConditionKindMarkedAsFailed, inner[1].Not().Inner().Kind())
+		assert.Equal(t, task.ConditionKindAnyOf, inner[2].Kind())
+		assert.Equal(t, task.ConditionKindAtLeast, inner[2].AnyOf().Conditions()[0].Kind())
+	})
+
+	t.Run("convert combinators of child conditions wrapped by at_least", func(t *testing.T) {
+		c := Condition{
+			Kind: "at_least",
+			AtLeast: &AtLeast{
+				Threshold: 1,
+				Inner: Condition{
+					Kind: "all_of",
+					AllOf: &AllOf{
+						Conditions: []Condition{
+							{Kind: "child", Child: &Child{Inner: Condition{Kind: "marked_as_failed"}}},
+							{Kind: "not", Not: &Not{Inner: Condition{Kind: "child", Child: &Child{Inner: Condition{Kind: "has_attribute", HasAttribute: &HasAttribute{Key: "retry"}}}}}},
+						},
+					},
+				},
+			},
+		}
+		_, err := c.To(rand.Float64)
+		assert.NoError(t, err)
+	})
+
+	t.Run("convert attribute value conditions", func(t *testing.T) {
+		c := Condition{Kind: "attribute_equals", AttributeEquals: &AttributeEquals{Key: "http.request.method", Value: "POST"}}
+		condition, err := c.To(rand.Float64)
+		assert.NoError(t, err)
+		assert.Equal(t, task.ConditionKindAttributeEquals, condition.Kind())
+		assert.Equal(t, "http.request.method", condition.AttributeEquals().Key())
+		assert.Equal(t, "POST", condition.AttributeEquals().Value())
+
+		c = Condition{Kind: "attribute_matches", AttributeMatches: &AttributeMatches{Key: "url.path", Pattern: "^/api/v1/"}}
+		condition, err = c.To(rand.Float64)
+		assert.NoError(t, err)
+		assert.Equal(t, task.ConditionKindAttributeMatches, condition.Kind())
+		assert.True(t, condition.AttributeMatches().Pattern().MatchString("/api/v1/messages"))
+
+		c = Condition{Kind: "attribute_compare", AttributeCompare: &AttributeCompare{Key: "http.response.status_code", Operator: "gte", Value: 500}}
+		condition, err = c.To(rand.Float64)
+		assert.NoError(t, err)
+		assert.Equal(t, task.ConditionKindAttributeCompare, condition.Kind())
+		assert.Equal(t, task.ComparisonOperatorGreaterThanOrEqual, condition.AttributeCompare().Operator())
+		assert.Equal(t, 500.0, condition.AttributeCompare().Value())
+	})
+
+	t.Run("invalid conditions", func(t *testing.T) {
+		testCases := []struct {
+			name      string
+			condition Condition
+			errorMsg  string
+		}{
+			{
+				name: "child condition without aggregation",
+				condition: Condition{
+					Kind:  "child",
+					Child: &Child{Inner: Condition{Kind: "marked_as_failed"}},
+				},
+				errorMsg: "child condition must be wrapped by an at_least condition",
+			},
+			{
+				name: "negated child condition without aggregation",
+				condition: Condition{
+					Kind: "not",
+					Not:  &Not{Inner: Condition{Kind: "child", Child: &Child{Inner: Condition{Kind: "marked_as_failed"}}}},
+				},
+				errorMsg: "not condition must be wrapped by an at_least condition",
+			},
+			{
+				name: "combined child condition without aggregation",
+				condition: Condition{
+					Kind: "any_of",
+					AnyOf: &AnyOf{Conditions: []Condition{
+						{Kind: "marked_as_failed"},
+						{Kind: "child", Child: &Child{Inner: Condition{Kind: "marked_as_failed"}}},
+					}},
+				},
+				errorMsg: "any_of condition must be wrapped by an at_least condition",
+			},
+			{
+				name:      "empty all_of condition",
+				condition: Condition{Kind: "all_of", AllOf: &AllOf{}},
+				errorMsg:  "all_of condition requires at least one condition",
+			},
+			{
+				name:      "missing at_least configuration",
+				condition: Condition{Kind: "at_least"},
+				errorMsg:  "at_least condition requires at_least configuration",
+			},
+			{
+				name: "non-positive threshold",
+				condition: Condition{
+					Kind:    "at_least",
+					AtLeast: &AtLeast{Threshold: 0, Inner: Condition{Kind: "marked_as_failed"}},
+				},
+				errorMsg: "at_least condition threshold must be greater than 0",
+			},
+			{
+				name: "invalid inner condition",
+				condition: Condition{
+					Kind:    "at_least",
+					AtLeast: &AtLeast{Threshold: 1, Inner: Condition{Kind: "unknown"}},
+				},
+				errorMsg: "failed to convert inner condition of at_least: unknown condition type: unknown",
+			},
+			{
+				name:      "invalid attribute_matches pattern",
+				condition: Condition{Kind: "attribute_matches", AttributeMatches: &AttributeMatches{Key: "url.path", Pattern: "("}},
+				errorMsg:  "attribute_matches condition has an invalid pattern: error parsing regexp: missing closing ): `(`",
+			},
+			{
+				name:      "unsupported attribute_compare operator",
+				condition: Condition{Kind: "attribute_compare", AttributeCompare: &AttributeCompare{Key: "http.response.status_code", Operator: "eq"}},
+				errorMsg:  "attribute_compare condition has an unsupported operator: eq",
+			},
+			{
+				name:      "missing has_attribute key",
+				condition: Condition{Kind: "has_attribute", HasAttribute: &HasAttribute{}},
+				errorMsg:  "has_attribute condition requires a key",
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				_, err := tc.condition.To(rand.Float64)
+				assert.EqualError(t, err, tc.errorMsg)
+			})
+		}
+	})
+
+	t.Run("apply effects when at least 2 children have an attribute", func(t *testing.T) {
+		child := func(name string, attributes Attributes) SpanDefinition {
+			return SpanDefinition{
+				Name:       name,
+				Kind:       "client",
+				Attributes: attributes,
+			}
+		}
+		newBlueprint := func(children []SpanDefinition) *Blueprint {
+			return &Blueprint{
+				Default: DefaultValues{
+					Delay:    &Delay{Value: ptrString("0"), Mode: ptrString("absolute")},
+					Duration: &Duration{Value: ptrString("1s"), Mode: ptrString("absolute")},
+				},
+				Services: []Service{
+					{
+						Name: "service",
+						SpanDefinitions: []SpanDefinition{
+							{
+								Name:     "root",
+								Kind:     "server",
+								Children: children,
+								ConditionalEffects: []ConditionalEffect{
+									{
+										Condition: Condition{
+											Kind: "at_least",
+											AtLeast: &AtLeast{
+												Threshold: 2,
+												Inner: Condition{
+													Kind: "child",
+													Child: &Child{
+														Inner: Condition{
+															Kind:         "has_attribute",
+															HasAttribute: &HasAttribute{Key: "db.error"},
+														},
+													},
+												},
+											},
+										},
+										Effects: []Effect{
+											{Kind: "mark_as_failed", MarkAsFailed: MarkAsFailed{Message: "db errors"}},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+		}
+		toSpan := func(bp *Blueprint) *span.TreeNode {
+			sbp, err := bp.To(rand.Float64)
+			assert.NoError(t, err)
+			result, err := sbp.Interpret()
+			assert.NoError(t, err)
+			root, err := span.FromTaskTree(result[0], span.NewTraceID([16]byte{0x01}), time.Now(), func() span.ID { return span.NewSpanID([8]byte{0x01}) }, nil)
+			assert.NoError(t, err)
+			return root
+		}
+
+		failed := toSpan(newBlueprint([]SpanDefinition{
+			child("query-1", Attributes{"db.error": "timeout"}),
+			child("query-2", Attributes{}),
+			child("query-3", Attributes{"db.error": "timeout"}),
+		}))
+		assert.Equal(t, span.StatusError("db errors"), failed.Status())
+
+		succeeded := toSpan(newBlueprint([]SpanDefinition{
+			child("query-1", Attributes{"db.error": "timeout"}),
+			child("query-2", Attributes{}),
+		}))
+		assert.Equal(t, span.StatusOK, succeeded.Status())
+	})
+}
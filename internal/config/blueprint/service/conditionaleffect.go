@@ -14,15 +14,15 @@ type ConditionalEffect struct {
 }
 
 // To creates a new ConditionalEffect with the given kind and attributes.
-func (c *ConditionalEffect) To() (*task.ConditionalDefinition, error) {
-	condition, err := c.Condition.To()
+func (c *ConditionalEffect) To(randomness func() float64) (*task.ConditionalDefinition, error) {
+	condition, err := c.Condition.To(randomness)
 	if err != nil {
 		return nil, fmt.Errorf("failed to convert condition: %w", err)
 	}
 
 	var effects []task.Effect
 	for _, effect := range c.Effects {
-		e, err := effect.To()
+		e, err := effect.To(randomness)
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert effect: %w", err)
 		}
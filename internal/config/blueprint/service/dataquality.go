@@ -0,0 +1,72 @@
+package service
+
+import (
+	"fmt"
+	"github.com/k4ji/tracesimulationreceiver/internal/config/utils"
+	domaintask "github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
+)
+
+// DataQuality represents the flaws of the data a span is delivered with, as probabilities per span instance.
+type DataQuality struct {
+	// DropProbability is the probability of the span being dropped while its children are kept as orphans.
+	DropProbability *float64 `mapstructure:"drop_probability"`
+
+	// DuplicateProbability is the probability of the span being delivered twice.
+	DuplicateProbability *float64 `mapstructure:"duplicate_probability"`
+
+	// BogusParentProbability is the probability of the span pointing at a parent span ID that does not exist.
+	BogusParentProbability *float64 `mapstructure:"bogus_parent_probability"`
+}
+
+// ServiceDataQuality represents the flaws of the data a service delivers.
+// The per-span probabilities are the defaults for the spans of the service.
+type ServiceDataQuality struct {
+	DataQuality `mapstructure:",squash"`
+
+	// LossProbability is the probability of all the spans of the service in a trace being lost.
+	LossProbability float64 `mapstructure:"loss_probability"`
+}
+
+// WithDefault returns a new DataQuality with default values applied.
+func (q *DataQuality) WithDefault(dq *DataQuality) *DataQuality {
+	if q == nil {
+		return dq
+	}
+	if dq == nil {
+		return q
+	}
+	return &DataQuality{
+		DropProbability:        utils.Coalesce(q.DropProbability, dq.DropProbability),
+		DuplicateProbability:   utils.Coalesce(q.DuplicateProbability, dq.DuplicateProbability),
+		BogusParentProbability: utils.Coalesce(q.BogusParentProbability, dq.BogusParentProbability),
+	}
+}
+
+// To converts the data quality to a domain model.
+func (q *DataQuality) To() (domaintask.DataQuality, error) {
+	if q == nil {
+		return domaintask.DataQuality{}, nil
+	}
+	valueOf := func(p *float64) float64 {
+		if p == nil {
+			return 0
+		}
+		return *p
+	}
+	dataQuality, err := domaintask.NewDataQuality(valueOf(q.DropProbability), valueOf(q.DuplicateProbability), valueOf(q.BogusParentProbability))
+	if err != nil {
+		return domaintask.DataQuality{}, err
+	}
+	return *dataQuality, nil
+}
+
+// lossProbability returns the probability of the spans of the service being lost.
+func (q *ServiceDataQuality) lossProbability() (float64, error) {
+	if q == nil {
+		return 0, nil
+	}
+	if q.LossProbability < 0 || q.LossProbability > 1 {
+		return 0, fmt.Errorf("loss probability must be between 0 and 1")
+	}
+	return q.LossProbability, nil
+}
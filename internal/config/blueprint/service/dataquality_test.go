@@ -0,0 +1,146 @@
+package service
+
+import (
+	domaintask "github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+func TestDataQuality_To(t *testing.T) {
+	newDataQuality := func(drop, duplicate, bogusParent float64) domaintask.DataQuality {
+		q, _ := domaintask.NewDataQuality(drop, duplicate, bogusParent)
+		return *q
+	}
+	testCases := []struct {
+		name          string
+		dataQuality   *DataQuality
+		expected      domaintask.DataQuality
+		expectedError string
+	}{
+		{
+			name:        "no data quality",
+			dataQuality: nil,
+			expected:    domaintask.DataQuality{},
+		},
+		{
+			name: "all probabilities",
+			dataQuality: &DataQuality{
+				DropProbability:        ptrFloat64(0.1),
+				DuplicateProbability:   ptrFloat64(0.2),
+				BogusParentProbability: ptrFloat64(0.3),
+			},
+			expected: newDataQuality(0.1, 0.2, 0.3),
+		},
+		{
+			name:        "missing probabilities default to 0",
+			dataQuality: &DataQuality{DuplicateProbability: ptrFloat64(1)},
+			expected:    newDataQuality(0, 1, 0),
+		},
+		{
+			name:          "probability out of range",
+			dataQuality:   &DataQuality{BogusParentProbability: ptrFloat64(1.5)},
+			expectedError: "bogus parent probability must be between 0 and 1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dataQuality, err := tc.dataQuality.To()
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, dataQuality)
+		})
+	}
+}
+
+func TestDataQuality_WithDefault(t *testing.T) {
+	dq := &DataQuality{DropProbability: ptrFloat64(0.1), DuplicateProbability: ptrFloat64(0.2)}
+
+	t.Run("no data quality", func(t *testing.T) {
+		var q *DataQuality
+		assert.Equal(t, dq, q.WithDefault(dq))
+	})
+
+	t.Run("no default", func(t *testing.T) {
+		q := &DataQuality{DropProbability: ptrFloat64(0.5)}
+		assert.Equal(t, q, q.WithDefault(nil))
+	})
+
+	t.Run("fill missing probabilities", func(t *testing.T) {
+		q := &DataQuality{DropProbability: ptrFloat64(0.5), BogusParentProbability: ptrFloat64(0.3)}
+		assert.Equal(t, &DataQuality{
+			DropProbability:        ptrFloat64(0.5),
+			DuplicateProbability:   ptrFloat64(0.2),
+			BogusParentProbability: ptrFloat64(0.3),
+		}, q.WithDefault(dq))
+	})
+}
+
+func TestConvertConfigWithDataQuality(t *testing.T) {
+	newBlueprint := func(dataQuality *ServiceDataQuality) *Blueprint {
+		return &Blueprint{
+			Default: DefaultValues{
+				Delay:    &Delay{Value: ptrString("0"), Mode: ptrString("absolute")},
+				Duration: &Duration{Value: ptrString("1s"), Mode: ptrString("absolute")},
+			},
+			Services: []Service{
+				{
+					Name:        "service",
+					DataQuality: dataQuality,
+					SpanDefinitions: []SpanDefinition{
+						{
+							Name: "root",
+							Kind: "server",
+							Children: []SpanDefinition{
+								{
+									Name:        "child",
+									Kind:        "client",
+									DataQuality: &DataQuality{DropProbability: ptrFloat64(1)},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("apply the data quality of the service to its spans", func(t *testing.T) {
+		bp := newBlueprint(&ServiceDataQuality{
+			DataQuality:     DataQuality{DropProbability: ptrFloat64(0.1), DuplicateProbability: ptrFloat64(0.2)},
+			LossProbability: 0.3,
+		})
+		assert.NoError(t, bp.Validate())
+		sbp, err := bp.To(rand.Float64)
+		assert.NoError(t, err)
+		result, err := sbp.Interpret()
+		assert.NoError(t, err)
+		assert.Len(t, result, 1)
+
+		root := result[0]
+		assert.Equal(t, 0.1, root.Definition().DataQuality().Drop())
+		assert.Equal(t, 0.2, root.Definition().DataQuality().Duplicate())
+		resource := root.Definition().Resource()
+		assert.Equal(t, 0.3, resource.LossProbability())
+
+		child := root.Children()[0]
+		assert.Equal(t, 1.0, child.Definition().DataQuality().Drop())
+		assert.Equal(t, 0.2, child.Definition().DataQuality().Duplicate())
+	})
+
+	t.Run("invalid loss probability", func(t *testing.T) {
+		bp := newBlueprint(&ServiceDataQuality{LossProbability: 2})
+		_, err := bp.To(rand.Float64)
+		assert.EqualError(t, err, "service service has invalid data quality: loss probability must be between 0 and 1")
+	})
+
+	t.Run("invalid span data quality", func(t *testing.T) {
+		bp := newBlueprint(&ServiceDataQuality{DataQuality: DataQuality{DuplicateProbability: ptrFloat64(-1)}})
+		_, err := bp.To(rand.Float64)
+		assert.EqualError(t, err, "span root has invalid data quality: duplicate probability must be between 0 and 1")
+	})
+}
@@ -12,20 +12,25 @@ import (
 type Delay struct {
 	// Value is the delay value
 	Value *string `mapstructure:"for"`
-	// Mode is the delay mode (absolute or relative)
+	// Mode is the delay mode (absolute, relative or one of the statistical modes)
 	Mode *string `mapstructure:"as"`
+	// Distributions holds the parameters of the statistical modes
+	Distributions `mapstructure:",squash"`
 }
 
 // To converts the delay to a model.Delay
-func (d *Delay) To() (*task.Delay, error) {
+func (d *Delay) To(randomness func() float64) (*task.Delay, error) {
 	if err := d.ValidateAfterDefaults(); err != nil {
 		return nil, err
 	}
 	td := SpanDuration{
-		Duration: *d.Value,
-		Mode:     FromString(*d.Mode),
+		Mode:          FromString(*d.Mode),
+		Distributions: d.Distributions,
 	}
-	expr, err := td.To()
+	if d.Value != nil {
+		td.Duration = *d.Value
+	}
+	expr, err := td.To(randomness)
 	if err != nil {
 		return nil, fmt.Errorf("failed to convert delay: %w", err)
 	}
@@ -44,14 +49,14 @@ func (d *Delay) ValidateAfterDefaults() error {
 	if d == nil {
 		return fmt.Errorf("missing delay")
 	}
-	if d.Value == nil {
-		return fmt.Errorf("missing required field: delay.for")
-	}
 	if d.Mode == nil {
 		return fmt.Errorf("missing required field: delay.mode")
 	}
 	switch FromString(*d.Mode) {
 	case AbsoluteMode:
+		if d.Value == nil {
+			return fmt.Errorf("missing required field: delay.for")
+		}
 		dur, err := time.ParseDuration(*d.Value)
 		if err != nil {
 			return fmt.Errorf("invalid absolute delay format: %w", err)
@@ -60,6 +65,9 @@ func (d *Delay) ValidateAfterDefaults() error {
 			return fmt.Errorf("absolute delay must be non-negative")
 		}
 	case RelativeMode:
+		if d.Value == nil {
+			return fmt.Errorf("missing required field: delay.for")
+		}
 		f, err := strconv.ParseFloat(*d.Value, 64)
 		if err != nil {
 			return fmt.Errorf("invalid relative delay format: %w", err)
@@ -67,6 +75,10 @@ func (d *Delay) ValidateAfterDefaults() error {
 		if f < 0 {
 			return fmt.Errorf("relative delay must be non-negative")
 		}
+	case NormalMode, LogNormalMode, ExponentialMode, UniformMode, EmpiricalMode:
+		if err := d.Distributions.validate(FromString(*d.Mode), "delay", false); err != nil {
+			return err
+		}
 	default:
 		return fmt.Errorf("unsupported delay mode: %s", *d.Mode)
 	}
@@ -82,7 +94,8 @@ func (d *Delay) WithDefault(dd *Delay) *Delay {
 		return d
 	}
 	return &Delay{
-		Value: utils.Coalesce(d.Value, dd.Value),
-		Mode:  utils.Coalesce(d.Mode, dd.Mode),
+		Value:         utils.Coalesce(d.Value, dd.Value),
+		Mode:          utils.Coalesce(d.Mode, dd.Mode),
+		Distributions: d.Distributions.withDefault(dd.Distributions),
 	}
 }
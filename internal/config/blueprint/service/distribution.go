@@ -0,0 +1,196 @@
+package service
+
+import (
+	"fmt"
+	"github.com/k4ji/tracesimulationreceiver/internal/config/utils"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task/taskduration"
+	"time"
+)
+
+// Distributions holds the parameters of the statistical span duration modes.
+type Distributions struct {
+	// Normal is the parameters of the normal mode.
+	Normal *NormalDistribution `mapstructure:"normal"`
+	// LogNormal is the parameters of the lognormal mode.
+	LogNormal *LogNormalDistribution `mapstructure:"lognormal"`
+	// Exponential is the parameters of the exponential mode.
+	Exponential *ExponentialDistribution `mapstructure:"exponential"`
+	// Uniform is the parameters of the uniform mode.
+	Uniform *UniformDistribution `mapstructure:"uniform"`
+	// Empirical is the parameters of the empirical mode.
+	Empirical *EmpiricalDistribution `mapstructure:"empirical"`
+}
+
+// NormalDistribution represents a normal distribution, truncated at zero for durations and clamped at zero for delays.
+type NormalDistribution struct {
+	// Mean is the mean of the distribution.
+	Mean time.Duration `mapstructure:"mean"`
+	// StdDev is the standard deviation of the distribution.
+	StdDev time.Duration `mapstructure:"stddev"`
+}
+
+// LogNormalDistribution represents a log-normal distribution.
+type LogNormalDistribution struct {
+	// Median is the median of the distribution.
+	Median time.Duration `mapstructure:"median"`
+	// Sigma is the standard deviation of the underlying normal distribution, which controls the length of the tail.
+	Sigma float64 `mapstructure:"sigma"`
+}
+
+// ExponentialDistribution represents an exponential distribution.
+type ExponentialDistribution struct {
+	// Mean is the mean of the distribution.
+	Mean time.Duration `mapstructure:"mean"`
+}
+
+// UniformDistribution represents a uniform distribution.
+type UniformDistribution struct {
+	// Min is the lower bound of the distribution.
+	Min time.Duration `mapstructure:"min"`
+	// Max is the upper bound of the distribution.
+	Max time.Duration `mapstructure:"max"`
+}
+
+// EmpiricalDistribution represents a distribution given as a percentile table.
+type EmpiricalDistribution struct {
+	// Percentiles is the list of percentiles in increasing order.
+	Percentiles []Percentile `mapstructure:"percentiles"`
+}
+
+// Percentile represents a point of an empirical distribution.
+type Percentile struct {
+	// Percentile is the percentile between 0 and 100.
+	Percentile float64 `mapstructure:"percentile"`
+	// Value is the duration at the percentile.
+	Value time.Duration `mapstructure:"value"`
+}
+
+// validate checks if the parameters of the given distribution mode are valid.
+// field is the name of the configuration field (delay or duration) used in error messages,
+// and positive requires sampled durations to be greater than 0.
+func (d *Distributions) validate(mode Mode, field string, positive bool) error {
+	switch mode {
+	case NormalMode:
+		if d.Normal == nil {
+			return fmt.Errorf("missing required field: %s.normal", field)
+		}
+		if d.Normal.Mean < 0 || (positive && d.Normal.Mean == 0) {
+			return fmt.Errorf("normal %s mean must be %s", field, boundDescription(positive))
+		}
+		if d.Normal.StdDev < 0 {
+			return fmt.Errorf("normal %s stddev must be non-negative", field)
+		}
+		if positive && taskduration.NormalDurationRunsOut(d.Normal.Mean, d.Normal.StdDev) {
+			return fmt.Errorf("normal %s mean is too small for its stddev, which makes non-positive samples too likely", field)
+		}
+	case LogNormalMode:
+		if d.LogNormal == nil {
+			return fmt.Errorf("missing required field: %s.lognormal", field)
+		}
+		if d.LogNormal.Median <= 0 {
+			return fmt.Errorf("lognormal %s median must be greater than 0", field)
+		}
+		if d.LogNormal.Sigma < 0 {
+			return fmt.Errorf("lognormal %s sigma must be non-negative", field)
+		}
+	case ExponentialMode:
+		if d.Exponential == nil {
+			return fmt.Errorf("missing required field: %s.exponential", field)
+		}
+		if d.Exponential.Mean <= 0 {
+			return fmt.Errorf("exponential %s mean must be greater than 0", field)
+		}
+	case UniformMode:
+		if d.Uniform == nil {
+			return fmt.Errorf("missing required field: %s.uniform", field)
+		}
+		if d.Uniform.Min < 0 || (positive && d.Uniform.Min == 0) {
+			return fmt.Errorf("uniform %s min must be %s", field, boundDescription(positive))
+		}
+		if d.Uniform.Max < d.Uniform.Min {
+			return fmt.Errorf("uniform %s max must be greater than or equal to min", field)
+		}
+	case EmpiricalMode:
+		if d.Empirical == nil {
+			return fmt.Errorf("missing required field: %s.empirical", field)
+		}
+		if len(d.Empirical.Percentiles) == 0 {
+			return fmt.Errorf("empirical %s requires at least one percentile", field)
+		}
+		for i, p := range d.Empirical.Percentiles {
+			if p.Percentile < 0 || p.Percentile > 100 {
+				return fmt.Errorf("empirical %s percentile must be between 0 and 100", field)
+			}
+			if p.Value < 0 || (positive && p.Value == 0) {
+				return fmt.Errorf("empirical %s value must be %s", field, boundDescription(positive))
+			}
+			if i > 0 && p.Percentile <= d.Empirical.Percentiles[i-1].Percentile {
+				return fmt.Errorf("empirical %s percentiles must be in strictly increasing order", field)
+			}
+			if i > 0 && p.Value < d.Empirical.Percentiles[i-1].Value {
+				return fmt.Errorf("empirical %s values must be in non-decreasing order", field)
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported %s mode: %s", field, mode)
+	}
+	return nil
+}
+
+// to converts the parameters of the given distribution mode to a taskduration.Expression.
+// positive makes the normal distribution truncated at zero instead of clamped at zero.
+func (d *Distributions) to(mode Mode, positive bool, randomness func() float64) (taskduration.Expression, error) {
+	switch mode {
+	case NormalMode:
+		if d.Normal == nil {
+			return nil, fmt.Errorf("normal span duration requires normal configuration")
+		}
+		if !positive {
+			return taskduration.NewClampedNormalDuration(d.Normal.Mean, d.Normal.StdDev, randomness)
+		}
+		return taskduration.NewNormalDuration(d.Normal.Mean, d.Normal.StdDev, randomness)
+	case LogNormalMode:
+		if d.LogNormal == nil {
+			return nil, fmt.Errorf("lognormal span duration requires lognormal configuration")
+		}
+		return taskduration.NewLogNormalDuration(d.LogNormal.Median, d.LogNormal.Sigma, randomness)
+	case ExponentialMode:
+		if d.Exponential == nil {
+			return nil, fmt.Errorf("exponential span duration requires exponential configuration")
+		}
+		return taskduration.NewExponentialDuration(d.Exponential.Mean, randomness)
+	case UniformMode:
+		if d.Uniform == nil {
+			return nil, fmt.Errorf("uniform span duration requires uniform configuration")
+		}
+		return taskduration.NewUniformDuration(d.Uniform.Min, d.Uniform.Max, randomness)
+	case EmpiricalMode:
+		if d.Empirical == nil {
+			return nil, fmt.Errorf("empirical span duration requires empirical configuration")
+		}
+		percentiles := make([]taskduration.Percentile, len(d.Empirical.Percentiles))
+		for i, p := range d.Empirical.Percentiles {
+			percentiles[i] = taskduration.NewPercentile(p.Percentile, p.Value)
+		}
+		return taskduration.NewEmpiricalDuration(percentiles, randomness)
+	}
+	return nil, fmt.Errorf("unsupported span duration mode: %s", mode)
+}
+
+// withDefault returns new Distributions with default values applied.
+func (d Distributions) withDefault(dd Distributions) Distributions {
+	return Distributions{
+		Normal:      utils.Coalesce(d.Normal, dd.Normal),
+		LogNormal:   utils.Coalesce(d.LogNormal, dd.LogNormal),
+		Exponential: utils.Coalesce(d.Exponential, dd.Exponential),
+		Uniform:     utils.Coalesce(d.Uniform, dd.Uniform),
+		Empirical:   utils.Coalesce(d.Empirical, dd.Empirical),
+	}
+}
+
+func boundDescription(positive bool) string {
+	if positive {
+		return "greater than 0"
+	}
+	return "non-negative"
+}
@@ -12,20 +12,26 @@ import (
 type Duration struct {
 	// Value is the duration value
 	Value *string `mapstructure:"for"`
-	// Mode is the duration mode (absolute or relative)
+	// Mode is the duration mode (absolute, relative or one of the statistical modes)
 	Mode *string `mapstructure:"as"`
+	// Distributions holds the parameters of the statistical modes
+	Distributions `mapstructure:",squash"`
 }
 
 // To converts the duration to a model.Value
-func (d *Duration) To() (*task.Duration, error) {
+func (d *Duration) To(randomness func() float64) (*task.Duration, error) {
 	if err := d.ValidateAfterDefaults(); err != nil {
 		return nil, err
 	}
 	td := SpanDuration{
-		Duration: *d.Value,
-		Mode:     FromString(*d.Mode),
+		Mode:          FromString(*d.Mode),
+		Distributions: d.Distributions,
+		Positive:      true,
 	}
-	expr, err := td.To()
+	if d.Value != nil {
+		td.Duration = *d.Value
+	}
+	expr, err := td.To(randomness)
 	if err != nil {
 		return nil, fmt.Errorf("failed to convert duration: %w", err)
 	}
@@ -44,14 +50,14 @@ func (d *Duration) ValidateAfterDefaults() error {
 	if d == nil {
 		return fmt.Errorf("missing duration")
 	}
-	if d.Value == nil {
-		return fmt.Errorf("missing required field: duration.for")
-	}
 	if d.Mode == nil {
 		return fmt.Errorf("missing required field: duration.mode")
 	}
 	switch FromString(*d.Mode) {
 	case AbsoluteMode:
+		if d.Value == nil {
+			return fmt.Errorf("missing required field: duration.for")
+		}
 		dur, err := time.ParseDuration(*d.Value)
 		if err != nil {
 			return fmt.Errorf("invalid absolute duration format: %w", err)
@@ -60,6 +66,9 @@ func (d *Duration) ValidateAfterDefaults() error {
 			return fmt.Errorf("absolute duration must be greater than 0")
 		}
 	case RelativeMode:
+		if d.Value == nil {
+			return fmt.Errorf("missing required field: duration.for")
+		}
 		f, err := strconv.ParseFloat(*d.Value, 64)
 		if err != nil {
 			return fmt.Errorf("invalid relative duration format: %w", err)
@@ -67,6 +76,10 @@ func (d *Duration) ValidateAfterDefaults() error {
 		if f <= 0 {
 			return fmt.Errorf("relative duration must be greater than 0")
 		}
+	case NormalMode, LogNormalMode, ExponentialMode, UniformMode, EmpiricalMode:
+		if err := d.Distributions.validate(FromString(*d.Mode), "duration", true); err != nil {
+			return err
+		}
 	default:
 		return fmt.Errorf("unsupported duration mode: %s", *d.Mode)
 	}
@@ -82,7 +95,8 @@ func (d *Duration) WithDefault(dd *Duration) *Duration {
 		return d
 	}
 	return &Duration{
-		Value: utils.Coalesce(d.Value, dd.Value),
-		Mode:  utils.Coalesce(d.Mode, dd.Mode),
+		Value:         utils.Coalesce(d.Value, dd.Value),
+		Mode:          utils.Coalesce(d.Mode, dd.Mode),
+		Distributions: d.Distributions.withDefault(dd.Distributions),
 	}
 }
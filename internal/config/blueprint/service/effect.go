@@ -3,6 +3,7 @@ package service
 import (
 	"fmt"
 	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
+	"time"
 )
 
 // Effect represents an effect that can be applied to a span.
@@ -15,6 +16,8 @@ type Effect struct {
 	Annotate Annotate `mapstructure:"annotate"`
 	// RecordEvent is the effect to record an event.
 	RecordEvent RecordEvent `mapstructure:"record_event"`
+	// SlowDown is the effect to make the span take longer.
+	SlowDown SlowDown `mapstructure:"slow_down"`
 }
 
 // MarkAsFailed represents an effect that marks a span as failed.
@@ -26,7 +29,7 @@ type MarkAsFailed struct {
 // Annotate represents an effect that annotates a span.
 type Annotate struct {
 	// Attributes are the attributes to be used when annotating the span.
-	Attributes map[string]string `mapstructure:"attributes"`
+	Attributes Attributes `mapstructure:"attributes"`
 }
 
 // RecordEvent represents an effect that records an event.
@@ -34,23 +37,66 @@ type RecordEvent struct {
 	Event Event `mapstructure:"event"`
 }
 
+// SlowDown represents an effect that makes a span take longer.
+// The parent is stretched to contain the span, and the later siblings are shifted in sequential mode.
+type SlowDown struct {
+	// By is the fixed time added to the duration of the span.
+	By *time.Duration `mapstructure:"by"`
+	// Factor is the factor the duration of the span is multiplied by.
+	Factor *float64 `mapstructure:"factor"`
+}
+
 // To converts the effect to a domain model.
-func (e *Effect) To() (*task.Effect, error) {
+func (e *Effect) To(randomness func() float64) (*task.Effect, error) {
 	switch e.Kind {
 	case "mark_as_failed":
 		e := task.FromMarkAsFailedEffect(task.NewMarkAsFailedEffect(e.MarkAsFailed.Message))
 		return &e, nil
 	case "annotate":
-		e := task.FromAnnotateEffect(task.NewAnnotateEffect(e.Annotate.Attributes))
+		attributes, err := e.Annotate.Attributes.To(randomness)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert annotate effect: %w", err)
+		}
+		e := task.FromAnnotateEffect(task.NewAnnotateEffect(attributes))
 		return &e, nil
 	case "record_event":
-		event, err := e.RecordEvent.Event.To()
+		event, err := e.RecordEvent.Event.To(randomness)
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert record event effect: %w", err)
 		}
 		e := task.FromRecordEventEffect(task.NewRecordEventEffect(*event))
 		return &e, nil
+	case "slow_down":
+		slowDown, err := e.SlowDown.To()
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert slow down effect: %w", err)
+		}
+		e := task.FromSlowDownEffect(*slowDown)
+		return &e, nil
 	default:
 		return nil, fmt.Errorf("unknown effect type: %s", e.Kind)
 	}
 }
+
+// To converts the slow down effect to a domain model.
+func (s *SlowDown) To() (*task.SlowDownEffect, error) {
+	if s.By == nil && s.Factor == nil {
+		return nil, fmt.Errorf("either by or factor must be set")
+	}
+	var by time.Duration
+	if s.By != nil {
+		if *s.By < 0 {
+			return nil, fmt.Errorf("by must be greater than or equal to 0")
+		}
+		by = *s.By
+	}
+	factor := 1.0
+	if s.Factor != nil {
+		if *s.Factor < 1 {
+			return nil, fmt.Errorf("factor must be greater than or equal to 1")
+		}
+		factor = *s.Factor
+	}
+	slowDown := task.NewSlowDownEffect(by, factor)
+	return &slowDown, nil
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	domaintask "github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestEffect_ToSlowDown(t *testing.T) {
+	by := 200 * time.Millisecond
+	negative := -time.Millisecond
+	testCases := []struct {
+		name          string
+		slowDown      SlowDown
+		expected      domaintask.SlowDownEffect
+		expectedError string
+	}{
+		{
+			name:     "fixed time",
+			slowDown: SlowDown{By: &by},
+			expected: domaintask.NewSlowDownEffect(200*time.Millisecond, 1),
+		},
+		{
+			name:     "factor",
+			slowDown: SlowDown{Factor: ptrFloat64(3)},
+			expected: domaintask.NewSlowDownEffect(0, 3),
+		},
+		{
+			name:     "fixed time and factor",
+			slowDown: SlowDown{By: &by, Factor: ptrFloat64(1.5)},
+			expected: domaintask.NewSlowDownEffect(200*time.Millisecond, 1.5),
+		},
+		{
+			name:          "neither fixed time nor factor",
+			slowDown:      SlowDown{},
+			expectedError: "failed to convert slow down effect: either by or factor must be set",
+		},
+		{
+			name:          "negative fixed time",
+			slowDown:      SlowDown{By: &negative},
+			expectedError: "failed to convert slow down effect: by must be greater than or equal to 0",
+		},
+		{
+			name:          "factor less than 1",
+			slowDown:      SlowDown{Factor: ptrFloat64(0.5)},
+			expectedError: "failed to convert slow down effect: factor must be greater than or equal to 1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			effect := Effect{Kind: "slow_down", SlowDown: tc.slowDown}
+			actual, err := effect.To(rand.Float64)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, domaintask.EffectKindSlowDown, actual.Kind())
+			assert.Equal(t, tc.expected, *actual.SlowDownEffect())
+		})
+	}
+}
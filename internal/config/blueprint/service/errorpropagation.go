@@ -0,0 +1,33 @@
+package service
+
+import (
+	"fmt"
+	domaintask "github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
+)
+
+// ErrorPropagation specifies how the failure of a span propagates to its ancestors.
+type ErrorPropagation struct {
+	// Target specifies how far the failure propagates: "none" (default), "parent", or "server" (up to the nearest server span).
+	Target string `mapstructure:"to"`
+	// RecordException specifies whether the ancestors marked as failed record an exception event.
+	RecordException bool `mapstructure:"record_exception"`
+}
+
+// To converts the error propagation to a domain model.
+func (e *ErrorPropagation) To() (domaintask.ErrorPropagation, error) {
+	if e == nil {
+		return domaintask.ErrorPropagation{}, nil
+	}
+	var mode domaintask.ErrorPropagationMode
+	switch e.Target {
+	case "", "none":
+		mode = domaintask.ErrorPropagationNone
+	case "parent":
+		mode = domaintask.ErrorPropagationParent
+	case "server":
+		mode = domaintask.ErrorPropagationServer
+	default:
+		return domaintask.ErrorPropagation{}, fmt.Errorf("unknown error propagation target: %s", e.Target)
+	}
+	return domaintask.NewErrorPropagation(mode, e.RecordException), nil
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	domaintask "github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestErrorPropagation_To(t *testing.T) {
+	testCases := []struct {
+		name             string
+		errorPropagation *ErrorPropagation
+		expected         domaintask.ErrorPropagation
+		expectedError    string
+	}{
+		{
+			name:     "default to none",
+			expected: domaintask.NewErrorPropagation(domaintask.ErrorPropagationNone, false),
+		},
+		{
+			name:             "none",
+			errorPropagation: &ErrorPropagation{Target: "none"},
+			expected:         domaintask.NewErrorPropagation(domaintask.ErrorPropagationNone, false),
+		},
+		{
+			name:             "parent",
+			errorPropagation: &ErrorPropagation{Target: "parent"},
+			expected:         domaintask.NewErrorPropagation(domaintask.ErrorPropagationParent, false),
+		},
+		{
+			name:             "server with exception",
+			errorPropagation: &ErrorPropagation{Target: "server", RecordException: true},
+			expected:         domaintask.NewErrorPropagation(domaintask.ErrorPropagationServer, true),
+		},
+		{
+			name:             "unknown target",
+			errorPropagation: &ErrorPropagation{Target: "root"},
+			expectedError:    "unknown error propagation target: root",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			errorPropagation, err := tc.errorPropagation.To()
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, errorPropagation)
+		})
+	}
+}
@@ -1,20 +1,28 @@
 package service
 
-import "github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
+import (
+	"fmt"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
+)
 
 type Event struct {
-	Name       string            `mapstructure:"name"`
-	Delay      Delay             `mapstructure:"delay"`
-	Attributes map[string]string `mapstructure:"attributes"`
+	Name       string     `mapstructure:"name"`
+	Delay      Delay      `mapstructure:"delay"`
+	Attributes Attributes `mapstructure:"attributes"`
 }
 
 // To converts the event to a domain model.
-func (e *Event) To() (*task.Event, error) {
-	delay, err := e.Delay.To()
+func (e *Event) To(randomness func() float64) (*task.Event, error) {
+	delay, err := e.Delay.To(randomness)
 	if err != nil {
 		return nil, err
 	}
 
-	event := task.NewEvent(e.Name, *delay, e.Attributes)
+	attributes, err := e.Attributes.To(randomness)
+	if err != nil {
+		return nil, fmt.Errorf("event %s has invalid attributes: %w", e.Name, err)
+	}
+
+	event := task.NewEvent(e.Name, *delay, attributes)
 	return &event, nil
 }
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"fmt"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/attribute"
+	"net"
+	"sort"
+	"strings"
+)
+
+const (
+	defaultSequenceStart  = 1
+	defaultSequenceStep   = 1
+	defaultRandomIPCIDR   = "10.0.0.0/8"
+	defaultUserIDPrefix   = "user-"
+	generatorKindKey      = "generator"
+	generatorRandomInt    = "random_int"
+	generatorWeightedEnum = "weighted_enum"
+	generatorUUID         = "uuid"
+	generatorSequence     = "sequence"
+	generatorRandomIP     = "random_ip"
+	generatorUserID       = "user_id"
+)
+
+// toGenerator converts the generator form of an attribute value, e.g. {generator: random_int, min: 1, max: 100}
+func toGenerator(g map[string]any, randomness func() float64) (attribute.Expression, error) {
+	kind, ok := g[generatorKindKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("generator must be a string")
+	}
+	switch kind {
+	case generatorRandomInt:
+		if err := checkGeneratorFields(g, "min", "max"); err != nil {
+			return nil, err
+		}
+		minimum, err := requiredIntField(g, "min")
+		if err != nil {
+			return nil, err
+		}
+		maximum, err := requiredIntField(g, "max")
+		if err != nil {
+			return nil, err
+		}
+		if minimum > maximum {
+			return nil, fmt.Errorf("random_int generator must have min less than or equal to max")
+		}
+		return attribute.NewRandomInt(minimum, maximum, randomness), nil
+	case generatorWeightedEnum:
+		if err := checkGeneratorFields(g, "values"); err != nil {
+			return nil, err
+		}
+		choices, err := toWeightedChoices(g["values"])
+		if err != nil {
+			return nil, err
+		}
+		return attribute.NewWeightedEnum(choices, randomness), nil
+	case generatorUUID:
+		if err := checkGeneratorFields(g); err != nil {
+			return nil, err
+		}
+		return attribute.NewUUID(randomness), nil
+	case generatorSequence:
+		if err := checkGeneratorFields(g, "start", "step"); err != nil {
+			return nil, err
+		}
+		start, err := optionalIntField(g, "start", defaultSequenceStart)
+		if err != nil {
+			return nil, err
+		}
+		step, err := optionalIntField(g, "step", defaultSequenceStep)
+		if err != nil {
+			return nil, err
+		}
+		return attribute.NewSequence(start, step), nil
+	case generatorRandomIP:
+		if err := checkGeneratorFields(g, "cidr"); err != nil {
+			return nil, err
+		}
+		cidr := defaultRandomIPCIDR
+		if v, exists := g["cidr"]; exists {
+			s, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("cidr must be a string")
+			}
+			cidr = s
+		}
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("random_ip generator has an invalid cidr: %w", err)
+		}
+		if network.IP.To4() == nil {
+			return nil, fmt.Errorf("random_ip generator supports only IPv4 networks")
+		}
+		return attribute.NewRandomIP(network, randomness), nil
+	case generatorUserID:
+		if err := checkGeneratorFields(g, "pool_size", "prefix"); err != nil {
+			return nil, err
+		}
+		poolSize, err := requiredIntField(g, "pool_size")
+		if err != nil {
+			return nil, err
+		}
+		if poolSize <= 0 {
+			return nil, fmt.Errorf("user_id generator must have pool_size greater than 0")
+		}
+		prefix := defaultUserIDPrefix
+		if v, exists := g["prefix"]; exists {
+			s, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("prefix must be a string")
+			}
+			prefix = s
+		}
+		return attribute.NewUserID(prefix, int(poolSize), randomness), nil
+	default:
+		return nil, fmt.Errorf("unknown generator: %s", kind)
+	}
+}
+
+func toWeightedChoices(v any) ([]attribute.WeightedChoice, error) {
+	values, ok := v.([]any)
+	if !ok || len(values) == 0 {
+		return nil, fmt.Errorf("weighted_enum generator must have at least one value")
+	}
+	choices := make([]attribute.WeightedChoice, 0, len(values))
+	for i, e := range values {
+		c, ok := e.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("value %d of weighted_enum generator must be an object with the fields 'value' and 'weight'", i)
+		}
+		if err := checkFields(c, "value", "weight"); err != nil {
+			return nil, fmt.Errorf("value %d of weighted_enum generator: %w", i, err)
+		}
+		value, err := toAttributeValue(c["value"])
+		if err != nil {
+			return nil, fmt.Errorf("value %d of weighted_enum generator: %w", i, err)
+		}
+		weight := 1.0
+		if w, exists := c["weight"]; exists {
+			f, ok := toFloat(w)
+			if !ok || f <= 0 {
+				return nil, fmt.Errorf("value %d of weighted_enum generator must have a weight greater than 0", i)
+			}
+			weight = f
+		}
+		choices = append(choices, attribute.NewWeightedChoice(*value, weight))
+	}
+	return choices, nil
+}
+
+func checkGeneratorFields(g map[string]any, allowed ...string) error {
+	return checkFields(g, append(allowed, generatorKindKey)...)
+}
+
+func checkFields(m map[string]any, allowed ...string) error {
+	var unknown []string
+	for k := range m {
+		found := false
+		for _, a := range allowed {
+			if k == a {
+				found = true
+				break
+			}
+		}
+		if !found {
+			unknown = append(unknown, k)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown fields: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+func requiredIntField(m map[string]any, name string) (int64, error) {
+	v, exists := m[name]
+	if !exists {
+		return 0, fmt.Errorf("%s is required", name)
+	}
+	i, ok := toInt(v)
+	if !ok {
+		return 0, fmt.Errorf("%s must be an integer", name)
+	}
+	return i, nil
+}
+
+func optionalIntField(m map[string]any, name string, defaultValue int64) (int64, error) {
+	if _, exists := m[name]; !exists {
+		return defaultValue, nil
+	}
+	return requiredIntField(m, name)
+}
+
+func toFloat(v any) (float64, bool) {
+	switch v := v.(type) {
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		i, ok := toInt(v)
+		return float64(i), ok
+	}
+}
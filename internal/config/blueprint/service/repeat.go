@@ -0,0 +1,62 @@
+package service
+
+import (
+	"fmt"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/blueprint/service/model"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/attribute"
+	"time"
+)
+
+// Repeat represents the repetition of a span as siblings.
+type Repeat struct {
+	// Count is the fixed number of repetitions.
+	Count *int `mapstructure:"count"`
+
+	// Min is the minimum number of repetitions when the number is drawn at random.
+	Min *int `mapstructure:"min"`
+
+	// Max is the maximum number of repetitions when the number is drawn at random.
+	Max *int `mapstructure:"max"`
+
+	// Index is the name of the variable that holds the zero-based index of the repetition.
+	// It can be referenced as ${repeat.<index>} in the names and attributes of the span and its descendants.
+	Index *string `mapstructure:"index"`
+
+	// Stagger is the delay added to each repetition relative to the previous one.
+	Stagger time.Duration `mapstructure:"stagger"`
+}
+
+// To converts the repeat to a domain model.
+func (r *Repeat) To(randomness func() float64) (*model.Repeat, error) {
+	var minimum, maximum int
+	switch {
+	case r.Count != nil && r.Min == nil && r.Max == nil:
+		if *r.Count < 0 {
+			return nil, fmt.Errorf("repeat count must be greater than or equal to 0")
+		}
+		minimum, maximum = *r.Count, *r.Count
+	case r.Count == nil && r.Min != nil && r.Max != nil:
+		if *r.Min < 0 {
+			return nil, fmt.Errorf("repeat min must be greater than or equal to 0")
+		}
+		if *r.Min > *r.Max {
+			return nil, fmt.Errorf("repeat min must be less than or equal to max")
+		}
+		minimum, maximum = *r.Min, *r.Max
+	default:
+		return nil, fmt.Errorf("repeat must have either count or both min and max")
+	}
+	if r.Index != nil && !attribute.VariableNamePattern.MatchString(*r.Index) {
+		return nil, fmt.Errorf("repeat index %s must match the regex %s", *r.Index, attribute.VariableNamePattern)
+	}
+	if r.Stagger < 0 {
+		return nil, fmt.Errorf("repeat stagger must be greater than or equal to 0")
+	}
+	return &model.Repeat{
+		Minimum:    minimum,
+		Maximum:    maximum,
+		Index:      r.Index,
+		Stagger:    r.Stagger,
+		Randomness: randomness,
+	}, nil
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/attribute"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/span"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestRepeat(t *testing.T) {
+	newBlueprint := func(children []SpanDefinition) *Blueprint {
+		return &Blueprint{
+			Default: DefaultValues{
+				Delay:    &Delay{Value: ptrString("0"), Mode: ptrString("absolute")},
+				Duration: &Duration{Value: ptrString("10ms"), Mode: ptrString("absolute")},
+			},
+			Services: []Service{
+				{
+					Name: "service",
+					SpanDefinitions: []SpanDefinition{
+						{
+							Name:     "root",
+							Kind:     "server",
+							Duration: &Duration{Value: ptrString("1s"), Mode: ptrString("absolute")},
+							Children: children,
+						},
+					},
+				},
+			},
+		}
+	}
+	toSpan := func(t *testing.T, bp *Blueprint) *span.TreeNode {
+		sbp, err := bp.To(rand.Float64)
+		assert.NoError(t, err)
+		result, err := sbp.Interpret()
+		assert.NoError(t, err)
+		root, err := span.FromTaskTree(result[0], span.NewTraceID([16]byte{0x01}), time.Unix(0, 0), func() span.ID { return span.NewSpanID([8]byte{0x01}) }, nil)
+		assert.NoError(t, err)
+		return root
+	}
+
+	t.Run("expand into siblings with index and stagger", func(t *testing.T) {
+		root := toSpan(t, newBlueprint([]SpanDefinition{
+			{
+				Name:       "SELECT item ${repeat.i}",
+				Kind:       "client",
+				Attributes: Attributes{"item.index": "${repeat.i}", "db.statement": "SELECT * FROM items WHERE id = ${repeat.i}"},
+				Repeat:     &Repeat{Count: ptrInt(3), Index: ptrString("i"), Stagger: 20 * time.Millisecond},
+				Children: []SpanDefinition{
+					{Name: "decode ${repeat.i}", Kind: "internal", Attributes: Attributes{"item.index": "${repeat.i}"}},
+				},
+			},
+		}))
+
+		assert.Len(t, root.Children(), 3)
+		for i, child := range root.Children() {
+			assert.Equal(t, "SELECT item "+[]string{"0", "1", "2"}[i], child.Name())
+			assert.Equal(t, map[string]attribute.Value{
+				"item.index":   attribute.Int(int64(i)),
+				"db.statement": attribute.String("SELECT * FROM items WHERE id = " + []string{"0", "1", "2"}[i]),
+			}, child.Attributes())
+			assert.Equal(t, time.Unix(0, 0).Add(time.Duration(i)*20*time.Millisecond), child.StartTime())
+			assert.Equal(t, "decode "+[]string{"0", "1", "2"}[i], child.Children()[0].Name())
+			assert.Equal(t, map[string]attribute.Value{"item.index": attribute.Int(int64(i))}, child.Children()[0].Attributes())
+		}
+	})
+
+	t.Run("draw the number of repetitions from a range", func(t *testing.T) {
+		bp := newBlueprint([]SpanDefinition{
+			{Name: "lookup", Kind: "client", Repeat: &Repeat{Min: ptrInt(2), Max: ptrInt(4)}},
+		})
+		for i := 0; i < 20; i++ {
+			root := toSpan(t, bp)
+			assert.GreaterOrEqual(t, len(root.Children()), 2)
+			assert.LessOrEqual(t, len(root.Children()), 4)
+		}
+	})
+
+	t.Run("invalid repeats", func(t *testing.T) {
+		testCases := []struct {
+			name     string
+			span     SpanDefinition
+			expected string
+		}{
+			{
+				name:     "neither count nor range",
+				span:     SpanDefinition{Name: "lookup", Repeat: &Repeat{}},
+				expected: "span lookup has invalid repeat: repeat must have either count or both min and max",
+			},
+			{
+				name:     "both count and range",
+				span:     SpanDefinition{Name: "lookup", Repeat: &Repeat{Count: ptrInt(1), Min: ptrInt(1), Max: ptrInt(2)}},
+				expected: "span lookup has invalid repeat: repeat must have either count or both min and max",
+			},
+			{
+				name:     "negative count",
+				span:     SpanDefinition{Name: "lookup", Repeat: &Repeat{Count: ptrInt(-1)}},
+				expected: "span lookup has invalid repeat: repeat count must be greater than or equal to 0",
+			},
+			{
+				name:     "min greater than max",
+				span:     SpanDefinition{Name: "lookup", Repeat: &Repeat{Min: ptrInt(3), Max: ptrInt(2)}},
+				expected: "span lookup has invalid repeat: repeat min must be less than or equal to max",
+			},
+			{
+				name:     "invalid index",
+				span:     SpanDefinition{Name: "lookup", Repeat: &Repeat{Count: ptrInt(2), Index: ptrString("page-index")}},
+				expected: "span lookup has invalid repeat: repeat index page-index must match the regex ^[a-zA-Z_][a-zA-Z0-9_]*$",
+			},
+			{
+				name:     "negative stagger",
+				span:     SpanDefinition{Name: "lookup", Repeat: &Repeat{Count: ptrInt(2), Stagger: -time.Millisecond}},
+				expected: "span lookup has invalid repeat: repeat stagger must be greater than or equal to 0",
+			},
+			{
+				name: "ref in repeated subtree",
+				span: SpanDefinition{
+					Name:     "lookup",
+					Repeat:   &Repeat{Count: ptrInt(2)},
+					Children: []SpanDefinition{{Name: "decode", Ref: ptrString("decode")}},
+				},
+				expected: "span lookup is repeated, so it and its children cannot have ref decode",
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				_, err := newBlueprint([]SpanDefinition{tc.span}).To(rand.Float64)
+				assert.EqualError(t, err, tc.expected)
+			})
+		}
+	})
+
+	t.Run("reference index outside of the repeated span", func(t *testing.T) {
+		bp := newBlueprint([]SpanDefinition{
+			{Name: "lookup", Repeat: &Repeat{Count: ptrInt(2), Index: ptrString("i")}},
+			{Name: "render", Attributes: Attributes{"item.index": "${repeat.i}"}},
+		})
+		assert.EqualError(t, bp.Validate(), "span render references undefined variable repeat.i")
+	})
+}
+
+func ptrInt(i int) *int {
+	return &i
+}
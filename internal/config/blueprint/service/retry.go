@@ -0,0 +1,94 @@
+package service
+
+import (
+	"fmt"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
+	"time"
+)
+
+// DefaultBackoffMultiplier is the default factor the wait time is multiplied by for every retry in the exponential backoff.
+const DefaultBackoffMultiplier = 2.0
+
+// Retry represents how a client span is attempted again when it fails.
+type Retry struct {
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	MaxAttempts int `mapstructure:"max_attempts"`
+
+	// Backoff specifies the wait time between the end of a failed attempt and the start of the next one.
+	Backoff Backoff `mapstructure:"backoff"`
+
+	// On is the condition for an attempt to count as failed. If not set, an attempt fails when it is marked as failed.
+	On *Condition `mapstructure:"on"`
+}
+
+// Backoff represents the wait time between attempts.
+type Backoff struct {
+	// Type is the type of the backoff, either "fixed" (default) or "exponential".
+	Type string `mapstructure:"type"`
+
+	// Initial is the wait time before the first retry.
+	Initial time.Duration `mapstructure:"initial"`
+
+	// Multiplier is the factor the wait time is multiplied by for every retry in the exponential backoff.
+	Multiplier *float64 `mapstructure:"multiplier"`
+
+	// Max is the upper limit of the wait time in the exponential backoff. No limit if not set.
+	Max time.Duration `mapstructure:"max"`
+
+	// Jitter is the fraction of the wait time that is randomly added or subtracted, between 0 and 1.
+	Jitter float64 `mapstructure:"jitter"`
+}
+
+// To converts the retry to a domain model.
+func (r *Retry) To(randomness func() float64) (*task.Retry, error) {
+	if r.MaxAttempts < 1 {
+		return nil, fmt.Errorf("max_attempts must be greater than or equal to 1")
+	}
+	backoff, err := r.Backoff.To(randomness)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backoff: %w", err)
+	}
+	var failure *task.Condition
+	if r.On != nil {
+		failure, err = r.On.To(randomness)
+		if err != nil {
+			return nil, fmt.Errorf("invalid failure condition: %w", err)
+		}
+	}
+	retry := task.NewRetry(r.MaxAttempts, *backoff, failure)
+	return &retry, nil
+}
+
+// To converts the backoff to a domain model.
+func (b *Backoff) To(randomness func() float64) (*task.Backoff, error) {
+	if b.Initial < 0 {
+		return nil, fmt.Errorf("initial must be greater than or equal to 0")
+	}
+	if b.Jitter < 0 || b.Jitter > 1 {
+		return nil, fmt.Errorf("jitter must be between 0 and 1")
+	}
+	var kind task.BackoffKind
+	multiplier := DefaultBackoffMultiplier
+	switch b.Type {
+	case "", "fixed":
+		if b.Multiplier != nil || b.Max != 0 {
+			return nil, fmt.Errorf("multiplier and max require the exponential backoff")
+		}
+		kind = task.BackoffFixed
+	case "exponential":
+		if b.Multiplier != nil {
+			if *b.Multiplier < 1 {
+				return nil, fmt.Errorf("multiplier must be greater than or equal to 1")
+			}
+			multiplier = *b.Multiplier
+		}
+		if b.Max < 0 {
+			return nil, fmt.Errorf("max must be greater than or equal to 0")
+		}
+		kind = task.BackoffExponential
+	default:
+		return nil, fmt.Errorf("unknown backoff type: %s", b.Type)
+	}
+	backoff := task.NewBackoff(kind, b.Initial, multiplier, b.Max, b.Jitter, randomness)
+	return &backoff, nil
+}
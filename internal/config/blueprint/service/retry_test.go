@@ -0,0 +1,117 @@
+package service
+
+import (
+	domaintask "github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestRetry_To(t *testing.T) {
+	t.Run("convert retry", func(t *testing.T) {
+		retry := Retry{
+			MaxAttempts: 3,
+			Backoff:     Backoff{Type: "exponential", Initial: 100 * time.Millisecond, Max: time.Second},
+			On:          &Condition{Kind: "has_attribute", HasAttribute: &HasAttribute{Key: "http.response.status_code"}},
+		}
+		actual, err := retry.To(rand.Float64)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, actual.MaxAttempts())
+		assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond}, []time.Duration{
+			actual.Backoff().WaitBefore(1),
+			actual.Backoff().WaitBefore(2),
+			actual.Backoff().WaitBefore(3),
+		})
+		assert.Equal(t, domaintask.ConditionKindHasAttribute, actual.Failure().Kind())
+	})
+
+	testCases := []struct {
+		name          string
+		retry         Retry
+		expectedError string
+	}{
+		{
+			name:          "no attempts",
+			retry:         Retry{},
+			expectedError: "max_attempts must be greater than or equal to 1",
+		},
+		{
+			name:          "unknown backoff type",
+			retry:         Retry{MaxAttempts: 3, Backoff: Backoff{Type: "linear"}},
+			expectedError: "invalid backoff: unknown backoff type: linear",
+		},
+		{
+			name:          "multiplier with fixed backoff",
+			retry:         Retry{MaxAttempts: 3, Backoff: Backoff{Multiplier: ptrFloat64(2)}},
+			expectedError: "invalid backoff: multiplier and max require the exponential backoff",
+		},
+		{
+			name:          "multiplier less than 1",
+			retry:         Retry{MaxAttempts: 3, Backoff: Backoff{Type: "exponential", Multiplier: ptrFloat64(0.5)}},
+			expectedError: "invalid backoff: multiplier must be greater than or equal to 1",
+		},
+		{
+			name:          "jitter out of range",
+			retry:         Retry{MaxAttempts: 3, Backoff: Backoff{Jitter: 1.5}},
+			expectedError: "invalid backoff: jitter must be between 0 and 1",
+		},
+		{
+			name:          "invalid failure condition",
+			retry:         Retry{MaxAttempts: 3, On: &Condition{Kind: "unknown"}},
+			expectedError: "invalid failure condition: unknown condition type: unknown",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.retry.To(rand.Float64)
+			assert.EqualError(t, err, tc.expectedError)
+		})
+	}
+}
+
+func TestSpanDefinitionRetry(t *testing.T) {
+	newSpan := func(kind string, childRef *string) SpanDefinition {
+		return SpanDefinition{
+			Name:     "call",
+			Kind:     kind,
+			Delay:    &Delay{Value: ptrString("0"), Mode: ptrString("absolute")},
+			Duration: &Duration{Value: ptrString("10ms"), Mode: ptrString("absolute")},
+			Retry:    &Retry{MaxAttempts: 3},
+			Children: []SpanDefinition{
+				{
+					Name:     "send",
+					Ref:      childRef,
+					Delay:    &Delay{Value: ptrString("0"), Mode: ptrString("absolute")},
+					Duration: &Duration{Value: ptrString("1ms"), Mode: ptrString("absolute")},
+				},
+			},
+		}
+	}
+
+	t.Run("convert retry of a client span", func(t *testing.T) {
+		sd := newSpan("client", nil)
+		task, err := sd.To(rand.Float64)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, task.Retry.MaxAttempts())
+	})
+
+	t.Run("return error if the span is not a client span", func(t *testing.T) {
+		sd := newSpan("server", nil)
+		_, err := sd.To(rand.Float64)
+		assert.EqualError(t, err, "span call is not a client span, so it cannot have retry")
+	})
+
+	t.Run("return error if the span has refs", func(t *testing.T) {
+		sd := newSpan("client", ptrString("send"))
+		_, err := sd.To(rand.Float64)
+		assert.EqualError(t, err, "span call is retried, so it and its children cannot have ref send")
+	})
+
+	t.Run("return error if the span is the root of a trace", func(t *testing.T) {
+		bp := Blueprint{Services: []Service{{Name: "client", SpanDefinitions: []SpanDefinition{newSpan("client", nil)}}}}
+		err := bp.Validate()
+		assert.EqualError(t, err, "span call is the root of a trace, so it cannot have retry")
+	})
+}
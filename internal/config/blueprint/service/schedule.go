@@ -0,0 +1,31 @@
+package service
+
+import (
+	"fmt"
+	domaintask "github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
+	"time"
+)
+
+// toSchedule converts the schedule of the children and the gap between them to a domain model
+func toSchedule(schedule *string, gap time.Duration) (domaintask.Schedule, error) {
+	if gap < 0 {
+		return domaintask.Schedule{}, fmt.Errorf("schedule gap must be greater than or equal to 0")
+	}
+	if schedule == nil {
+		if gap > 0 {
+			return domaintask.Schedule{}, fmt.Errorf("schedule gap requires the sequential schedule")
+		}
+		return domaintask.NewSchedule(domaintask.ScheduleParallel, 0), nil
+	}
+	switch *schedule {
+	case "parallel":
+		if gap > 0 {
+			return domaintask.Schedule{}, fmt.Errorf("schedule gap requires the sequential schedule")
+		}
+		return domaintask.NewSchedule(domaintask.ScheduleParallel, 0), nil
+	case "sequential":
+		return domaintask.NewSchedule(domaintask.ScheduleSequential, gap), nil
+	default:
+		return domaintask.Schedule{}, fmt.Errorf("unknown schedule: %s", *schedule)
+	}
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	domaintask "github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestToSchedule(t *testing.T) {
+	testCases := []struct {
+		name          string
+		schedule      *string
+		gap           time.Duration
+		expected      domaintask.Schedule
+		expectedError string
+	}{
+		{
+			name:     "default to parallel",
+			expected: domaintask.NewSchedule(domaintask.ScheduleParallel, 0),
+		},
+		{
+			name:     "parallel",
+			schedule: ptrString("parallel"),
+			expected: domaintask.NewSchedule(domaintask.ScheduleParallel, 0),
+		},
+		{
+			name:     "sequential with gap",
+			schedule: ptrString("sequential"),
+			gap:      50 * time.Millisecond,
+			expected: domaintask.NewSchedule(domaintask.ScheduleSequential, 50*time.Millisecond),
+		},
+		{
+			name:          "unknown schedule",
+			schedule:      ptrString("random"),
+			expectedError: "unknown schedule: random",
+		},
+		{
+			name:          "gap without sequential schedule",
+			gap:           50 * time.Millisecond,
+			expectedError: "schedule gap requires the sequential schedule",
+		},
+		{
+			name:          "gap with parallel schedule",
+			schedule:      ptrString("parallel"),
+			gap:           50 * time.Millisecond,
+			expectedError: "schedule gap requires the sequential schedule",
+		},
+		{
+			name:          "negative gap",
+			schedule:      ptrString("sequential"),
+			gap:           -50 * time.Millisecond,
+			expectedError: "schedule gap must be greater than or equal to 0",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			schedule, err := toSchedule(tc.schedule, tc.gap)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, schedule)
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package service
 
-import "github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/blueprint/service/model"
+import (
+	"fmt"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/blueprint/service/model"
+)
 
 // Service represents a service in the blueprint.
 type Service struct {
@@ -8,26 +11,46 @@ type Service struct {
 	Name string `mapstructure:"name"`
 
 	// Resource contains metadata or attributes associated with the service.
-	Resource map[string]string `mapstructure:"resource"`
+	Resource Attributes `mapstructure:"resource"`
 
 	// SpanDefinitions is a list of span definitions associated with the service.
 	SpanDefinitions []SpanDefinition `mapstructure:"spans"`
+
+	// DataQuality specifies the flaws of the data the service delivers, which also apply to its spans unless they override them.
+	DataQuality *ServiceDataQuality `mapstructure:"data_quality"`
+
+	// ClockSkew specifies how far the clock of the service is off, which shifts the timestamps of its spans and events.
+	ClockSkew *ClockSkew `mapstructure:"clock_skew"`
 }
 
 // To converts the service to a domain model.
-func (s *Service) To() (*model.Service, error) {
+func (s *Service) To(randomness func() float64) (*model.Service, error) {
 	tasks := make([]model.Task, 0, len(s.SpanDefinitions))
 	for _, sd := range s.SpanDefinitions {
-		t, err := sd.To()
+		t, err := sd.To(randomness)
 		if err != nil {
 			return nil, err
 		}
 		tasks = append(tasks, *t)
 	}
+	resource, err := s.Resource.To(randomness)
+	if err != nil {
+		return nil, fmt.Errorf("service %s has invalid resource attributes: %w", s.Name, err)
+	}
+	lossProbability, err := s.DataQuality.lossProbability()
+	if err != nil {
+		return nil, fmt.Errorf("service %s has invalid data quality: %w", s.Name, err)
+	}
+	clockSkew, err := s.ClockSkew.To(randomness)
+	if err != nil {
+		return nil, fmt.Errorf("service %s has invalid clock skew: %w", s.Name, err)
+	}
 	service := model.Service{
-		Name:     s.Name,
-		Resource: s.Resource,
-		Tasks:    tasks,
+		Name:            s.Name,
+		Resource:        resource,
+		Tasks:           tasks,
+		LossProbability: lossProbability,
+		ClockSkew:       clockSkew,
 	}
 	return &service, nil
 }
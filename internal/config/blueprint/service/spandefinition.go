@@ -2,8 +2,10 @@ package service
 
 import (
 	"fmt"
+	"github.com/k4ji/tracesimulationreceiver/internal/config/global"
 	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/blueprint/service/model"
 	domaintask "github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
+	"time"
 )
 
 // SpanDefinition represents a span definition in the blueprint.
@@ -24,7 +26,7 @@ type SpanDefinition struct {
 	Kind string `mapstructure:"kind"`
 
 	// Attributes contains optional attributes for the span.
-	Attributes map[string]string `mapstructure:"attributes"`
+	Attributes Attributes `mapstructure:"attributes"`
 
 	// Children is a list of child spans triggered by this span.
 	Children []SpanDefinition `mapstructure:"children"`
@@ -40,23 +42,147 @@ type SpanDefinition struct {
 
 	// ConditionalEffects specifies the effects that can occur based on certain conditions.
 	ConditionalEffects []ConditionalEffect `mapstructure:"conditional_effects"`
+
+	// Repeat specifies the repetition of the span as siblings.
+	Repeat *Repeat `mapstructure:"repeat"`
+
+	// Schedule specifies how the children are scheduled: "parallel" (default) starts each child relative to the start of this span,
+	// "sequential" starts each child relative to the end of the previous sibling.
+	Schedule *string `mapstructure:"schedule"`
+
+	// ScheduleGap specifies the wait time between the end of a child and the start of the next one in the sequential schedule.
+	ScheduleGap time.Duration `mapstructure:"schedule_gap"`
+
+	// Variants is a list of alternative attributes and children of the span, one of which is picked for each trace.
+	Variants []Variant `mapstructure:"variants"`
+
+	// IncludeProbability specifies the probability of the span and its children being included in a trace.
+	IncludeProbability *float64 `mapstructure:"include_probability"`
+
+	// Weight specifies the share of the traces rooted at the span in the mix of traces. Only for spans without a parent.
+	Weight *float64 `mapstructure:"weight"`
+
+	// Rate specifies the rate at which the traces rooted at the span are generated, apart from the mix of traces.
+	// Only for spans without a parent.
+	Rate *global.Rate `mapstructure:"rate"`
+
+	// ErrorPropagation specifies how the failure of the span propagates to its ancestors.
+	ErrorPropagation *ErrorPropagation `mapstructure:"error_propagation"`
+
+	// Retry specifies how the span is attempted again when it fails. Only for client spans with a parent.
+	Retry *Retry `mapstructure:"retry"`
+
+	// Timeout specifies the deadline of the span, after which the span ends with an error.
+	Timeout *Timeout `mapstructure:"timeout"`
+
+	// DataQuality specifies the flaws of the data the span is delivered with.
+	DataQuality *DataQuality `mapstructure:"data_quality"`
 }
 
 // To return model.Task
-func (t *SpanDefinition) To() (*model.Task, error) {
+func (t *SpanDefinition) To(randomness func() float64) (*model.Task, error) {
 	var externalID *domaintask.ExternalID
 	var parentID *domaintask.ExternalID
 	var links []*domaintask.ExternalID
 	var children []model.Task
 	var events []domaintask.Event
-	delay, err := t.Delay.To()
+	delay, err := t.Delay.To(randomness)
 	if err != nil {
 		return nil, err
 	}
-	duration, err := t.Duration.To()
+	duration, err := t.Duration.To(randomness)
 	if err != nil {
 		return nil, err
 	}
+	attributes, err := t.Attributes.To(randomness)
+	if err != nil {
+		return nil, fmt.Errorf("span %s has invalid attributes: %w", t.Name, err)
+	}
+	schedule, err := toSchedule(t.Schedule, t.ScheduleGap)
+	if err != nil {
+		return nil, fmt.Errorf("span %s has invalid schedule: %w", t.Name, err)
+	}
+	errorPropagation, err := t.ErrorPropagation.To()
+	if err != nil {
+		return nil, fmt.Errorf("span %s has invalid error propagation: %w", t.Name, err)
+	}
+	var repeat *model.Repeat
+	if t.Repeat != nil {
+		repeat, err = t.Repeat.To(randomness)
+		if err != nil {
+			return nil, fmt.Errorf("span %s has invalid repeat: %w", t.Name, err)
+		}
+		// refs must be unique, so they cannot be duplicated by repetitions
+		if ref := t.findRef(); ref != nil {
+			return nil, fmt.Errorf("span %s is repeated, so it and its children cannot have ref %s", t.Name, *ref)
+		}
+	}
+	var retry *domaintask.Retry
+	if t.Retry != nil {
+		if t.Kind != "client" {
+			return nil, fmt.Errorf("span %s is not a client span, so it cannot have retry", t.Name)
+		}
+		retry, err = t.Retry.To(randomness)
+		if err != nil {
+			return nil, fmt.Errorf("span %s has invalid retry: %w", t.Name, err)
+		}
+		// refs must be unique, so they cannot be duplicated by attempts
+		if ref := t.findRef(); ref != nil {
+			return nil, fmt.Errorf("span %s is retried, so it and its children cannot have ref %s", t.Name, *ref)
+		}
+	}
+	var timeout *domaintask.Timeout
+	if t.Timeout != nil {
+		timeout, err = t.Timeout.To()
+		if err != nil {
+			return nil, fmt.Errorf("span %s has invalid timeout: %w", t.Name, err)
+		}
+	}
+	dataQuality, err := t.DataQuality.To()
+	if err != nil {
+		return nil, fmt.Errorf("span %s has invalid data quality: %w", t.Name, err)
+	}
+	var variants *model.Variants
+	if len(t.Variants) > 0 {
+		variants, err = toVariants(t.Variants, randomness)
+		if err != nil {
+			return nil, fmt.Errorf("span %s has invalid variants: %w", t.Name, err)
+		}
+		// spans in variants are not always present, so they cannot be referenced
+		for _, v := range t.Variants {
+			for _, child := range v.Children {
+				if ref := child.findRef(); ref != nil {
+					return nil, fmt.Errorf("span %s has variants, so their children cannot have ref %s", t.Name, *ref)
+				}
+			}
+		}
+	}
+	var inclusion *model.Inclusion
+	if t.IncludeProbability != nil {
+		if *t.IncludeProbability < 0 || *t.IncludeProbability > 1 {
+			return nil, fmt.Errorf("span %s has invalid include probability: must be between 0 and 1", t.Name)
+		}
+		inclusion = &model.Inclusion{
+			Probability: *t.IncludeProbability,
+			Randomness:  randomness,
+		}
+	}
+	var flow *model.Flow
+	if t.Weight != nil || t.Rate != nil {
+		flow = &model.Flow{Weight: 1}
+		if t.Weight != nil {
+			if *t.Weight < 0 {
+				return nil, fmt.Errorf("span %s has invalid weight: must be greater than or equal to 0", t.Name)
+			}
+			flow.Weight = *t.Weight
+		}
+		if t.Rate != nil {
+			flow.Rate, err = t.Rate.To(randomness)
+			if err != nil {
+				return nil, fmt.Errorf("span %s has invalid rate: %w", t.Name, err)
+			}
+		}
+	}
 	if t.Ref != nil {
 		externalID, err = domaintask.NewExternalID(*t.Ref)
 		if err != nil {
@@ -81,7 +207,7 @@ func (t *SpanDefinition) To() (*model.Task, error) {
 	if t.Children != nil {
 		children = make([]model.Task, len(t.Children))
 		for i, child := range t.Children {
-			c, err := child.To()
+			c, err := child.To(randomness)
 			if err != nil {
 				return nil, err
 			}
@@ -91,7 +217,7 @@ func (t *SpanDefinition) To() (*model.Task, error) {
 	if t.Events != nil {
 		events = make([]domaintask.Event, len(t.Events))
 		for i, event := range t.Events {
-			d, err := event.To()
+			d, err := event.To(randomness)
 			if err != nil {
 				return nil, err
 			}
@@ -100,7 +226,7 @@ func (t *SpanDefinition) To() (*model.Task, error) {
 	}
 	var conditionalDefinitions []domaintask.ConditionalDefinition
 	for _, effect := range t.ConditionalEffects {
-		def, err := effect.To()
+		def, err := effect.To(randomness)
 		if err != nil {
 			return nil, err
 		}
@@ -113,11 +239,40 @@ func (t *SpanDefinition) To() (*model.Task, error) {
 		Delay:                 *delay,
 		Duration:              *duration,
 		Kind:                  t.Kind,
-		Attributes:            t.Attributes,
+		Attributes:            attributes,
 		Children:              children,
 		ChildOf:               parentID,
 		LinkedTo:              links,
 		Events:                events,
 		ConditionalDefinition: conditionalDefinitions,
+		Repeat:                repeat,
+		Schedule:              schedule,
+		Variants:              variants,
+		Inclusion:             inclusion,
+		Flow:                  flow,
+		ErrorPropagation:      errorPropagation,
+		Retry:                 retry,
+		Timeout:               timeout,
+		DataQuality:           dataQuality,
 	}, nil
 }
+
+// findRef returns the first ref found in the span and its descendants, including the ones in variants
+func (t *SpanDefinition) findRef() *string {
+	if t.Ref != nil {
+		return t.Ref
+	}
+	for _, child := range t.Children {
+		if ref := child.findRef(); ref != nil {
+			return ref
+		}
+	}
+	for _, v := range t.Variants {
+		for _, child := range v.Children {
+			if ref := child.findRef(); ref != nil {
+				return ref
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	domaintask "github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+func TestSpanDefinitionIncludeProbability(t *testing.T) {
+	newSpan := func(probability float64) SpanDefinition {
+		return SpanDefinition{
+			Name:     "request",
+			Kind:     "server",
+			Delay:    &Delay{Value: ptrString("0"), Mode: ptrString("absolute")},
+			Duration: &Duration{Value: ptrString("1s"), Mode: ptrString("absolute")},
+			Children: []SpanDefinition{
+				{
+					Name:               "cache refresh",
+					Kind:               "client",
+					Delay:              &Delay{Value: ptrString("0"), Mode: ptrString("absolute")},
+					Duration:           &Duration{Value: ptrString("10ms"), Mode: ptrString("absolute")},
+					IncludeProbability: &probability,
+				},
+			},
+		}
+	}
+
+	t.Run("include or drop the span", func(t *testing.T) {
+		for probability, expected := range map[float64]int{0: 0, 1: 1} {
+			sd := newSpan(probability)
+			task, err := sd.To(rand.Float64)
+			assert.NoError(t, err)
+			for i := 0; i < 10; i++ {
+				nodes, _, err := task.ToRootNodesWithResource(domaintask.NewResource("service", nil))
+				assert.NoError(t, err)
+				assert.Len(t, nodes[0].Children(), expected)
+			}
+		}
+	})
+
+	t.Run("return error if the probability is out of range", func(t *testing.T) {
+		for _, probability := range []float64{-0.1, 1.1} {
+			sd := newSpan(probability)
+			_, err := sd.To(rand.Float64)
+			assert.EqualError(t, err, "span cache refresh has invalid include probability: must be between 0 and 1")
+		}
+	})
+}
@@ -15,6 +15,16 @@ const (
 	AbsoluteMode Mode = "absolute"
 	// RelativeMode represents a relative duration
 	RelativeMode Mode = "relative"
+	// NormalMode represents a duration sampled from a normal distribution
+	NormalMode Mode = "normal"
+	// LogNormalMode represents a duration sampled from a log-normal distribution
+	LogNormalMode Mode = "lognormal"
+	// ExponentialMode represents a duration sampled from an exponential distribution
+	ExponentialMode Mode = "exponential"
+	// UniformMode represents a duration sampled from a uniform distribution
+	UniformMode Mode = "uniform"
+	// EmpiricalMode represents a duration sampled from a percentile table
+	EmpiricalMode Mode = "empirical"
 	// UnknownMode represents an unknown duration
 	UnknownMode Mode = "unknown"
 )
@@ -25,6 +35,16 @@ func FromString(mode string) Mode {
 		return AbsoluteMode
 	case "relative":
 		return RelativeMode
+	case "normal":
+		return NormalMode
+	case "lognormal":
+		return LogNormalMode
+	case "exponential":
+		return ExponentialMode
+	case "uniform":
+		return UniformMode
+	case "empirical":
+		return EmpiricalMode
 	default:
 		return UnknownMode
 	}
@@ -34,12 +54,16 @@ func FromString(mode string) Mode {
 type SpanDuration struct {
 	// Duration is the duration string
 	Duration string
-	// Mode is the duration mode (absolute or relative)
+	// Mode is the duration mode
 	Mode Mode
+	// Distributions holds the parameters of the statistical modes
+	Distributions Distributions
+	// Positive is whether the sampled durations must be greater than 0, as span durations must and delays need not
+	Positive bool
 }
 
 // To converts the SpanDuration to a taskduration.Expression
-func (d *SpanDuration) To() (taskduration.Expression, error) {
+func (d *SpanDuration) To(randomness func() float64) (taskduration.Expression, error) {
 	switch d.Mode {
 	case AbsoluteMode:
 		dur, err := time.ParseDuration(d.Duration)
@@ -61,6 +85,12 @@ func (d *SpanDuration) To() (taskduration.Expression, error) {
 			return nil, fmt.Errorf("failed to create relative span duration: %w", err)
 		}
 		return expr, nil
+	case NormalMode, LogNormalMode, ExponentialMode, UniformMode, EmpiricalMode:
+		expr, err := d.Distributions.to(d.Mode, d.Positive, randomness)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s span duration: %w", d.Mode, err)
+		}
+		return expr, nil
 	case UnknownMode:
 		return nil, fmt.Errorf("unsupported span duration mode: %s", d.Mode)
 	}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"fmt"
+	domaintask "github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
+	"time"
+)
+
+// Timeout represents the deadline of a span, relative to its start.
+type Timeout struct {
+	// After is the time from the start of the span to the deadline.
+	After time.Duration `mapstructure:"after"`
+
+	// Overrun specifies what happens to the descendants running past the deadline:
+	// "truncate" (default) ends them at the deadline and drops the ones that would start after it,
+	// "dangle" leaves them as they are.
+	Overrun string `mapstructure:"overrun"`
+}
+
+// To converts the timeout to a domain model.
+func (t *Timeout) To() (*domaintask.Timeout, error) {
+	if t.After <= 0 {
+		return nil, fmt.Errorf("after must be greater than 0")
+	}
+	var overrun domaintask.TimeoutOverrun
+	switch t.Overrun {
+	case "", "truncate":
+		overrun = domaintask.TimeoutTruncate
+	case "dangle":
+		overrun = domaintask.TimeoutDangle
+	default:
+		return nil, fmt.Errorf("unknown overrun: %s", t.Overrun)
+	}
+	timeout := domaintask.NewTimeout(t.After, overrun)
+	return &timeout, nil
+}
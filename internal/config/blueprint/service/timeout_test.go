@@ -0,0 +1,50 @@
+package service
+
+import (
+	domaintask "github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestTimeout_To(t *testing.T) {
+	testCases := []struct {
+		name          string
+		timeout       Timeout
+		expected      domaintask.Timeout
+		expectedError string
+	}{
+		{
+			name:     "default to truncate",
+			timeout:  Timeout{After: time.Second},
+			expected: domaintask.NewTimeout(time.Second, domaintask.TimeoutTruncate),
+		},
+		{
+			name:     "dangle",
+			timeout:  Timeout{After: time.Second, Overrun: "dangle"},
+			expected: domaintask.NewTimeout(time.Second, domaintask.TimeoutDangle),
+		},
+		{
+			name:          "no deadline",
+			timeout:       Timeout{Overrun: "truncate"},
+			expectedError: "after must be greater than 0",
+		},
+		{
+			name:          "unknown overrun",
+			timeout:       Timeout{After: time.Second, Overrun: "cancel"},
+			expectedError: "unknown overrun: cancel",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			timeout, err := tc.timeout.To()
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, *timeout)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"fmt"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/blueprint/service/model"
+)
+
+// Variant represents one of the alternative shapes of a span, e.g. a cache hit without a database call or a cache miss with one.
+type Variant struct {
+	// Weight is the relative likelihood of the variant being picked. Defaults to 1.
+	Weight *float64 `mapstructure:"weight"`
+
+	// Attributes are added to the attributes of the span, overriding the ones with the same key.
+	Attributes Attributes `mapstructure:"attributes"`
+
+	// Children are added after the children of the span.
+	Children []SpanDefinition `mapstructure:"children"`
+}
+
+// toVariants converts the variants to a domain model.
+func toVariants(variants []Variant, randomness func() float64) (*model.Variants, error) {
+	choices := make([]model.Variant, len(variants))
+	total := 0.0
+	for i, v := range variants {
+		weight := 1.0
+		if v.Weight != nil {
+			weight = *v.Weight
+		}
+		if weight < 0 {
+			return nil, fmt.Errorf("variant %d must have a weight greater than or equal to 0", i)
+		}
+		total += weight
+		attributes, err := v.Attributes.To(randomness)
+		if err != nil {
+			return nil, fmt.Errorf("variant %d has invalid attributes: %w", i, err)
+		}
+		var children []model.Task
+		if v.Children != nil {
+			children = make([]model.Task, len(v.Children))
+			for j, child := range v.Children {
+				c, err := child.To(randomness)
+				if err != nil {
+					return nil, err
+				}
+				children[j] = *c
+			}
+		}
+		choices[i] = model.Variant{
+			Weight:     weight,
+			Attributes: attributes,
+			Children:   children,
+		}
+	}
+	if total <= 0 {
+		return nil, fmt.Errorf("variants must have a total weight greater than 0")
+	}
+	return &model.Variants{
+		Choices:    choices,
+		Randomness: randomness,
+	}, nil
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/attribute"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/span"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestVariants(t *testing.T) {
+	newBlueprint := func(variants []Variant) *Blueprint {
+		return &Blueprint{
+			Default: DefaultValues{
+				Delay:    &Delay{Value: ptrString("0"), Mode: ptrString("absolute")},
+				Duration: &Duration{Value: ptrString("10ms"), Mode: ptrString("absolute")},
+			},
+			Services: []Service{
+				{
+					Name: "service",
+					SpanDefinitions: []SpanDefinition{
+						{
+							Name:       "GET /items",
+							Kind:       "server",
+							Attributes: Attributes{"http.request.method": "GET", "cache.hit": false},
+							Children:   []SpanDefinition{{Name: "cache lookup", Kind: "client"}},
+							Variants:   variants,
+						},
+					},
+				},
+			},
+		}
+	}
+	toSpan := func(t *testing.T, bp *Blueprint) *span.TreeNode {
+		sbp, err := bp.To(rand.Float64)
+		assert.NoError(t, err)
+		result, err := sbp.Interpret()
+		assert.NoError(t, err)
+		root, err := span.FromTaskTree(result[0], span.NewTraceID([16]byte{0x01}), time.Unix(0, 0), func() span.ID { return span.NewSpanID([8]byte{0x01}) }, nil)
+		assert.NoError(t, err)
+		return root
+	}
+	childNames := func(node *span.TreeNode) []string {
+		var names []string
+		for _, child := range node.Children() {
+			names = append(names, child.Name())
+		}
+		return names
+	}
+
+	t.Run("apply the attributes and children of the picked variant", func(t *testing.T) {
+		root := toSpan(t, newBlueprint([]Variant{
+			{Weight: ptrFloat64(0), Attributes: Attributes{"cache.hit": true}},
+			{Weight: ptrFloat64(1), Children: []SpanDefinition{{Name: "SELECT items", Kind: "client"}}},
+		}))
+		assert.Equal(t, map[string]attribute.Value{"http.request.method": attribute.String("GET"), "cache.hit": attribute.Bool(false)}, root.Attributes())
+		assert.Equal(t, []string{"cache lookup", "SELECT items"}, childNames(root))
+
+		root = toSpan(t, newBlueprint([]Variant{
+			{Weight: ptrFloat64(1), Attributes: Attributes{"cache.hit": true}},
+			{Weight: ptrFloat64(0), Children: []SpanDefinition{{Name: "SELECT items", Kind: "client"}}},
+		}))
+		assert.Equal(t, map[string]attribute.Value{"http.request.method": attribute.String("GET"), "cache.hit": attribute.Bool(true)}, root.Attributes())
+		assert.Equal(t, []string{"cache lookup"}, childNames(root))
+	})
+
+	t.Run("pick a variant for each trace", func(t *testing.T) {
+		bp := newBlueprint([]Variant{
+			{Attributes: Attributes{"cache.hit": true}},
+			{Children: []SpanDefinition{{Name: "SELECT items", Kind: "client"}}},
+		})
+		picked := make(map[int]struct{})
+		for i := 0; i < 100; i++ {
+			picked[len(toSpan(t, bp).Children())] = struct{}{}
+		}
+		assert.Equal(t, map[int]struct{}{1: {}, 2: {}}, picked)
+	})
+
+	t.Run("invalid variants", func(t *testing.T) {
+		testCases := []struct {
+			name     string
+			variants []Variant
+			expected string
+		}{
+			{
+				name:     "negative weight",
+				variants: []Variant{{Weight: ptrFloat64(-1)}, {}},
+				expected: "span GET /items has invalid variants: variant 0 must have a weight greater than or equal to 0",
+			},
+			{
+				name:     "zero total weight",
+				variants: []Variant{{Weight: ptrFloat64(0)}, {Weight: ptrFloat64(0)}},
+				expected: "span GET /items has invalid variants: variants must have a total weight greater than 0",
+			},
+			{
+				name:     "ref in variant",
+				variants: []Variant{{Children: []SpanDefinition{{Name: "SELECT items", Ref: ptrString("select_items")}}}},
+				expected: "span GET /items has variants, so their children cannot have ref select_items",
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				_, err := newBlueprint(tc.variants).To(rand.Float64)
+				assert.EqualError(t, err, tc.expected)
+			})
+		}
+	})
+}
+
+func ptrFloat64(f float64) *float64 {
+	return &f
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	configBlueprint "github.com/k4ji/tracesimulationreceiver/internal/config/blueprint"
 	"github.com/k4ji/tracesimulationreceiver/internal/config/global"
+	"github.com/k4ji/tracesimulationreceiver/internal/config/scenario"
 )
 
 // Config defines configuration for the Trace Simulation receiver.
@@ -13,6 +14,9 @@ type Config struct {
 
 	// Blueprint defines the blueprint of spans and their parameters.
 	Blueprint configBlueprint.Blueprint `mapstructure:"blueprint"`
+
+	// Scenarios defines incidents that change the spans of services during windows of time.
+	Scenarios []scenario.Scenario `mapstructure:"scenarios"`
 }
 
 // Validate checks if the receiver configuration is valid
@@ -25,5 +29,22 @@ func (cfg *Config) Validate() error {
 		return fmt.Errorf("blueprint validation failed: %w", err)
 	}
 
+	if err := scenario.Validate(cfg.Scenarios); err != nil {
+		return fmt.Errorf("scenario validation failed: %w", err)
+	}
+	if cfg.Blueprint.ServiceBlueprint != nil {
+		services := make(map[string]struct{}, len(cfg.Blueprint.ServiceBlueprint.Services))
+		for _, s := range cfg.Blueprint.ServiceBlueprint.Services {
+			services[s.Name] = struct{}{}
+		}
+		for _, sc := range cfg.Scenarios {
+			for _, o := range sc.Services {
+				if _, exists := services[o.Name]; !exists {
+					return fmt.Errorf("scenario validation failed: scenario %s refers to unknown service %s", sc.Name, o.Name)
+				}
+			}
+		}
+	}
+
 	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"github.com/k4ji/tracesimulationreceiver/internal/config/blueprint"
 	"github.com/k4ji/tracesimulationreceiver/internal/config/blueprint/service"
 	"github.com/k4ji/tracesimulationreceiver/internal/config/global"
+	"github.com/k4ji/tracesimulationreceiver/internal/config/scenario"
 	"github.com/stretchr/testify/assert"
 	"testing"
 	"time"
@@ -53,6 +54,235 @@ func TestConfig_Validate(t *testing.T) {
 		assert.Contains(t, err.Error(), "global interval must be greater than 0")
 	})
 
+	t.Run("global rate with a profile giving the rates itself", func(t *testing.T) {
+		for _, rate := range []global.Rate{
+			{Profile: &global.Profile{Type: "ramp", Ramp: &global.RampProfile{From: 1, To: 100, Duration: time.Minute}}},
+			{Profile: &global.Profile{Type: "diurnal", Diurnal: &global.DiurnalProfile{Min: 1, Max: 100, Period: time.Hour, Peak: 30 * time.Minute}}},
+		} {
+			assert.NoError(t, rate.Validate())
+			controller, err := rate.To(func() float64 { return 0.5 })
+			assert.NoError(t, err)
+			// the rate at the start is given by the profile, not by traces_per_second
+			assert.Equal(t, 1.0, controller.Profile().Rate(0))
+		}
+	})
+
+	t.Run("invalid global rate", func(t *testing.T) {
+		testCases := []struct {
+			rate     global.Rate
+			expected string
+		}{
+			{
+				rate:     global.Rate{TracesPerSecond: 0},
+				expected: "global validation failed: global rate traces_per_second must be greater than 0",
+			},
+			{
+				rate:     global.Rate{TracesPerSecond: 10, Arrival: "burst"},
+				expected: "global validation failed: global rate arrival must be either 'constant' or 'poisson', got burst",
+			},
+			{
+				rate:     global.Rate{Profile: &global.Profile{Type: "ramp"}},
+				expected: "global validation failed: global rate has invalid profile: profile type is 'ramp' but ramp is not set",
+			},
+			{
+				rate: global.Rate{
+					TracesPerSecond: 10,
+					Profile:         &global.Profile{Type: "diurnal", Diurnal: &global.DiurnalProfile{Min: 1, Max: 20}},
+				},
+				expected: "global validation failed: global rate traces_per_second cannot be set with a diurnal profile, which gives the rates itself",
+			},
+			{
+				rate:     global.Rate{TracesPerSecond: -1, Profile: &global.Profile{Type: "step", Step: &global.StepProfile{}}},
+				expected: "global validation failed: global rate traces_per_second must be greater than or equal to 0 with a step profile",
+			},
+			{
+				rate:     global.Rate{Profile: &global.Profile{Type: "step", Step: &global.StepProfile{}}},
+				expected: "global validation failed: global rate has invalid profile: rate must be a positive number, got 0",
+			},
+			{
+				rate: global.Rate{
+					TracesPerSecond: 10,
+					Profile:         &global.Profile{Type: "step", Step: &global.StepProfile{Steps: []global.Step{{After: time.Minute, TracesPerSecond: 0}}}},
+				},
+				expected: "global validation failed: global rate has invalid profile: invalid step at 1m0s: the rate of the last step must be greater than 0",
+			},
+			{
+				rate:     global.Rate{TracesPerSecond: 10, Profile: &global.Profile{Type: "sawtooth"}},
+				expected: "global validation failed: global rate has invalid profile: unknown profile type: sawtooth",
+			},
+			{
+				rate: global.Rate{
+					TracesPerSecond: 10,
+					Profile:         &global.Profile{Type: "burst", Burst: &global.BurstProfile{TracesPerSecond: 100, Every: time.Minute}},
+				},
+				expected: "global validation failed: global rate has invalid profile: burst duration must be greater than 0 and less than or equal to the period",
+			},
+		}
+		for _, tc := range testCases {
+			cfg := Config{
+				Global: global.Default(),
+			}
+			cfg.Global.Rate = &tc.rate
+			err := cfg.Validate()
+			assert.EqualError(t, err, tc.expected)
+		}
+	})
+
+	t.Run("invalid global backfill", func(t *testing.T) {
+		testCases := []struct {
+			backfill global.Backfill
+			expected string
+		}{
+			{
+				backfill: global.Backfill{},
+				expected: "global validation failed: global backfill has invalid start_time: parsing time \"\" as \"2006-01-02T15:04:05Z07:00\": cannot parse \"\" as \"2006\"",
+			},
+			{
+				backfill: global.Backfill{StartTime: "2025-01-01T00:00:00Z", EndTime: ptrString("2025-01-01")},
+				expected: "global validation failed: global backfill has invalid end_time: parsing time \"2025-01-01\" as \"2006-01-02T15:04:05Z07:00\": cannot parse \"\" as \"T\"",
+			},
+			{
+				backfill: global.Backfill{StartTime: "2025-01-01T00:00:00Z", EndTime: ptrString("2025-01-01T00:00:00Z")},
+				expected: "global validation failed: global backfill end_time must be after start_time",
+			},
+			{
+				backfill: global.Backfill{StartTime: "2025-01-01T00:00:00Z", EndTime: ptrString("2025-01-02T00:00:00Z"), Then: "live"},
+				expected: "global validation failed: global backfill end_time cannot be set when then is 'live'",
+			},
+			{
+				backfill: global.Backfill{StartTime: "2025-01-01T00:00:00Z", Then: "loop"},
+				expected: "global validation failed: global backfill then must be either 'stop' or 'live', got loop",
+			},
+			{
+				backfill: global.Backfill{StartTime: "2999-01-01T00:00:00Z"},
+				expected: "global validation failed: global backfill start_time must not be in the future",
+			},
+			{
+				backfill: global.Backfill{StartTime: "2025-01-01T00:00:00Z", EndTime: ptrString("2999-01-01T00:00:00Z")},
+				expected: "global validation failed: global backfill end_time must not be in the future",
+			},
+		}
+		for _, tc := range testCases {
+			cfg := Config{
+				Global: global.Default(),
+			}
+			cfg.Global.Backfill = &tc.backfill
+			err := cfg.Validate()
+			assert.EqualError(t, err, tc.expected)
+		}
+	})
+
+	t.Run("invalid global delivery", func(t *testing.T) {
+		testCases := []struct {
+			delivery global.Delivery
+			expected string
+		}{
+			{
+				delivery: global.Delivery{Mode: "streaming"},
+				expected: "global validation failed: global delivery mode must be either 'trace' or 'realtime', got streaming",
+			},
+			{
+				delivery: global.Delivery{BatchInterval: time.Second},
+				expected: "global validation failed: global delivery batch_interval can only be set when mode is 'realtime'",
+			},
+			{
+				delivery: global.Delivery{Mode: "realtime", BatchInterval: -time.Second},
+				expected: "global validation failed: global delivery batch_interval must be greater than or equal to 0",
+			},
+			{
+				delivery: global.Delivery{Delay: &global.DeliveryDelay{Min: -time.Second}},
+				expected: "global validation failed: global delivery delay min must be greater than or equal to 0",
+			},
+			{
+				delivery: global.Delivery{Delay: &global.DeliveryDelay{Min: 2 * time.Second, Max: time.Second}},
+				expected: "global validation failed: global delivery delay min must be less than or equal to max",
+			},
+			{
+				delivery: global.Delivery{ShuffleParts: -1},
+				expected: "global validation failed: global delivery shuffle_parts must be greater than or equal to 0",
+			},
+		}
+		for _, tc := range testCases {
+			cfg := Config{
+				Global: global.Default(),
+			}
+			cfg.Global.Delivery = &tc.delivery
+			err := cfg.Validate()
+			assert.EqualError(t, err, tc.expected)
+		}
+	})
+
+	t.Run("invalid scenarios", func(t *testing.T) {
+		testCases := []struct {
+			scenario scenario.Scenario
+			expected string
+		}{
+			{
+				scenario: scenario.Scenario{Name: "outage", Services: []scenario.ServiceOverlay{{Name: "service1", ErrorRate: 0.5}}},
+				expected: "scenario validation failed: scenario outage is invalid: scenario must have either from/to or start_time/end_time",
+			},
+			{
+				scenario: scenario.Scenario{Name: "outage", From: ptrDuration(10 * time.Minute), StartTime: ptrString("2025-01-01T00:00:00Z"), Services: []scenario.ServiceOverlay{{Name: "service1", ErrorRate: 0.5}}},
+				expected: "scenario validation failed: scenario outage is invalid: scenario must have either from/to or start_time/end_time",
+			},
+			{
+				scenario: scenario.Scenario{Name: "outage", From: ptrDuration(10 * time.Minute), To: ptrDuration(5 * time.Minute), Services: []scenario.ServiceOverlay{{Name: "service1", ErrorRate: 0.5}}},
+				expected: "scenario validation failed: scenario outage is invalid: to must be after from",
+			},
+			{
+				scenario: scenario.Scenario{Name: "outage", StartTime: ptrString("yesterday"), Services: []scenario.ServiceOverlay{{Name: "service1", ErrorRate: 0.5}}},
+				expected: "scenario validation failed: scenario outage is invalid: invalid start_time: parsing time \"yesterday\" as \"2006-01-02T15:04:05Z07:00\": cannot parse \"yesterday\" as \"2006\"",
+			},
+			{
+				scenario: scenario.Scenario{Name: "outage", From: ptrDuration(0)},
+				expected: "scenario validation failed: scenario outage is invalid: services must not be empty",
+			},
+			{
+				scenario: scenario.Scenario{Name: "outage", From: ptrDuration(0), Services: []scenario.ServiceOverlay{{Name: "service1", ErrorRate: 1.5}}},
+				expected: "scenario validation failed: scenario outage is invalid: error_rate of service service1 must be between 0 and 1",
+			},
+			{
+				scenario: scenario.Scenario{Name: "outage", From: ptrDuration(0), Services: []scenario.ServiceOverlay{{Name: "service1", LatencyMultiplier: ptrFloat64(0)}}},
+				expected: "scenario validation failed: scenario outage is invalid: latency_multiplier of service service1 must be greater than or equal to 1",
+			},
+			{
+				scenario: scenario.Scenario{Name: "outage", From: ptrDuration(0), Services: []scenario.ServiceOverlay{{Name: "service2", ErrorRate: 0.5}}},
+				expected: "scenario validation failed: scenario outage refers to unknown service service2",
+			},
+		}
+		for _, tc := range testCases {
+			cfg := Config{
+				Global: global.Default(),
+				Blueprint: blueprint.Blueprint{
+					Type: "service",
+					ServiceBlueprint: &service.Blueprint{
+						Services: []service.Service{
+							{
+								Name: "service1",
+								SpanDefinitions: []service.SpanDefinition{
+									{
+										Name: "span1",
+										Delay: &service.Delay{
+											Value: ptrString("0"),
+											Mode:  ptrString("absolute"),
+										},
+										Duration: &service.Duration{
+											Value: ptrString("1ns"),
+											Mode:  ptrString("absolute"),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				Scenarios: []scenario.Scenario{tc.scenario},
+			}
+			err := cfg.Validate()
+			assert.EqualError(t, err, tc.expected)
+		}
+	})
+
 	t.Run("duplicate span refs", func(t *testing.T) {
 		duplicateRef := "span-ref"
 		cfg := Config{
@@ -173,3 +403,11 @@ func TestConfig_Validate(t *testing.T) {
 func ptrString(s string) *string {
 	return &s
 }
+
+func ptrDuration(d time.Duration) *time.Duration {
+	return &d
+}
+
+func ptrFloat64(f float64) *float64 {
+	return &f
+}
@@ -0,0 +1,76 @@
+package global
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	BackfillThenStop = "stop"
+	BackfillThenLive = "live"
+)
+
+const DefaultBackfillThen = BackfillThenStop
+
+// Backfill defines a range of past time whose traces are generated as fast as the next consumer accepts them.
+type Backfill struct {
+	// StartTime specifies the wall clock time from which traces are generated, in RFC 3339 format. Must not be in the future.
+	StartTime string `mapstructure:"start_time"`
+	// EndTime specifies the wall clock time until which traces are generated, in RFC 3339 format.
+	// Defaults to the time the receiver starts, which is also the latest end. Only for "stop", since "live" generates traces until the receiver shuts down.
+	EndTime *string `mapstructure:"end_time"`
+	// Then specifies what the receiver does once the backfill catches up: "stop" or "live".
+	Then string `mapstructure:"then"`
+}
+
+func (b *Backfill) Validate() error {
+	start, end, err := b.Range()
+	if err != nil {
+		return fmt.Errorf("backfill %w", err)
+	}
+	// a backfill of the future would wait for the time to come instead of catching up
+	now := time.Now()
+	if start.After(now) {
+		return fmt.Errorf("backfill start_time must not be in the future")
+	}
+	if end != nil && end.After(now) {
+		return fmt.Errorf("backfill end_time must not be in the future")
+	}
+	switch b.ThenOrDefault() {
+	case BackfillThenStop:
+	case BackfillThenLive:
+		if b.EndTime != nil {
+			return fmt.Errorf("backfill end_time cannot be set when then is 'live'")
+		}
+	default:
+		return fmt.Errorf("backfill then must be either 'stop' or 'live', got %s", b.Then)
+	}
+	return nil
+}
+
+// Range returns the start and the end of the backfill. The end is nil if not set.
+func (b *Backfill) Range() (time.Time, *time.Time, error) {
+	start, err := time.Parse(time.RFC3339, b.StartTime)
+	if err != nil {
+		return time.Time{}, nil, fmt.Errorf("has invalid start_time: %w", err)
+	}
+	if b.EndTime == nil {
+		return start, nil, nil
+	}
+	end, err := time.Parse(time.RFC3339, *b.EndTime)
+	if err != nil {
+		return time.Time{}, nil, fmt.Errorf("has invalid end_time: %w", err)
+	}
+	if !end.After(start) {
+		return time.Time{}, nil, fmt.Errorf("end_time must be after start_time")
+	}
+	return start, &end, nil
+}
+
+// ThenOrDefault returns what the receiver does once the backfill catches up, applying the default.
+func (b *Backfill) ThenOrDefault() string {
+	if b.Then == "" {
+		return DefaultBackfillThen
+	}
+	return b.Then
+}
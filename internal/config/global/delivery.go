@@ -0,0 +1,85 @@
+package global
+
+import (
+	"fmt"
+	"github.com/k4ji/tracesimulationreceiver/internal/delivery"
+	"time"
+)
+
+const (
+	DeliveryModeTrace    = "trace"
+	DeliveryModeRealtime = "realtime"
+)
+
+const DefaultDeliveryMode = DeliveryModeTrace
+
+// Delivery defines how the generated spans are handed to the next consumer.
+type Delivery struct {
+	// Mode specifies when the spans are delivered: "trace" delivers each trace at once, already finished and shifted into the past,
+	// and "realtime" starts each trace at its arrival time and delivers each span when it ends.
+	Mode string `mapstructure:"mode"`
+	// BatchInterval specifies the interval at which the spans ending since the start of the trace are delivered together in the realtime mode.
+	// If 0, each span is delivered when it ends.
+	BatchInterval time.Duration `mapstructure:"batch_interval"`
+	// Delay specifies the random delay added to the delivery of each payload, which makes the payloads arrive late and out of order.
+	Delay *DeliveryDelay `mapstructure:"delay"`
+	// SplitByResource specifies whether the spans of each resource are delivered in a separate payload,
+	// as if each service shipped its spans independently.
+	SplitByResource bool `mapstructure:"split_by_resource"`
+	// ShuffleParts specifies the number of payloads the spans of a trace, or of a batch in the realtime mode, are randomly scattered across.
+	// If 0 or 1, the spans are not shuffled.
+	ShuffleParts int `mapstructure:"shuffle_parts"`
+}
+
+// DeliveryDelay defines the range the delivery delay of each payload is drawn from uniformly.
+type DeliveryDelay struct {
+	Min time.Duration `mapstructure:"min"`
+	Max time.Duration `mapstructure:"max"`
+}
+
+func (d *Delivery) Validate() error {
+	switch d.ModeOrDefault() {
+	case DeliveryModeTrace:
+		if d.BatchInterval != 0 {
+			return fmt.Errorf("delivery batch_interval can only be set when mode is 'realtime'")
+		}
+	case DeliveryModeRealtime:
+		if d.BatchInterval < 0 {
+			return fmt.Errorf("delivery batch_interval must be greater than or equal to 0")
+		}
+	default:
+		return fmt.Errorf("delivery mode must be either 'trace' or 'realtime', got %s", d.Mode)
+	}
+	if d.Delay != nil {
+		if d.Delay.Min < 0 {
+			return fmt.Errorf("delivery delay min must be greater than or equal to 0")
+		}
+		if d.Delay.Min > d.Delay.Max {
+			return fmt.Errorf("delivery delay min must be less than or equal to max")
+		}
+	}
+	if d.ShuffleParts < 0 {
+		return fmt.Errorf("delivery shuffle_parts must be greater than or equal to 0")
+	}
+	return nil
+}
+
+// Shaper returns the shaper of the payloads delivered to the next consumer. If nil, the payloads are not reshaped.
+func (d *Delivery) Shaper(randomness func() float64) *delivery.Shaper {
+	if d.Delay == nil && !d.SplitByResource && d.ShuffleParts < 2 {
+		return nil
+	}
+	var minDelay, maxDelay time.Duration
+	if d.Delay != nil {
+		minDelay, maxDelay = d.Delay.Min, d.Delay.Max
+	}
+	return delivery.NewShaper(d.SplitByResource, d.ShuffleParts, minDelay, maxDelay, randomness)
+}
+
+// ModeOrDefault returns the delivery mode, applying the default.
+func (d *Delivery) ModeOrDefault() string {
+	if d.Mode == "" {
+		return DefaultDeliveryMode
+	}
+	return d.Mode
+}
@@ -14,6 +14,15 @@ type Global struct {
 	Interval time.Duration `mapstructure:"interval"`
 	// EndTimeOffset specifies the base offset for the end time of spans.
 	EndTimeOffset time.Duration `mapstructure:"end_time_offset"`
+	// Rate specifies the rate at which traces are generated. If set, it is used instead of Interval.
+	Rate *Rate `mapstructure:"rate"`
+	// Seed specifies the seed of all the randomness in the simulation, which makes the simulation reproducible.
+	// If nil, a seed is derived from the current time.
+	Seed *int64 `mapstructure:"seed"`
+	// Backfill specifies a range of past time whose traces are generated before the receiver stops or runs live.
+	Backfill *Backfill `mapstructure:"backfill"`
+	// Delivery specifies how the generated spans are handed to the next consumer. If nil, each trace is delivered at once.
+	Delivery *Delivery `mapstructure:"delivery"`
 }
 
 func Validate(g *Global) error {
@@ -24,6 +33,21 @@ func Validate(g *Global) error {
 	if g.EndTimeOffset < -365*24*time.Hour || g.EndTimeOffset > 365*24*time.Hour {
 		return fmt.Errorf("global end_time_offset must be between -1 year and +1 year")
 	}
+	if g.Rate != nil {
+		if err := g.Rate.Validate(); err != nil {
+			return fmt.Errorf("global %w", err)
+		}
+	}
+	if g.Backfill != nil {
+		if err := g.Backfill.Validate(); err != nil {
+			return fmt.Errorf("global %w", err)
+		}
+	}
+	if g.Delivery != nil {
+		if err := g.Delivery.Validate(); err != nil {
+			return fmt.Errorf("global %w", err)
+		}
+	}
 	return nil
 }
 
@@ -0,0 +1,110 @@
+package global
+
+import (
+	"fmt"
+	"github.com/k4ji/tracesimulationreceiver/internal/ratecontrol"
+	"time"
+)
+
+const DefaultDiurnalPeriod = 24 * time.Hour
+
+// Profile defines how the rate changes over the time elapsed since the receiver starts.
+type Profile struct {
+	// Type specifies the type of the profile: "ramp", "step", "diurnal" or "burst".
+	Type string `mapstructure:"type"`
+	// Ramp changes the rate linearly.
+	Ramp *RampProfile `mapstructure:"ramp"`
+	// Step changes the rate in steps.
+	Step *StepProfile `mapstructure:"step"`
+	// Diurnal changes the rate along a sinusoidal curve.
+	Diurnal *DiurnalProfile `mapstructure:"diurnal"`
+	// Burst raises the rate periodically.
+	Burst *BurstProfile `mapstructure:"burst"`
+}
+
+// RampProfile defines a linear change of the rate, which is kept after the change.
+type RampProfile struct {
+	// From specifies the rate at the start.
+	From float64 `mapstructure:"from"`
+	// To specifies the rate at the end of the ramp.
+	To float64 `mapstructure:"to"`
+	// Duration specifies the duration of the ramp.
+	Duration time.Duration `mapstructure:"duration"`
+}
+
+// StepProfile defines changes of the rate from the base rate at given times.
+type StepProfile struct {
+	// Steps is a list of changes of the rate.
+	Steps []Step `mapstructure:"steps"`
+}
+
+// Step defines a change of the rate.
+type Step struct {
+	// After specifies the time elapsed since the start at which the rate changes.
+	After time.Duration `mapstructure:"after"`
+	// TracesPerSecond specifies the rate after the change.
+	TracesPerSecond float64 `mapstructure:"traces_per_second"`
+}
+
+// DiurnalProfile defines a sinusoidal curve of the rate.
+type DiurnalProfile struct {
+	// Min specifies the minimum rate.
+	Min float64 `mapstructure:"min"`
+	// Max specifies the maximum rate.
+	Max float64 `mapstructure:"max"`
+	// Period specifies the period of the curve. Defaults to 24h.
+	Period time.Duration `mapstructure:"period"`
+	// Peak specifies the time elapsed since the start at which the rate reaches the maximum.
+	Peak time.Duration `mapstructure:"peak"`
+}
+
+// BurstProfile defines periodic spikes of the rate from the base rate.
+type BurstProfile struct {
+	// TracesPerSecond specifies the rate during a burst.
+	TracesPerSecond float64 `mapstructure:"traces_per_second"`
+	// Every specifies the period of the bursts. A burst starts at the end of every period.
+	Every time.Duration `mapstructure:"every"`
+	// Duration specifies the duration of a burst.
+	Duration time.Duration `mapstructure:"duration"`
+}
+
+// hasAbsoluteRates returns whether the profile gives the rates itself instead of changing them from the base rate.
+func (p *Profile) hasAbsoluteRates() bool {
+	return p != nil && (p.Type == "ramp" || p.Type == "diurnal")
+}
+
+// to converts the profile to a domain model on top of the base rate, which ramp and diurnal profiles do not use.
+func (p *Profile) to(base float64) (ratecontrol.Profile, error) {
+	switch p.Type {
+	case "ramp":
+		if p.Ramp == nil {
+			return nil, fmt.Errorf("profile type is 'ramp' but ramp is not set")
+		}
+		return ratecontrol.NewRampProfile(p.Ramp.From, p.Ramp.To, p.Ramp.Duration)
+	case "step":
+		if p.Step == nil {
+			return nil, fmt.Errorf("profile type is 'step' but step is not set")
+		}
+		steps := make([]ratecontrol.Step, len(p.Step.Steps))
+		for i, s := range p.Step.Steps {
+			steps[i] = ratecontrol.NewStep(s.After, s.TracesPerSecond)
+		}
+		return ratecontrol.NewStepProfile(base, steps)
+	case "diurnal":
+		if p.Diurnal == nil {
+			return nil, fmt.Errorf("profile type is 'diurnal' but diurnal is not set")
+		}
+		period := p.Diurnal.Period
+		if period == 0 {
+			period = DefaultDiurnalPeriod
+		}
+		return ratecontrol.NewDiurnalProfile(p.Diurnal.Min, p.Diurnal.Max, period, p.Diurnal.Peak)
+	case "burst":
+		if p.Burst == nil {
+			return nil, fmt.Errorf("profile type is 'burst' but burst is not set")
+		}
+		return ratecontrol.NewBurstProfile(base, p.Burst.TracesPerSecond, p.Burst.Every, p.Burst.Duration)
+	default:
+		return nil, fmt.Errorf("unknown profile type: %s", p.Type)
+	}
+}
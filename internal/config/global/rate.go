@@ -0,0 +1,70 @@
+package global
+
+import (
+	"fmt"
+	"github.com/k4ji/tracesimulationreceiver/internal/ratecontrol"
+)
+
+const DefaultArrival = "constant"
+
+// Rate defines the rate at which traces are generated.
+type Rate struct {
+	// TracesPerSecond specifies the target number of traces generated per second.
+	// With a step or burst profile, it is the base rate that the profile changes from, which can be 0.
+	// It cannot be set with a ramp or diurnal profile, which give the rates themselves.
+	TracesPerSecond float64 `mapstructure:"traces_per_second"`
+	// Arrival specifies how the times between traces are distributed: "constant" or "poisson".
+	Arrival string `mapstructure:"arrival"`
+	// Profile specifies how the rate changes over time.
+	Profile *Profile `mapstructure:"profile"`
+}
+
+func (r *Rate) Validate() error {
+	switch {
+	case r.Profile.hasAbsoluteRates():
+		if r.TracesPerSecond != 0 {
+			return fmt.Errorf("rate traces_per_second cannot be set with a %s profile, which gives the rates itself", r.Profile.Type)
+		}
+	case r.Profile != nil:
+		// the profile can start from 0 as long as it rises later, which the profile checks
+		if r.TracesPerSecond < 0 {
+			return fmt.Errorf("rate traces_per_second must be greater than or equal to 0 with a %s profile", r.Profile.Type)
+		}
+	case r.TracesPerSecond <= 0:
+		return fmt.Errorf("rate traces_per_second must be greater than 0")
+	}
+	if ratecontrol.ArrivalFromString(r.arrival()) == ratecontrol.ArrivalUnknown {
+		return fmt.Errorf("rate arrival must be either 'constant' or 'poisson', got %s", r.Arrival)
+	}
+	if _, err := r.profile(); err != nil {
+		return fmt.Errorf("rate has invalid profile: %w", err)
+	}
+	return nil
+}
+
+// To converts the rate to a rate controller.
+func (r *Rate) To(randomness func() float64) (*ratecontrol.Controller, error) {
+	profile, err := r.profile()
+	if err != nil {
+		return nil, fmt.Errorf("invalid profile: %w", err)
+	}
+	return ratecontrol.NewController(
+		profile,
+		ratecontrol.ArrivalFromString(r.arrival()),
+		randomness,
+	)
+}
+
+func (r *Rate) arrival() string {
+	if r.Arrival == "" {
+		return DefaultArrival
+	}
+	return r.Arrival
+}
+
+func (r *Rate) profile() (ratecontrol.Profile, error) {
+	if r.Profile == nil {
+		return ratecontrol.NewConstantProfile(r.TracesPerSecond)
+	}
+	return r.Profile.to(r.TracesPerSecond)
+}
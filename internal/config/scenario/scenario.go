@@ -0,0 +1,135 @@
+package scenario
+
+import (
+	"fmt"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/scenario"
+	"math/rand"
+	"time"
+)
+
+// DefaultErrorMessage is the message of the spans marked as failed by a scenario if not specified.
+const DefaultErrorMessage = "simulated incident"
+
+// Scenario defines an incident that changes the spans of services during a window.
+// The window is given either by from/to or by start_time/end_time.
+type Scenario struct {
+	// Name is the name of the scenario.
+	Name string `mapstructure:"name"`
+	// From specifies the time elapsed since the receiver starts at which the scenario starts.
+	From *time.Duration `mapstructure:"from"`
+	// To specifies the time elapsed since the receiver starts at which the scenario ends.
+	To *time.Duration `mapstructure:"to"`
+	// StartTime specifies the wall clock time at which the scenario starts, in RFC 3339 format.
+	StartTime *string `mapstructure:"start_time"`
+	// EndTime specifies the wall clock time at which the scenario ends, in RFC 3339 format.
+	EndTime *string `mapstructure:"end_time"`
+	// Services is a list of changes to the spans of services.
+	Services []ServiceOverlay `mapstructure:"services"`
+}
+
+// ServiceOverlay defines changes to the spans of a service.
+type ServiceOverlay struct {
+	// Name is the name of the service.
+	Name string `mapstructure:"name"`
+	// ErrorRate specifies the probability of a span of the service being marked as failed.
+	ErrorRate float64 `mapstructure:"error_rate"`
+	// ErrorMessage specifies the message of the spans marked as failed.
+	ErrorMessage *string `mapstructure:"error_message"`
+	// LatencyMultiplier specifies the factor the durations of the spans of the service are multiplied by, which must be at least 1.
+	LatencyMultiplier *float64 `mapstructure:"latency_multiplier"`
+}
+
+// Validate checks the scenarios for errors.
+func Validate(scenarios []Scenario) error {
+	// the randomness is only used while simulating, so any source is fine for validation
+	_, err := To(scenarios, rand.Float64)
+	return err
+}
+
+// To converts the scenarios to a timeline.
+func To(scenarios []Scenario, randomness func() float64) (*scenario.Timeline, error) {
+	converted := make([]scenario.Scenario, len(scenarios))
+	for i, s := range scenarios {
+		c, err := s.to(randomness)
+		if err != nil {
+			return nil, fmt.Errorf("scenario %s is invalid: %w", s.Name, err)
+		}
+		converted[i] = *c
+	}
+	return scenario.NewTimeline(converted), nil
+}
+
+func (s *Scenario) to(randomness func() float64) (*scenario.Scenario, error) {
+	window, err := s.window()
+	if err != nil {
+		return nil, err
+	}
+	if len(s.Services) == 0 {
+		return nil, fmt.Errorf("services must not be empty")
+	}
+	overlays := make([]scenario.Overlay, len(s.Services))
+	for i, o := range s.Services {
+		if o.Name == "" {
+			return nil, fmt.Errorf("service name cannot be empty")
+		}
+		if o.ErrorRate < 0 || o.ErrorRate > 1 {
+			return nil, fmt.Errorf("error_rate of service %s must be between 0 and 1", o.Name)
+		}
+		errorMessage := DefaultErrorMessage
+		if o.ErrorMessage != nil {
+			errorMessage = *o.ErrorMessage
+		}
+		latencyMultiplier := 1.0
+		if o.LatencyMultiplier != nil {
+			if *o.LatencyMultiplier < 1 {
+				return nil, fmt.Errorf("latency_multiplier of service %s must be greater than or equal to 1", o.Name)
+			}
+			latencyMultiplier = *o.LatencyMultiplier
+		}
+		overlays[i] = scenario.NewOverlay(o.Name, o.ErrorRate, errorMessage, latencyMultiplier, randomness)
+	}
+	c := scenario.NewScenario(s.Name, *window, overlays)
+	return &c, nil
+}
+
+func (s *Scenario) window() (*scenario.Window, error) {
+	elapsed := s.From != nil || s.To != nil
+	wallClock := s.StartTime != nil || s.EndTime != nil
+	switch {
+	case elapsed && !wallClock:
+		if s.From == nil {
+			return nil, fmt.Errorf("from is required when to is set")
+		}
+		if *s.From < 0 {
+			return nil, fmt.Errorf("from must be greater than or equal to 0")
+		}
+		if s.To != nil && *s.To <= *s.From {
+			return nil, fmt.Errorf("to must be after from")
+		}
+		w := scenario.NewElapsedWindow(*s.From, s.To)
+		return &w, nil
+	case wallClock && !elapsed:
+		if s.StartTime == nil {
+			return nil, fmt.Errorf("start_time is required when end_time is set")
+		}
+		start, err := time.Parse(time.RFC3339, *s.StartTime)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start_time: %w", err)
+		}
+		var end *time.Time
+		if s.EndTime != nil {
+			e, err := time.Parse(time.RFC3339, *s.EndTime)
+			if err != nil {
+				return nil, fmt.Errorf("invalid end_time: %w", err)
+			}
+			if !e.After(start) {
+				return nil, fmt.Errorf("end_time must be after start_time")
+			}
+			end = &e
+		}
+		w := scenario.NewWallClockWindow(start, end)
+		return &w, nil
+	default:
+		return nil, fmt.Errorf("scenario must have either from/to or start_time/end_time")
+	}
+}
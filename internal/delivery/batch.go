@@ -0,0 +1,92 @@
+package delivery
+
+import (
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"sort"
+	"time"
+)
+
+// Batch is a part of traces that is delivered at a time.
+type Batch struct {
+	// At is the time at which the batch is delivered.
+	At time.Time
+	// Traces holds the spans of the batch, grouped by the same resources and scopes as the original traces.
+	Traces ptrace.Traces
+}
+
+// SplitByEndTime splits the traces into batches that are delivered as their spans end.
+// The spans ending in the same interval since the start are delivered together at the end of the interval, as
+// instrumentations exporting spans periodically do. If the interval is 0, each span is delivered when it ends.
+func SplitByEndTime(td ptrace.Traces, start time.Time, interval time.Duration) []Batch {
+	batches := make(map[time.Time]*builder)
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			ss := rs.ScopeSpans().At(j)
+			for k := 0; k < ss.Spans().Len(); k++ {
+				span := ss.Spans().At(k)
+				at := deliveryTime(span.EndTimestamp().AsTime(), start, interval)
+				b, ok := batches[at]
+				if !ok {
+					b = newBuilder()
+					batches[at] = b
+				}
+				span.CopyTo(b.scopeSpansOf(rs, i, ss, j).Spans().AppendEmpty())
+			}
+		}
+	}
+
+	result := make([]Batch, 0, len(batches))
+	for at, b := range batches {
+		result = append(result, Batch{At: at, Traces: b.traces})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].At.Before(result[j].At)
+	})
+	return result
+}
+
+// deliveryTime returns the end of the interval the end time falls in
+func deliveryTime(end time.Time, start time.Time, interval time.Duration) time.Time {
+	elapsed := end.Sub(start)
+	if interval <= 0 || elapsed <= 0 {
+		return end
+	}
+	intervals := (elapsed + interval - 1) / interval
+	return start.Add(intervals * interval)
+}
+
+// builder builds traces keeping the resources and scopes of the spans copied from the original traces
+type builder struct {
+	traces ptrace.Traces
+	// resourceSpans and scopeSpans map the indexes in the original traces to the ones in the built traces
+	resourceSpans map[int]ptrace.ResourceSpans
+	scopeSpans    map[[2]int]ptrace.ScopeSpans
+}
+
+func newBuilder() *builder {
+	return &builder{
+		traces:        ptrace.NewTraces(),
+		resourceSpans: make(map[int]ptrace.ResourceSpans),
+		scopeSpans:    make(map[[2]int]ptrace.ScopeSpans),
+	}
+}
+
+func (b *builder) scopeSpansOf(rs ptrace.ResourceSpans, i int, ss ptrace.ScopeSpans, j int) ptrace.ScopeSpans {
+	if built, ok := b.scopeSpans[[2]int{i, j}]; ok {
+		return built
+	}
+	builtResourceSpans, ok := b.resourceSpans[i]
+	if !ok {
+		builtResourceSpans = b.traces.ResourceSpans().AppendEmpty()
+		rs.Resource().CopyTo(builtResourceSpans.Resource())
+		builtResourceSpans.SetSchemaUrl(rs.SchemaUrl())
+		b.resourceSpans[i] = builtResourceSpans
+	}
+	built := builtResourceSpans.ScopeSpans().AppendEmpty()
+	ss.Scope().CopyTo(built.Scope())
+	built.SetSchemaUrl(ss.SchemaUrl())
+	b.scopeSpans[[2]int{i, j}] = built
+	return built
+}
@@ -0,0 +1,78 @@
+package delivery
+
+import (
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"testing"
+	"time"
+)
+
+func TestSplitByEndTime(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	td := ptrace.NewTraces()
+	for _, service := range []struct {
+		name  string
+		spans []string
+		ends  []time.Duration
+	}{
+		{name: "frontend", spans: []string{"request"}, ends: []time.Duration{100 * time.Millisecond}},
+		{name: "backend", spans: []string{"query", "render"}, ends: []time.Duration{30 * time.Millisecond, 60 * time.Millisecond}},
+	} {
+		rs := td.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr("service.name", service.name)
+		ss := rs.ScopeSpans().AppendEmpty()
+		ss.Scope().SetName("tracesimulator")
+		for i, name := range service.spans {
+			span := ss.Spans().AppendEmpty()
+			span.SetName(name)
+			span.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+			span.SetEndTimestamp(pcommon.NewTimestampFromTime(start.Add(service.ends[i])))
+		}
+	}
+
+	// spanNames returns the names of the spans in the batch by service, checking that the scopes are kept
+	spanNames := func(t *testing.T, td ptrace.Traces) map[string][]string {
+		names := make(map[string][]string)
+		for i := 0; i < td.ResourceSpans().Len(); i++ {
+			rs := td.ResourceSpans().At(i)
+			service, _ := rs.Resource().Attributes().Get("service.name")
+			for j := 0; j < rs.ScopeSpans().Len(); j++ {
+				ss := rs.ScopeSpans().At(j)
+				assert.Equal(t, "tracesimulator", ss.Scope().Name())
+				for k := 0; k < ss.Spans().Len(); k++ {
+					names[service.Str()] = append(names[service.Str()], ss.Spans().At(k).Name())
+				}
+			}
+		}
+		return names
+	}
+
+	t.Run("deliver each span when it ends", func(t *testing.T) {
+		batches := SplitByEndTime(td, start, 0)
+		assert.Len(t, batches, 3)
+		assert.Equal(t, start.Add(30*time.Millisecond), batches[0].At)
+		assert.Equal(t, map[string][]string{"backend": {"query"}}, spanNames(t, batches[0].Traces))
+		assert.Equal(t, start.Add(60*time.Millisecond), batches[1].At)
+		assert.Equal(t, map[string][]string{"backend": {"render"}}, spanNames(t, batches[1].Traces))
+		assert.Equal(t, start.Add(100*time.Millisecond), batches[2].At)
+		assert.Equal(t, map[string][]string{"frontend": {"request"}}, spanNames(t, batches[2].Traces))
+	})
+
+	t.Run("deliver spans ending in the same interval together", func(t *testing.T) {
+		batches := SplitByEndTime(td, start, 50*time.Millisecond)
+		assert.Len(t, batches, 2)
+		assert.Equal(t, start.Add(50*time.Millisecond), batches[0].At)
+		assert.Equal(t, map[string][]string{"backend": {"query"}}, spanNames(t, batches[0].Traces))
+		assert.Equal(t, start.Add(100*time.Millisecond), batches[1].At)
+		assert.Equal(t, map[string][]string{"backend": {"render"}, "frontend": {"request"}}, spanNames(t, batches[1].Traces))
+	})
+
+	t.Run("keep all the spans", func(t *testing.T) {
+		count := 0
+		for _, b := range SplitByEndTime(td, start, 10*time.Millisecond) {
+			count += b.Traces.SpanCount()
+		}
+		assert.Equal(t, td.SpanCount(), count)
+	})
+}
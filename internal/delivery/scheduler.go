@@ -0,0 +1,88 @@
+package delivery
+
+import (
+	"context"
+	"github.com/k4ji/tracesimulationreceiver/internal/clock"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Scheduler hands batches to a function at their delivery times, in the order of the times
+// and, for the same time, in the order they are scheduled.
+type Scheduler struct {
+	clock   clock.Clock
+	deliver func(ptrace.Traces)
+	// mu guards pending and makes the deliveries one at a time.
+	mu sync.Mutex
+	// pending are the batches that are not delivered yet, sorted by their delivery times.
+	pending []Batch
+	// wake tells Run that a batch is scheduled before the one it waits for.
+	wake chan struct{}
+}
+
+// NewScheduler creates a new Scheduler that hands the batches to deliver.
+func NewScheduler(clock clock.Clock, deliver func(ptrace.Traces)) *Scheduler {
+	return &Scheduler{
+		clock:   clock,
+		deliver: deliver,
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// Schedule adds the batch to the batches to deliver. The batches that are already due are delivered before it returns,
+// so that catching up waits for the deliveries.
+func (s *Scheduler) Schedule(batch Batch) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := sort.Search(len(s.pending), func(i int) bool { return s.pending[i].At.After(batch.At) })
+	s.pending = append(s.pending, Batch{})
+	copy(s.pending[i+1:], s.pending[i:])
+	s.pending[i] = batch
+	s.deliverDue()
+	// Run waits for the first batch only, so it has to wait again if another batch comes first
+	if i == 0 && batch.At.After(s.clock.Now()) {
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Run delivers the scheduled batches at their delivery times until the context is done.
+// The batches that are not delivered by then are discarded.
+func (s *Scheduler) Run(ctx context.Context) {
+	for {
+		var wait <-chan time.Time
+		s.mu.Lock()
+		// the batches scheduled so far are taken into account, so there is no need to wake up for them
+		select {
+		case <-s.wake:
+		default:
+		}
+		s.deliverDue()
+		if len(s.pending) > 0 {
+			wait = s.clock.After(s.pending[0].At.Sub(s.clock.Now()))
+		}
+		s.mu.Unlock()
+		// no batch is waited for if there is none, until one is scheduled
+		select {
+		case <-wait:
+		case <-s.wake:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// deliverDue delivers the batches whose delivery times have come. The caller must hold mu.
+func (s *Scheduler) deliverDue() {
+	now := s.clock.Now()
+	for len(s.pending) > 0 && !s.pending[0].At.After(now) {
+		batch := s.pending[0]
+		s.pending[0] = Batch{}
+		s.pending = s.pending[1:]
+		s.deliver(batch.Traces)
+	}
+}
@@ -0,0 +1,106 @@
+package delivery
+
+import (
+	"context"
+	"github.com/k4ji/tracesimulationreceiver/internal/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestScheduler(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	newBatch := func(name string, at time.Time) Batch {
+		td := ptrace.NewTraces()
+		td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName(name)
+		return Batch{At: at, Traces: td}
+	}
+	// newScheduler returns a scheduler and the names of the spans it has delivered so far
+	newScheduler := func(c clock.Clock) (*Scheduler, func() []string) {
+		var mu sync.Mutex
+		var names []string
+		s := NewScheduler(c, func(td ptrace.Traces) {
+			mu.Lock()
+			defer mu.Unlock()
+			names = append(names, td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Name())
+		})
+		return s, func() []string {
+			mu.Lock()
+			defer mu.Unlock()
+			return append([]string(nil), names...)
+		}
+	}
+	// run runs the scheduler until the returned function is called, which waits for Run to return
+	run := func(t *testing.T, s *Scheduler) func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.Run(ctx)
+		}()
+		stop := func() {
+			cancel()
+			wg.Wait()
+		}
+		t.Cleanup(stop)
+		return stop
+	}
+
+	t.Run("deliver the due batches right away", func(t *testing.T) {
+		s, delivered := newScheduler(clock.NewFake(now))
+		s.Schedule(newBatch("past", now.Add(-time.Second)))
+		s.Schedule(newBatch("now", now))
+		assert.Equal(t, []string{"past", "now"}, delivered())
+	})
+
+	t.Run("deliver the batches in the order of their times", func(t *testing.T) {
+		c := clock.NewFake(now)
+		s, delivered := newScheduler(c)
+		run(t, s)
+		s.Schedule(newBatch("late", now.Add(2*time.Second)))
+		s.Schedule(newBatch("first", now.Add(time.Second)))
+		s.Schedule(newBatch("second", now.Add(time.Second)))
+		require.Eventually(t, func() bool { return c.Waiters() > 0 }, time.Second, time.Millisecond)
+		assert.Empty(t, delivered())
+
+		c.Advance(time.Second)
+		require.Eventually(t, func() bool { return len(delivered()) == 2 }, time.Second, time.Millisecond)
+		// the batches at the same time keep the order they are scheduled in
+		assert.Equal(t, []string{"first", "second"}, delivered())
+
+		c.Advance(time.Second)
+		require.Eventually(t, func() bool { return len(delivered()) == 3 }, time.Second, time.Millisecond)
+		assert.Equal(t, "late", delivered()[2])
+	})
+
+	t.Run("deliver a batch scheduled before the one waited for", func(t *testing.T) {
+		c := clock.NewFake(now)
+		s, delivered := newScheduler(c)
+		run(t, s)
+		s.Schedule(newBatch("late", now.Add(time.Hour)))
+		require.Eventually(t, func() bool { return c.Waiters() == 1 }, time.Second, time.Millisecond)
+		s.Schedule(newBatch("early", now.Add(time.Second)))
+		// the waiter for the late batch is left behind
+		require.Eventually(t, func() bool { return c.Waiters() == 2 }, time.Second, time.Millisecond)
+
+		c.Advance(time.Second)
+		require.Eventually(t, func() bool { return len(delivered()) == 1 }, time.Second, time.Millisecond)
+		assert.Equal(t, []string{"early"}, delivered())
+	})
+
+	t.Run("discard the pending batches once the context is done", func(t *testing.T) {
+		c := clock.NewFake(now)
+		s, delivered := newScheduler(c)
+		stop := run(t, s)
+		s.Schedule(newBatch("pending", now.Add(time.Second)))
+		require.Eventually(t, func() bool { return c.Waiters() == 1 }, time.Second, time.Millisecond)
+
+		stop()
+		c.Advance(time.Second)
+		assert.Empty(t, delivered())
+	})
+}
@@ -0,0 +1,112 @@
+package delivery
+
+import (
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"time"
+)
+
+// Shaper reshapes batches into payloads that are delivered separately and late, as multiple SDKs shipping
+// independently do, so that the spans of a trace arrive out of order.
+type Shaper struct {
+	// splitByResource delivers the spans of each resource in a separate payload.
+	splitByResource bool
+	// shuffleParts is the number of payloads the spans of a batch are randomly scattered across. No shuffle if less than 2.
+	shuffleParts int
+	// minDelay and maxDelay are the range the delay of each payload is drawn from uniformly.
+	minDelay time.Duration
+	maxDelay time.Duration
+	// randomness is the source of randomness for the delays and the shuffle.
+	randomness func() float64
+}
+
+// NewShaper creates a new Shaper.
+func NewShaper(splitByResource bool, shuffleParts int, minDelay, maxDelay time.Duration, randomness func() float64) *Shaper {
+	return &Shaper{
+		splitByResource: splitByResource,
+		shuffleParts:    shuffleParts,
+		minDelay:        minDelay,
+		maxDelay:        maxDelay,
+		randomness:      randomness,
+	}
+}
+
+// Shape splits the batch into payloads, each of which is delivered after its own delay from the time of the batch.
+// The payloads are returned in the order they are handed over, which is not necessarily the order of their delivery times.
+func (s *Shaper) Shape(batch Batch) []Batch {
+	payloads := []ptrace.Traces{batch.Traces}
+	if s.splitByResource {
+		payloads = splitByResource(batch.Traces)
+	}
+	if s.shuffleParts > 1 {
+		shuffled := make([]ptrace.Traces, 0, len(payloads)*s.shuffleParts)
+		for _, payload := range payloads {
+			shuffled = append(shuffled, shuffle(payload, s.shuffleParts, s.randomness)...)
+		}
+		payloads = shuffled
+	}
+	result := make([]Batch, 0, len(payloads))
+	for _, payload := range payloads {
+		result = append(result, Batch{At: batch.At.Add(s.delay()), Traces: payload})
+	}
+	return result
+}
+
+// delay draws the delay of a payload. No random number is drawn if the delay is fixed.
+func (s *Shaper) delay() time.Duration {
+	if s.minDelay == s.maxDelay {
+		return s.minDelay
+	}
+	return s.minDelay + time.Duration(s.randomness()*float64(s.maxDelay-s.minDelay))
+}
+
+// splitByResource splits the traces into one per resource
+func splitByResource(td ptrace.Traces) []ptrace.Traces {
+	rss := td.ResourceSpans()
+	result := make([]ptrace.Traces, 0, rss.Len())
+	for i := 0; i < rss.Len(); i++ {
+		payload := ptrace.NewTraces()
+		rss.At(i).CopyTo(payload.ResourceSpans().AppendEmpty())
+		result = append(result, payload)
+	}
+	return result
+}
+
+// shuffle scatters the spans of the traces across the given number of traces in random order.
+// The spans keep their resources and scopes. Fewer traces are returned if there are fewer spans than parts.
+func shuffle(td ptrace.Traces, parts int, randomness func() float64) []ptrace.Traces {
+	type location struct {
+		rs, ss, span int
+	}
+	var locations []location
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		for j := 0; j < rss.At(i).ScopeSpans().Len(); j++ {
+			for k := 0; k < rss.At(i).ScopeSpans().At(j).Spans().Len(); k++ {
+				locations = append(locations, location{rs: i, ss: j, span: k})
+			}
+		}
+	}
+	// Fisher-Yates shuffle
+	for i := len(locations) - 1; i > 0; i-- {
+		j := int(randomness() * float64(i+1))
+		locations[i], locations[j] = locations[j], locations[i]
+	}
+
+	parts = min(parts, len(locations))
+	builders := make([]*builder, parts)
+	for i := range builders {
+		builders[i] = newBuilder()
+	}
+	// the shuffled spans are cut into parts of almost equal size, so that no part is empty
+	for p, l := range locations {
+		rs := rss.At(l.rs)
+		ss := rs.ScopeSpans().At(l.ss)
+		b := builders[p*parts/len(locations)]
+		ss.Spans().At(l.span).CopyTo(b.scopeSpansOf(rs, l.rs, ss, l.ss).Spans().AppendEmpty())
+	}
+	result := make([]ptrace.Traces, 0, parts)
+	for _, b := range builders {
+		result = append(result, b.traces)
+	}
+	return result
+}
@@ -0,0 +1,93 @@
+package delivery
+
+import (
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestShaper_Shape(t *testing.T) {
+	at := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	td := ptrace.NewTraces()
+	for _, service := range []struct {
+		name  string
+		spans []string
+	}{
+		{name: "frontend", spans: []string{"request"}},
+		{name: "backend", spans: []string{"query", "render"}},
+	} {
+		rs := td.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr("service.name", service.name)
+		ss := rs.ScopeSpans().AppendEmpty()
+		ss.Scope().SetName("tracesimulator")
+		for _, name := range service.spans {
+			ss.Spans().AppendEmpty().SetName(name)
+		}
+	}
+	batch := Batch{At: at, Traces: td}
+
+	// services returns the names of the services in the payload, checking that the scopes are kept
+	services := func(t *testing.T, td ptrace.Traces) []string {
+		var names []string
+		for i := 0; i < td.ResourceSpans().Len(); i++ {
+			rs := td.ResourceSpans().At(i)
+			service, _ := rs.Resource().Attributes().Get("service.name")
+			names = append(names, service.Str())
+			for j := 0; j < rs.ScopeSpans().Len(); j++ {
+				assert.Equal(t, "tracesimulator", rs.ScopeSpans().At(j).Scope().Name())
+			}
+		}
+		return names
+	}
+
+	t.Run("deliver the spans of each resource separately", func(t *testing.T) {
+		payloads := NewShaper(true, 0, 0, 0, nil).Shape(batch)
+		assert.Len(t, payloads, 2)
+		assert.Equal(t, []string{"frontend"}, services(t, payloads[0].Traces))
+		assert.Equal(t, 1, payloads[0].Traces.SpanCount())
+		assert.Equal(t, []string{"backend"}, services(t, payloads[1].Traces))
+		assert.Equal(t, 2, payloads[1].Traces.SpanCount())
+		for _, p := range payloads {
+			assert.Equal(t, at, p.At)
+		}
+	})
+
+	t.Run("scatter the spans across parts", func(t *testing.T) {
+		payloads := NewShaper(false, 2, 0, 0, rand.Float64).Shape(batch)
+		assert.Len(t, payloads, 2)
+		count := 0
+		for _, p := range payloads {
+			assert.Positive(t, p.Traces.SpanCount())
+			services(t, p.Traces)
+			count += p.Traces.SpanCount()
+		}
+		assert.Equal(t, td.SpanCount(), count)
+	})
+
+	t.Run("scatter the spans of each resource across parts", func(t *testing.T) {
+		payloads := NewShaper(true, 2, 0, 0, rand.Float64).Shape(batch)
+		// the frontend has only one span, so it is not scattered
+		assert.Len(t, payloads, 3)
+		assert.Equal(t, []string{"frontend"}, services(t, payloads[0].Traces))
+		assert.Equal(t, []string{"backend"}, services(t, payloads[1].Traces))
+		assert.Equal(t, []string{"backend"}, services(t, payloads[2].Traces))
+	})
+
+	t.Run("delay each payload", func(t *testing.T) {
+		draws := 0
+		payloads := NewShaper(true, 0, time.Second, 3*time.Second, func() float64 { draws++; return 0.5 }).Shape(batch)
+		assert.Equal(t, 2, draws)
+		for _, p := range payloads {
+			assert.Equal(t, at.Add(2*time.Second), p.At)
+		}
+	})
+
+	t.Run("delay each payload by the fixed delay", func(t *testing.T) {
+		payloads := NewShaper(false, 0, time.Second, time.Second, nil).Shape(batch)
+		assert.Len(t, payloads, 1)
+		assert.Equal(t, at.Add(time.Second), payloads[0].At)
+		assert.Equal(t, td.SpanCount(), payloads[0].Traces.SpanCount())
+	})
+}
@@ -0,0 +1,35 @@
+package ratecontrol
+
+// Arrival represents how the times between trace arrivals are distributed
+type Arrival int
+
+const (
+	// ArrivalUnknown represents an unknown arrival process
+	ArrivalUnknown Arrival = iota
+	// ArrivalConstant spaces arrivals evenly
+	ArrivalConstant
+	// ArrivalPoisson draws the times between arrivals from an exponential distribution
+	ArrivalPoisson
+)
+
+func (a Arrival) String() string {
+	switch a {
+	case ArrivalConstant:
+		return "constant"
+	case ArrivalPoisson:
+		return "poisson"
+	default:
+		return "unknown"
+	}
+}
+
+func ArrivalFromString(arrival string) Arrival {
+	switch arrival {
+	case "constant":
+		return ArrivalConstant
+	case "poisson":
+		return ArrivalPoisson
+	default:
+		return ArrivalUnknown
+	}
+}
@@ -0,0 +1,66 @@
+package ratecontrol
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Controller decides when traces arrive to follow the target rate of a profile
+type Controller struct {
+	profile    Profile
+	arrival    Arrival
+	randomness func() float64
+}
+
+// NewController creates a new controller
+func NewController(profile Profile, arrival Arrival, randomness func() float64) (*Controller, error) {
+	if profile == nil {
+		return nil, fmt.Errorf("profile cannot be nil")
+	}
+	if arrival != ArrivalConstant && arrival != ArrivalPoisson {
+		return nil, fmt.Errorf("unsupported arrival: %s", arrival)
+	}
+	return &Controller{
+		profile:    profile,
+		arrival:    arrival,
+		randomness: randomness,
+	}, nil
+}
+
+// Next returns the time elapsed since the start at which the arrival following the arrival at previous occurs.
+// The arrival occurs when the rate integrated since the previous arrival reaches a target, which is 1 with constant arrival
+// and drawn from the exponential distribution with poisson arrival. Changes of the rate between arrivals are therefore
+// followed, so that a burst or a step starting long before the next arrival at the previous rate still brings arrivals.
+// The parts of the profile at 0 are skipped, since nothing arrives during them.
+func (c *Controller) Next(previous time.Duration) time.Duration {
+	target := 1.0
+	if c.arrival == ArrivalPoisson {
+		// inverse transform sampling of the exponential distribution
+		target = -math.Log(1 - c.randomness())
+	}
+	elapsed := previous
+	for {
+		perSecond := c.profile.Rate(elapsed)
+		change, changes := c.profile.NextChange(elapsed)
+		// the profiles only stay at 0 for a while, which they make sure of when they are created
+		if perSecond <= 0 && changes {
+			elapsed = change
+			continue
+		}
+		needed := time.Duration(target / perSecond * float64(time.Second))
+		if !changes || elapsed+needed <= change {
+			return elapsed + needed
+		}
+		target -= perSecond * (change - elapsed).Seconds()
+		elapsed = change
+	}
+}
+
+func (c *Controller) Profile() Profile {
+	return c.profile
+}
+
+func (c *Controller) Arrival() Arrival {
+	return c.arrival
+}
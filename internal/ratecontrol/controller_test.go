@@ -0,0 +1,131 @@
+package ratecontrol
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestNewController(t *testing.T) {
+	profile, _ := NewConstantProfile(1)
+
+	t.Run("return error if the profile is nil", func(t *testing.T) {
+		_, err := NewController(nil, ArrivalConstant, rand.Float64)
+		assert.EqualError(t, err, "profile cannot be nil")
+	})
+
+	t.Run("return error if the arrival is unknown", func(t *testing.T) {
+		_, err := NewController(profile, ArrivalUnknown, rand.Float64)
+		assert.EqualError(t, err, "unsupported arrival: unknown")
+	})
+}
+
+func TestController_Next(t *testing.T) {
+	t.Run("space arrivals evenly with constant arrival", func(t *testing.T) {
+		profile, _ := NewConstantProfile(4)
+		controller, err := NewController(profile, ArrivalConstant, rand.Float64)
+		assert.NoError(t, err)
+		var next time.Duration
+		for i := 1; i <= 8; i++ {
+			next = controller.Next(next)
+			assert.Equal(t, time.Duration(i)*250*time.Millisecond, next)
+		}
+	})
+
+	t.Run("draw the times between arrivals from an exponential distribution with poisson arrival", func(t *testing.T) {
+		profile, _ := NewConstantProfile(10)
+		controller, err := NewController(profile, ArrivalPoisson, func() float64 { return 1 - math.Exp(-1) })
+		assert.NoError(t, err)
+		// the inverse of the exponential CDF at 1-e^-1 is the mean
+		assert.Equal(t, 100*time.Millisecond, controller.Next(0).Round(time.Millisecond))
+
+		profile, _ = NewConstantProfile(100)
+		controller, err = NewController(profile, ArrivalPoisson, rand.New(rand.NewSource(1)).Float64)
+		assert.NoError(t, err)
+		var next time.Duration
+		for i := 0; i < 10000; i++ {
+			next = controller.Next(next)
+		}
+		// 10000 arrivals at 100 per second take about 100 seconds
+		assert.InDelta(t, 100, next.Seconds(), 5)
+	})
+
+	t.Run("follow the rate of the profile", func(t *testing.T) {
+		profile, _ := NewStepProfile(1, []Step{NewStep(2*time.Second, 10)})
+		controller, err := NewController(profile, ArrivalConstant, rand.Float64)
+		assert.NoError(t, err)
+		var arrivals []time.Duration
+		for next := controller.Next(0); next <= 3*time.Second; next = controller.Next(next) {
+			arrivals = append(arrivals, next)
+		}
+		// 1 per second until 2s, then 10 per second
+		assert.Len(t, arrivals, 2+10)
+	})
+
+	t.Run("bring arrivals in a burst starting between arrivals at a low base rate", func(t *testing.T) {
+		// one arrival every 20 seconds at the base rate, and 10 per second in the bursts from 50s to 60s and from 100s to 110s
+		profile, _ := NewBurstProfile(0.05, 10, 50*time.Second, 10*time.Second)
+		controller, err := NewController(profile, ArrivalConstant, rand.Float64)
+		assert.NoError(t, err)
+		inBursts := 0
+		for next := controller.Next(0); next < 120*time.Second; next = controller.Next(next) {
+			if (next >= 50*time.Second && next < 60*time.Second) || (next >= 100*time.Second && next < 110*time.Second) {
+				inBursts++
+			}
+		}
+		assert.InDelta(t, 200, inBursts, 2)
+	})
+
+	t.Run("follow a step starting between arrivals at a low base rate", func(t *testing.T) {
+		profile, _ := NewStepProfile(0.05, []Step{NewStep(30*time.Second, 10)})
+		controller, err := NewController(profile, ArrivalConstant, rand.Float64)
+		assert.NoError(t, err)
+		assert.Equal(t, 20*time.Second, controller.Next(0))
+		// half of the arrival is made up at the base rate until 30s, and the rest at the rate of the step
+		assert.Equal(t, 30*time.Second+50*time.Millisecond, controller.Next(20*time.Second))
+	})
+
+	t.Run("skip the parts of the profile at 0", func(t *testing.T) {
+		rampFromZero, _ := NewRampProfile(0, 10, 10*time.Second)
+		stepsToZero, _ := NewStepProfile(10, []Step{NewStep(time.Second, 0), NewStep(time.Minute, 10)})
+		diurnalFromZero, _ := NewDiurnalProfile(0, 10, time.Minute, 30*time.Second)
+		burstsOnly, _ := NewBurstProfile(0, 10, time.Minute, time.Second)
+		testCases := []struct {
+			name     string
+			profile  Profile
+			previous time.Duration
+			expected time.Duration
+			delta    time.Duration
+		}{
+			// the area under the ramp, t²/2, reaches 1 at about √2 seconds
+			{name: "ramp from 0", profile: rampFromZero, previous: 0, expected: 1414 * time.Millisecond, delta: 100 * time.Millisecond},
+			{name: "step down to 0 and up again", profile: stepsToZero, previous: 950 * time.Millisecond, expected: time.Minute + 50*time.Millisecond},
+			// the area under the curve from the trough reaches 1 at about 4.8 seconds
+			{name: "diurnal with minimum at 0", profile: diurnalFromZero, previous: time.Minute, expected: time.Minute + 4800*time.Millisecond, delta: 100 * time.Millisecond},
+			{name: "bursts from 0", profile: burstsOnly, previous: 0, expected: time.Minute + 100*time.Millisecond},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				controller, err := NewController(tc.profile, ArrivalConstant, rand.Float64)
+				assert.NoError(t, err)
+				assert.InDelta(t, tc.expected, controller.Next(tc.previous), float64(tc.delta))
+			})
+		}
+	})
+
+	t.Run("bring arrivals in a burst with poisson arrival", func(t *testing.T) {
+		profile, _ := NewBurstProfile(0.05, 100, time.Minute, 10*time.Second)
+		controller, err := NewController(profile, ArrivalPoisson, rand.New(rand.NewSource(1)).Float64)
+		assert.NoError(t, err)
+		inBursts := 0
+		for next := controller.Next(0); next < 10*time.Minute; next = controller.Next(next) {
+			if (next-time.Minute)%time.Minute < 10*time.Second && next >= time.Minute {
+				inBursts++
+			}
+		}
+		// 9 bursts of 1000 arrivals
+		assert.InDelta(t, 9000, inBursts, 300)
+	})
+}
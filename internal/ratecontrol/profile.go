@@ -0,0 +1,245 @@
+package ratecontrol
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// continuousResolution is the length of the segments over which the rates of continuously changing profiles are taken as constant
+const continuousResolution = 100 * time.Millisecond
+
+// Profile gives the target rate in traces per second at a time elapsed since the start of the generation
+type Profile interface {
+	Rate(elapsed time.Duration) float64
+	// NextChange returns the time after elapsed until which the rate is taken as constant, or false if the rate never changes after elapsed.
+	NextChange(elapsed time.Duration) (time.Duration, bool)
+}
+
+// ConstantProfile keeps the rate constant
+type ConstantProfile struct {
+	perSecond float64
+}
+
+// NewConstantProfile creates a new constant profile
+func NewConstantProfile(perSecond float64) (*ConstantProfile, error) {
+	if err := validatePositiveRate(perSecond); err != nil {
+		return nil, err
+	}
+	return &ConstantProfile{perSecond: perSecond}, nil
+}
+
+func (p *ConstantProfile) Rate(_ time.Duration) float64 {
+	return p.perSecond
+}
+
+func (p *ConstantProfile) NextChange(_ time.Duration) (time.Duration, bool) {
+	return 0, false
+}
+
+// RampProfile changes the rate linearly from one rate to another over a duration, and then keeps it
+type RampProfile struct {
+	from     float64
+	to       float64
+	duration time.Duration
+}
+
+// NewRampProfile creates a new ramp profile, which can start from 0 but has to end at a positive rate
+func NewRampProfile(from, to float64, duration time.Duration) (*RampProfile, error) {
+	if err := validateRate(from); err != nil {
+		return nil, fmt.Errorf("invalid ramp start: %w", err)
+	}
+	// the rate is kept at the end of the ramp, so no trace would arrive anymore at 0
+	if err := validatePositiveRate(to); err != nil {
+		return nil, fmt.Errorf("invalid ramp end: %w", err)
+	}
+	if duration <= 0 {
+		return nil, fmt.Errorf("ramp duration must be greater than 0")
+	}
+	return &RampProfile{from: from, to: to, duration: duration}, nil
+}
+
+func (p *RampProfile) Rate(elapsed time.Duration) float64 {
+	if elapsed >= p.duration {
+		return p.to
+	}
+	if elapsed <= 0 {
+		return p.from
+	}
+	return p.from + (p.to-p.from)*float64(elapsed)/float64(p.duration)
+}
+
+func (p *RampProfile) NextChange(elapsed time.Duration) (time.Duration, bool) {
+	if elapsed >= p.duration {
+		return 0, false
+	}
+	return min(max(elapsed, 0)+continuousResolution, p.duration), true
+}
+
+// Step represents a change of the rate at a time elapsed since the start
+type Step struct {
+	after     time.Duration
+	perSecond float64
+}
+
+// NewStep creates a new step
+func NewStep(after time.Duration, perSecond float64) Step {
+	return Step{after: after, perSecond: perSecond}
+}
+
+// StepProfile changes the rate in steps, starting from a base rate
+type StepProfile struct {
+	base  float64
+	steps []Step
+}
+
+// NewStepProfile creates a new step profile. The rates can be 0 except for the last one, which is kept forever.
+func NewStepProfile(base float64, steps []Step) (*StepProfile, error) {
+	if err := validateRate(base); err != nil {
+		return nil, err
+	}
+	sorted := make([]Step, len(steps))
+	copy(sorted, steps)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].after < sorted[j].after })
+	for i, s := range sorted {
+		if s.after < 0 {
+			return nil, fmt.Errorf("step time must be greater than or equal to 0")
+		}
+		if err := validateRate(s.perSecond); err != nil {
+			return nil, fmt.Errorf("invalid step at %s: %w", s.after, err)
+		}
+		if i > 0 && sorted[i-1].after == s.after {
+			return nil, fmt.Errorf("duplicate step at %s", s.after)
+		}
+	}
+	if len(sorted) == 0 {
+		if err := validatePositiveRate(base); err != nil {
+			return nil, err
+		}
+	} else if last := sorted[len(sorted)-1]; last.perSecond <= 0 {
+		return nil, fmt.Errorf("invalid step at %s: the rate of the last step must be greater than 0", last.after)
+	}
+	return &StepProfile{base: base, steps: sorted}, nil
+}
+
+func (p *StepProfile) Rate(elapsed time.Duration) float64 {
+	rate := p.base
+	for _, s := range p.steps {
+		if elapsed < s.after {
+			break
+		}
+		rate = s.perSecond
+	}
+	return rate
+}
+
+func (p *StepProfile) NextChange(elapsed time.Duration) (time.Duration, bool) {
+	for _, s := range p.steps {
+		if elapsed < s.after {
+			return s.after, true
+		}
+	}
+	return 0, false
+}
+
+// DiurnalProfile changes the rate along a sinusoidal curve between a minimum and a maximum
+type DiurnalProfile struct {
+	minimum float64
+	maximum float64
+	period  time.Duration
+	peak    time.Duration // Time elapsed since the start at which the rate reaches the maximum
+}
+
+// NewDiurnalProfile creates a new diurnal profile, whose minimum can be 0 but maximum has to be positive
+func NewDiurnalProfile(minimum, maximum float64, period, peak time.Duration) (*DiurnalProfile, error) {
+	if err := validateRate(minimum); err != nil {
+		return nil, fmt.Errorf("invalid diurnal minimum: %w", err)
+	}
+	if err := validatePositiveRate(maximum); err != nil {
+		return nil, fmt.Errorf("invalid diurnal maximum: %w", err)
+	}
+	if minimum > maximum {
+		return nil, fmt.Errorf("diurnal minimum must be less than or equal to maximum")
+	}
+	if period <= 0 {
+		return nil, fmt.Errorf("diurnal period must be greater than 0")
+	}
+	return &DiurnalProfile{minimum: minimum, maximum: maximum, period: period, peak: peak}, nil
+}
+
+func (p *DiurnalProfile) Rate(elapsed time.Duration) float64 {
+	phase := 2 * math.Pi * float64(elapsed-p.peak) / float64(p.period)
+	return p.minimum + (p.maximum-p.minimum)*(1+math.Cos(phase))/2
+}
+
+func (p *DiurnalProfile) NextChange(elapsed time.Duration) (time.Duration, bool) {
+	if p.minimum == p.maximum {
+		return 0, false
+	}
+	return elapsed + continuousResolution, true
+}
+
+// BurstProfile raises the rate from a base rate periodically for a duration
+type BurstProfile struct {
+	base      float64
+	perSecond float64
+	every     time.Duration
+	duration  time.Duration
+}
+
+// NewBurstProfile creates a new burst profile, whose bursts start at the end of every period.
+// The base rate can be 0, so that traces only arrive in the bursts.
+func NewBurstProfile(base, perSecond float64, every, duration time.Duration) (*BurstProfile, error) {
+	if err := validateRate(base); err != nil {
+		return nil, err
+	}
+	if err := validatePositiveRate(perSecond); err != nil {
+		return nil, fmt.Errorf("invalid burst rate: %w", err)
+	}
+	if every <= 0 {
+		return nil, fmt.Errorf("burst period must be greater than 0")
+	}
+	if duration <= 0 || duration > every {
+		return nil, fmt.Errorf("burst duration must be greater than 0 and less than or equal to the period")
+	}
+	return &BurstProfile{base: base, perSecond: perSecond, every: every, duration: duration}, nil
+}
+
+func (p *BurstProfile) Rate(elapsed time.Duration) float64 {
+	if elapsed < p.every {
+		return p.base
+	}
+	if (elapsed-p.every)%p.every < p.duration {
+		return p.perSecond
+	}
+	return p.base
+}
+
+func (p *BurstProfile) NextChange(elapsed time.Duration) (time.Duration, bool) {
+	if elapsed < p.every {
+		return p.every, true
+	}
+	phase := (elapsed - p.every) % p.every
+	if phase < p.duration {
+		// the end of the burst
+		return elapsed + p.duration - phase, true
+	}
+	// the start of the next burst
+	return elapsed + p.every - phase, true
+}
+
+func validateRate(perSecond float64) error {
+	if perSecond < 0 || math.IsInf(perSecond, 0) || math.IsNaN(perSecond) {
+		return fmt.Errorf("rate must be a non-negative number, got %v", perSecond)
+	}
+	return nil
+}
+
+// validatePositiveRate validates a rate that is kept forever, at which no trace would arrive anymore if it were 0
+func validatePositiveRate(perSecond float64) error {
+	if perSecond <= 0 || math.IsInf(perSecond, 0) || math.IsNaN(perSecond) {
+		return fmt.Errorf("rate must be a positive number, got %v", perSecond)
+	}
+	return nil
+}
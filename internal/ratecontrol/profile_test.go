@@ -0,0 +1,161 @@
+package ratecontrol
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestProfile_Rate(t *testing.T) {
+	ramp, err := NewRampProfile(10, 110, 10*time.Second)
+	assert.NoError(t, err)
+	steps, err := NewStepProfile(5, []Step{NewStep(20*time.Second, 1), NewStep(10*time.Second, 50)})
+	assert.NoError(t, err)
+	diurnal, err := NewDiurnalProfile(10, 30, 24*time.Hour, 12*time.Hour)
+	assert.NoError(t, err)
+	burst, err := NewBurstProfile(5, 100, time.Minute, 10*time.Second)
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		profile  Profile
+		elapsed  time.Duration
+		expected float64
+	}{
+		{name: "ramp at start", profile: ramp, elapsed: 0, expected: 10},
+		{name: "ramp halfway", profile: ramp, elapsed: 5 * time.Second, expected: 60},
+		{name: "ramp after end", profile: ramp, elapsed: time.Minute, expected: 110},
+		{name: "step before first step", profile: steps, elapsed: 5 * time.Second, expected: 5},
+		{name: "step at first step", profile: steps, elapsed: 10 * time.Second, expected: 50},
+		{name: "step after last step", profile: steps, elapsed: time.Minute, expected: 1},
+		{name: "diurnal at trough", profile: diurnal, elapsed: 0, expected: 10},
+		{name: "diurnal halfway to peak", profile: diurnal, elapsed: 6 * time.Hour, expected: 20},
+		{name: "diurnal at peak", profile: diurnal, elapsed: 12 * time.Hour, expected: 30},
+		{name: "burst before first burst", profile: burst, elapsed: 30 * time.Second, expected: 5},
+		{name: "burst during burst", profile: burst, elapsed: 65 * time.Second, expected: 100},
+		{name: "burst between bursts", profile: burst, elapsed: 90 * time.Second, expected: 5},
+		{name: "burst during next burst", profile: burst, elapsed: 125 * time.Second, expected: 100},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.InDelta(t, tc.expected, tc.profile.Rate(tc.elapsed), 1e-9)
+		})
+	}
+}
+
+func TestProfile_NextChange(t *testing.T) {
+	constant, err := NewConstantProfile(10)
+	assert.NoError(t, err)
+	ramp, err := NewRampProfile(10, 110, 10*time.Second)
+	assert.NoError(t, err)
+	steps, err := NewStepProfile(5, []Step{NewStep(20*time.Second, 1), NewStep(10*time.Second, 50)})
+	assert.NoError(t, err)
+	burst, err := NewBurstProfile(5, 100, time.Minute, 10*time.Second)
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name            string
+		profile         Profile
+		elapsed         time.Duration
+		expected        time.Duration
+		expectedChanges bool
+	}{
+		{name: "constant", profile: constant, elapsed: time.Second, expectedChanges: false},
+		{name: "ramp during ramp", profile: ramp, elapsed: time.Second, expected: time.Second + continuousResolution, expectedChanges: true},
+		{name: "ramp at the end of ramp", profile: ramp, elapsed: 10*time.Second - time.Millisecond, expected: 10 * time.Second, expectedChanges: true},
+		{name: "ramp after end", profile: ramp, elapsed: time.Minute, expectedChanges: false},
+		{name: "step before first step", profile: steps, elapsed: 5 * time.Second, expected: 10 * time.Second, expectedChanges: true},
+		{name: "step at first step", profile: steps, elapsed: 10 * time.Second, expected: 20 * time.Second, expectedChanges: true},
+		{name: "step after last step", profile: steps, elapsed: time.Minute, expectedChanges: false},
+		{name: "burst before first burst", profile: burst, elapsed: 30 * time.Second, expected: time.Minute, expectedChanges: true},
+		{name: "burst during burst", profile: burst, elapsed: 65 * time.Second, expected: 70 * time.Second, expectedChanges: true},
+		{name: "burst between bursts", profile: burst, elapsed: 90 * time.Second, expected: 2 * time.Minute, expectedChanges: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			change, changes := tc.profile.NextChange(tc.elapsed)
+			assert.Equal(t, tc.expectedChanges, changes)
+			if tc.expectedChanges {
+				assert.Equal(t, tc.expected, change)
+			}
+		})
+	}
+}
+
+func TestNewProfileErrors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		create   func() error
+		expected string
+	}{
+		{
+			name:     "constant with non-positive rate",
+			create:   func() error { _, err := NewConstantProfile(0); return err },
+			expected: "rate must be a positive number, got 0",
+		},
+		{
+			name:     "ramp without duration",
+			create:   func() error { _, err := NewRampProfile(1, 2, 0); return err },
+			expected: "ramp duration must be greater than 0",
+		},
+		{
+			name:     "ramp to non-positive rate",
+			create:   func() error { _, err := NewRampProfile(1, 0, time.Second); return err },
+			expected: "invalid ramp end: rate must be a positive number, got 0",
+		},
+		{
+			name:     "ramp from negative rate",
+			create:   func() error { _, err := NewRampProfile(-1, 2, time.Second); return err },
+			expected: "invalid ramp start: rate must be a non-negative number, got -1",
+		},
+		{
+			name: "last step at 0",
+			create: func() error {
+				_, err := NewStepProfile(1, []Step{NewStep(2*time.Second, 0), NewStep(time.Second, 3)})
+				return err
+			},
+			expected: "invalid step at 2s: the rate of the last step must be greater than 0",
+		},
+		{
+			name:     "step without steps at 0",
+			create:   func() error { _, err := NewStepProfile(0, nil); return err },
+			expected: "rate must be a positive number, got 0",
+		},
+		{
+			name:     "diurnal with maximum at 0",
+			create:   func() error { _, err := NewDiurnalProfile(0, 0, time.Hour, 0); return err },
+			expected: "invalid diurnal maximum: rate must be a positive number, got 0",
+		},
+		{
+			name:     "burst at 0",
+			create:   func() error { _, err := NewBurstProfile(1, 0, time.Second, time.Second); return err },
+			expected: "invalid burst rate: rate must be a positive number, got 0",
+		},
+		{
+			name: "duplicate steps",
+			create: func() error {
+				_, err := NewStepProfile(1, []Step{NewStep(time.Second, 2), NewStep(time.Second, 3)})
+				return err
+			},
+			expected: "duplicate step at 1s",
+		},
+		{
+			name:     "diurnal with minimum greater than maximum",
+			create:   func() error { _, err := NewDiurnalProfile(2, 1, time.Hour, 0); return err },
+			expected: "diurnal minimum must be less than or equal to maximum",
+		},
+		{
+			name:     "burst longer than period",
+			create:   func() error { _, err := NewBurstProfile(1, 2, time.Second, 2*time.Second); return err },
+			expected: "burst duration must be greater than 0 and less than or equal to the period",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.EqualError(t, tc.create(), tc.expected)
+		})
+	}
+}
@@ -3,10 +3,12 @@ package opentelemetry
 import (
 	"fmt"
 	simulator "github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/adapter"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/attribute"
 	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/span"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.27.0"
+	"sort"
 )
 
 const DefaultInstrumentationScopeName = "tracesimulator"
@@ -28,6 +30,10 @@ func (a *Adapter) Transform(rootSpans []*span.TreeNode) ([]ptrace.Traces, error)
 		if err := a.processNode(&otelTrace, rootSpan, nil); err != nil {
 			return nil, fmt.Errorf("failed to transform root span '%s': %w", rootSpan.Name(), err)
 		}
+		// resources whose spans are all dropped are not delivered
+		otelTrace.ResourceSpans().RemoveIf(func(rs ptrace.ResourceSpans) bool {
+			return rs.ScopeSpans().At(0).Spans().Len() == 0
+		})
 		otelTraces = append(otelTraces, otelTrace)
 	}
 
@@ -40,7 +46,12 @@ func (a *Adapter) processNode(otelTrace *ptrace.Traces, node *span.TreeNode, par
 		return fmt.Errorf("failed to process node '%s': %w", node.Name(), err)
 	}
 
-	a.addSpanToScope(*scopeSpans, node)
+	if !node.Dropped() {
+		a.addSpanToScope(*scopeSpans, node)
+		if node.Duplicated() {
+			a.addSpanToScope(*scopeSpans, node)
+		}
+	}
 
 	for _, child := range node.Children() {
 		if err := a.processNode(otelTrace, child, scopeSpans); err != nil {
@@ -57,9 +68,7 @@ func (a *Adapter) createOrGetScopeSpans(otelTrace *ptrace.Traces, node *span.Tre
 		resourceSpans := otelTrace.ResourceSpans().AppendEmpty()
 		resource := resourceSpans.Resource()
 		resource.Attributes().PutStr(string(semconv.ServiceNameKey), spanResource.Name())
-		for k, v := range spanResource.Attributes() {
-			resource.Attributes().PutStr(k, v)
-		}
+		putAttributes(resource.Attributes(), node.ResourceAttributes())
 		scopeSpans := resourceSpans.ScopeSpans().AppendEmpty()
 		scopeSpans.Scope().SetName(DefaultInstrumentationScopeName)
 		return &scopeSpans, nil
@@ -86,14 +95,10 @@ func (a *Adapter) addSpanToScope(scopeSpans ptrace.ScopeSpans, node *span.TreeNo
 		otelEvent := otelSpan.Events().AppendEmpty()
 		otelEvent.SetTimestamp(pcommon.NewTimestampFromTime(event.OccurredAt()))
 		otelEvent.SetName(event.Name())
-		for k, v := range event.Attributes() {
-			otelEvent.Attributes().PutStr(k, v)
-		}
+		putAttributes(otelEvent.Attributes(), event.Attributes())
 	}
 
-	for k, v := range node.Attributes() {
-		otelSpan.Attributes().PutStr(k, v)
-	}
+	putAttributes(otelSpan.Attributes(), node.Attributes())
 
 	if node.ParentID() != nil {
 		otelSpan.SetParentSpanID(pcommon.SpanID(node.ParentID().Bytes()))
@@ -136,3 +141,38 @@ func setOtelStatusCode(otelSpan *ptrace.Span, status span.Status) {
 		otelSpan.Status().SetCode(ptrace.StatusCodeUnset)
 	}
 }
+
+// putAttributes puts the values in the order of their keys, so that the same values are always encoded the same way
+func putAttributes(attributes pcommon.Map, values map[string]attribute.Value) {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		putAttribute(attributes, k, values[k])
+	}
+}
+
+func putAttribute(attributes pcommon.Map, key string, value attribute.Value) {
+	switch value.Kind() {
+	case attribute.KindInt:
+		attributes.PutInt(key, value.AsInt())
+	case attribute.KindDouble:
+		attributes.PutDouble(key, value.AsDouble())
+	case attribute.KindBool:
+		attributes.PutBool(key, value.AsBool())
+	case attribute.KindStringSlice:
+		slice := attributes.PutEmptySlice(key)
+		for _, v := range value.AsStringSlice() {
+			slice.AppendEmpty().SetStr(v)
+		}
+	case attribute.KindIntSlice:
+		slice := attributes.PutEmptySlice(key)
+		for _, v := range value.AsIntSlice() {
+			slice.AppendEmpty().SetInt(v)
+		}
+	default:
+		attributes.PutStr(key, value.String())
+	}
+}
@@ -4,9 +4,12 @@ import (
 	simulator "github.com/k4ji/tracesimulationreceiver/internal/tracesimulator"
 	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/blueprint/service"
 	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/blueprint/service/model"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/attribute"
 	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
 	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task/taskduration"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/random"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	conventions "go.opentelemetry.io/otel/semconv/v1.27.0"
 	mathRand "math/rand"
@@ -29,8 +32,8 @@ func TestAdapter_Transform(t *testing.T) {
 	blueprint := service.NewServiceBlueprint([]model.Service{
 		{
 			Name: "service-a",
-			Resource: map[string]string{
-				"resource-key-service-a": "resource-value-service-a",
+			Resource: map[string]attribute.Expression{
+				"resource-key-service-a": attribute.String("resource-value-service-a"),
 			},
 			Tasks: []model.Task{
 				{
@@ -43,20 +46,20 @@ func TestAdapter_Transform(t *testing.T) {
 						task.NewEvent(
 							"event-root-task-a-1",
 							NewAbsoluteDurationDelay(0),
-							map[string]string{
-								"attribute-key-event-root-task-a-1": "attribute-value-event-root-task-a-1",
+							map[string]attribute.Expression{
+								"attribute-key-event-root-task-a-1": attribute.String("attribute-value-event-root-task-a-1"),
 							},
 						),
 						task.NewEvent(
 							"event-root-task-a-2",
 							NewAbsoluteDurationDelay(100*time.Millisecond),
-							map[string]string{
-								"attribute-key-event-root-task-a-2": "attribute-value-event-root-task-a-2",
+							map[string]attribute.Expression{
+								"attribute-key-event-root-task-a-2": attribute.String("attribute-value-event-root-task-a-2"),
 							},
 						),
 					},
-					Attributes: map[string]string{
-						"attribute-key-root-task-a": "attribute-value-root-task-a",
+					Attributes: map[string]attribute.Expression{
+						"attribute-key-root-task-a": attribute.String("attribute-value-root-task-a"),
 					},
 					Children: []model.Task{
 						{
@@ -69,13 +72,13 @@ func TestAdapter_Transform(t *testing.T) {
 								task.NewEvent(
 									"event-child-task-a1-1",
 									NewAbsoluteDurationDelay(0),
-									map[string]string{
-										"attribute-key-event-child-task-a1-1": "attribute-value-event-child-task-a1-1",
+									map[string]attribute.Expression{
+										"attribute-key-event-child-task-a1-1": attribute.String("attribute-value-event-child-task-a1-1"),
 									},
 								),
 							},
-							Attributes: map[string]string{
-								"attribute-key-child-task-a1": "attribute-value-child-task-a1",
+							Attributes: map[string]attribute.Expression{
+								"attribute-key-child-task-a1": attribute.String("attribute-value-child-task-a1"),
 							},
 						},
 						{
@@ -88,13 +91,13 @@ func TestAdapter_Transform(t *testing.T) {
 								task.NewEvent(
 									"event-child-task-a2-1",
 									NewAbsoluteDurationDelay(0),
-									map[string]string{
-										"attribute-key-event-child-task-a2-1": "attribute-value-event-child-task-a2-1",
+									map[string]attribute.Expression{
+										"attribute-key-event-child-task-a2-1": attribute.String("attribute-value-event-child-task-a2-1"),
 									},
 								),
 							},
-							Attributes: map[string]string{
-								"attribute-key-child-task-a2": "attribute-value-child-task-a2",
+							Attributes: map[string]attribute.Expression{
+								"attribute-key-child-task-a2": attribute.String("attribute-value-child-task-a2"),
 							},
 						},
 					},
@@ -104,8 +107,8 @@ func TestAdapter_Transform(t *testing.T) {
 		// Linked spans
 		{
 			Name: "service-b",
-			Resource: map[string]string{
-				"resource-key-service-b": "resource-value-service-b",
+			Resource: map[string]attribute.Expression{
+				"resource-key-service-b": attribute.String("resource-value-service-b"),
 			},
 			Tasks: []model.Task{
 				{
@@ -118,13 +121,13 @@ func TestAdapter_Transform(t *testing.T) {
 						task.NewEvent(
 							"event-root-task-b-1",
 							NewAbsoluteDurationDelay(0),
-							map[string]string{
-								"attribute-key-event-root-task-b-1": "attribute-value-event-root-task-b-1",
+							map[string]attribute.Expression{
+								"attribute-key-event-root-task-b-1": attribute.String("attribute-value-event-root-task-b-1"),
 							},
 						),
 					},
-					Attributes: map[string]string{
-						"attribute-key-root-task-b": "attribute-value-root-task-b",
+					Attributes: map[string]attribute.Expression{
+						"attribute-key-root-task-b": attribute.String("attribute-value-root-task-b"),
 					},
 					Children: []model.Task{
 						{
@@ -137,13 +140,13 @@ func TestAdapter_Transform(t *testing.T) {
 								task.NewEvent(
 									"event-child-task-b1-1",
 									NewAbsoluteDurationDelay(0),
-									map[string]string{
-										"attribute-key-event-child-task-b1-1": "attribute-value-event-child-task-b1-1",
+									map[string]attribute.Expression{
+										"attribute-key-event-child-task-b1-1": attribute.String("attribute-value-event-child-task-b1-1"),
 									},
 								),
 							},
-							Attributes: map[string]string{
-								"attribute-key-child-task-b1": "attribute-value-child-task-b1",
+							Attributes: map[string]attribute.Expression{
+								"attribute-key-child-task-b1": attribute.String("attribute-value-child-task-b1"),
 							},
 						},
 					},
@@ -157,8 +160,8 @@ func TestAdapter_Transform(t *testing.T) {
 		// child span of another service span
 		{
 			Name: "service-c",
-			Resource: map[string]string{
-				"resource-key-service-c": "resource-value-service-c",
+			Resource: map[string]attribute.Expression{
+				"resource-key-service-c": attribute.String("resource-value-service-c"),
 			},
 			Tasks: []model.Task{
 				{
@@ -171,14 +174,14 @@ func TestAdapter_Transform(t *testing.T) {
 						task.NewEvent(
 							"event-root-task-c-1",
 							NewAbsoluteDurationDelay(0),
-							map[string]string{
-								"attribute-key-event-root-task-c-1": "attribute-value-event-root-task-c-1",
+							map[string]attribute.Expression{
+								"attribute-key-event-root-task-c-1": attribute.String("attribute-value-event-root-task-c-1"),
 							},
 						),
 					},
 					ChildOf: rootTaskAExternalID,
-					Attributes: map[string]string{
-						"attribute-key-root-task-c": "attribute-value-root-task-c",
+					Attributes: map[string]attribute.Expression{
+						"attribute-key-root-task-c": attribute.String("attribute-value-root-task-c"),
 					},
 				},
 			},
@@ -186,8 +189,8 @@ func TestAdapter_Transform(t *testing.T) {
 		// error spans
 		{
 			Name: "service-d",
-			Resource: map[string]string{
-				"resource-key-service-d": "resource-value-service-d",
+			Resource: map[string]attribute.Expression{
+				"resource-key-service-d": attribute.String("resource-value-service-d"),
 			},
 			Tasks: []model.Task{
 				{
@@ -200,14 +203,14 @@ func TestAdapter_Transform(t *testing.T) {
 						task.NewEvent(
 							"event-root-task-d-1",
 							NewAbsoluteDurationDelay(0),
-							map[string]string{
-								"attribute-key-event-root-task-d-1": "attribute-value-event-root-task-d-1",
+							map[string]attribute.Expression{
+								"attribute-key-event-root-task-d-1": attribute.String("attribute-value-event-root-task-d-1"),
 							},
 						),
 					},
 					ChildOf: childTaskA2ExternalID,
-					Attributes: map[string]string{
-						"attribute-key-root-task-d": "attribute-value-root-task-d",
+					Attributes: map[string]attribute.Expression{
+						"attribute-key-root-task-d": attribute.String("attribute-value-root-task-d"),
 					},
 					ConditionalDefinition: []task.ConditionalDefinition{
 						task.NewConditionalDefinition(
@@ -220,9 +223,9 @@ func TestAdapter_Transform(t *testing.T) {
 				},
 			},
 		},
-	})
+	}, nil, nil)
 
-	sim := simulator.New[[]ptrace.Traces](NewAdapter())
+	sim := simulator.New[[]ptrace.Traces](NewAdapter(), random.NewSource(42))
 	traces, err := sim.Run(&blueprint, now)
 
 	// Create a map of span names to their corresponding span for easy lookup
@@ -612,6 +615,95 @@ func TestAdapter_Transform(t *testing.T) {
 	})
 }
 
+func TestAdapter_TransformDegradedSpans(t *testing.T) {
+	dataQuality := func(drop, duplicate float64) task.DataQuality {
+		q, _ := task.NewDataQuality(drop, duplicate, 0)
+		return *q
+	}
+	blueprint := service.NewServiceBlueprint([]model.Service{
+		{
+			Name: "service-a",
+			Tasks: []model.Task{
+				{
+					Name:        "request",
+					Delay:       NewAbsoluteDurationDelay(0),
+					Duration:    NewAbsoluteDurationDuration(1000 * time.Millisecond),
+					Kind:        "server",
+					DataQuality: dataQuality(0, 1),
+					Children: []model.Task{
+						{
+							Name:        "query",
+							Delay:       NewAbsoluteDurationDelay(0),
+							Duration:    NewAbsoluteDurationDuration(500 * time.Millisecond),
+							Kind:        "client",
+							DataQuality: dataQuality(1, 0),
+							Children: []model.Task{
+								{
+									Name:     "fetch",
+									Delay:    NewAbsoluteDurationDelay(0),
+									Duration: NewAbsoluteDurationDuration(100 * time.Millisecond),
+									Kind:     "internal",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:            "service-b",
+			LossProbability: 1,
+			Tasks: []model.Task{
+				{
+					Name:     "job",
+					Delay:    NewAbsoluteDurationDelay(0),
+					Duration: NewAbsoluteDurationDuration(1000 * time.Millisecond),
+					Kind:     "internal",
+				},
+			},
+		},
+	}, nil, nil)
+
+	sim := simulator.New[[]ptrace.Traces](NewAdapter(), random.NewSource(42))
+	traces, err := sim.Run(&blueprint, time.Now())
+	assert.NoError(t, err)
+	assert.Len(t, traces, 2)
+
+	t.Run("skip dropped spans and deliver duplicated spans twice", func(t *testing.T) {
+		spans := traces[0].ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+		var names []string
+		for i := 0; i < spans.Len(); i++ {
+			names = append(names, spans.At(i).Name())
+		}
+		assert.Equal(t, []string{"request", "request", "fetch"}, names)
+		assert.Equal(t, spans.At(0).SpanID(), spans.At(1).SpanID())
+	})
+
+	t.Run("skip resources whose spans are all lost", func(t *testing.T) {
+		assert.Equal(t, 0, traces[1].ResourceSpans().Len())
+	})
+}
+
+func TestPutAttribute(t *testing.T) {
+	attributes := pcommon.NewMap()
+	putAttribute(attributes, "http.request.method", attribute.String("GET"))
+	putAttribute(attributes, "http.response.status_code", attribute.Int(200))
+	putAttribute(attributes, "sampling.ratio", attribute.Double(0.5))
+	putAttribute(attributes, "feature.enabled", attribute.Bool(true))
+	putAttribute(attributes, "tags", attribute.StringSlice([]string{"a", "b"}))
+	putAttribute(attributes, "codes", attribute.IntSlice([]int64{1, 2}))
+
+	expected := map[string]any{
+		"http.request.method":       "GET",
+		"http.response.status_code": int64(200),
+		"sampling.ratio":            0.5,
+		"feature.enabled":           true,
+		"tags":                      []any{"a", "b"},
+		"codes":                     []any{int64(1), int64(2)},
+	}
+	assert.Equal(t, expected, attributes.AsRaw())
+}
+
 func NewAbsoluteDurationDelay(duration time.Duration) task.Delay {
 	e, _ := taskduration.NewAbsoluteDuration(duration)
 	d, _ := task.NewDelay(e)
@@ -1,6 +1,8 @@
 package blueprint
 
 import (
+	"github.com/k4ji/tracesimulationreceiver/internal/ratecontrol"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/attribute"
 	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
 )
 
@@ -8,4 +10,16 @@ import (
 type Blueprint interface {
 	// Interpret converts the blueprint into a slice of task.TreeNode, which then can be used to create a trace
 	Interpret() ([]*task.TreeNode, error)
+	// Variables returns the trace-scoped variables, which are resolved once per trace
+	Variables() map[string]attribute.Expression
+	// DedicatedFlows returns the flows that are generated at their own rate, which are excluded from Interpret
+	DedicatedFlows() []DedicatedFlow
+}
+
+// DedicatedFlow represents a flow of traces that is generated at its own rate
+type DedicatedFlow struct {
+	// Blueprint interprets only the traces of the flow
+	Blueprint Blueprint
+	// Rate decides when the traces of the flow arrive
+	Rate *ratecontrol.Controller
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/blueprint"
 	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/blueprint/service/model"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/attribute"
 	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
 )
 
@@ -12,27 +13,44 @@ var _ blueprint.Blueprint = (*Blueprint)(nil)
 
 // Blueprint represents a blueprint based on tasks grouped by services
 type Blueprint struct {
-	services []model.Service
+	services   []model.Service
+	variables  map[string]attribute.Expression
+	randomness func() float64 // Used to pick a flow from the mix of flows
+	dedicated  *model.Task    // Root task of the only flow to interpret, if the blueprint is for a dedicated flow
 }
 
 // NewServiceBlueprint creates a new service blueprint
-func NewServiceBlueprint(services []model.Service) Blueprint {
+func NewServiceBlueprint(services []model.Service, variables map[string]attribute.Expression, randomness func() float64) Blueprint {
 	return Blueprint{
-		services: services,
+		services:   services,
+		variables:  variables,
+		randomness: randomness,
 	}
 }
 
+// Variables returns the trace-scoped variables
+func (sb *Blueprint) Variables() map[string]attribute.Expression {
+	return sb.variables
+}
+
 func (sb *Blueprint) Interpret() ([]*task.TreeNode, error) {
 	rootTaskNodes := make([]*task.TreeNode, 0)
 	TasksByExternalID := make(map[task.ExternalID]*task.TreeNode)
 
+	flowFilter := sb.flowFilter()
+	// External IDs of the tasks that are not included in this interpretation
+	droppedExternalIDs := make(map[task.ExternalID]struct{})
+
 	// Convert each service to trees of tasks
 	for _, service := range sb.services {
-		serviceRootTaskNodes, err := service.To()
+		serviceRootTaskNodes, dropped, err := service.To(flowFilter)
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert service %s to task tree: %w", service.Name, err)
 		}
 		rootTaskNodes = append(rootTaskNodes, serviceRootTaskNodes...)
+		for _, id := range dropped {
+			droppedExternalIDs[id] = struct{}{}
+		}
 	}
 
 	// Add all task nodes to the map by ExternalID while checking for duplicates
@@ -63,6 +81,11 @@ func (sb *Blueprint) Interpret() ([]*task.TreeNode, error) {
 		if rootTaskNode.Definition().ChildOf() != nil {
 			parentSpan := TasksByExternalID[*rootTaskNode.Definition().ChildOf()]
 			if parentSpan == nil {
+				// a task whose parent is not included is not included either, along with its descendants
+				if _, dropped := droppedExternalIDs[*rootTaskNode.Definition().ChildOf()]; dropped {
+					dropTaskNode(rootTaskNode, TasksByExternalID, droppedExternalIDs)
+					continue
+				}
 				return nil, fmt.Errorf("parent task not found for %s", *rootTaskNode.Definition().ChildOf())
 			}
 			if err := parentSpan.AddChild(rootTaskNode); err != nil {
@@ -73,5 +96,119 @@ func (sb *Blueprint) Interpret() ([]*task.TreeNode, error) {
 		}
 	}
 
+	// Skip links to the tasks that are not included
+	if len(droppedExternalIDs) > 0 {
+		var removeLinks func(node *task.TreeNode)
+		removeLinks = func(node *task.TreeNode) {
+			node.Definition().RemoveLinksTo(droppedExternalIDs)
+			for _, child := range node.Children() {
+				removeLinks(child)
+			}
+		}
+		for _, rootTaskNode := range traceRootTaskNodes {
+			removeLinks(rootTaskNode)
+		}
+	}
+
 	return traceRootTaskNodes, nil
 }
+
+// dropTaskNode marks the task node and its descendants as not included
+func dropTaskNode(node *task.TreeNode, tasksByExternalID map[task.ExternalID]*task.TreeNode, droppedExternalIDs map[task.ExternalID]struct{}) {
+	if node.Definition().ExternalID() != nil {
+		delete(tasksByExternalID, *node.Definition().ExternalID())
+		droppedExternalIDs[*node.Definition().ExternalID()] = struct{}{}
+	}
+	for _, child := range node.Children() {
+		dropTaskNode(child, tasksByExternalID, droppedExternalIDs)
+	}
+}
+
+// DedicatedFlows returns the flows that are generated at their own rate
+func (sb *Blueprint) DedicatedFlows() []blueprint.DedicatedFlow {
+	if sb.dedicated != nil {
+		return nil
+	}
+	var flows []blueprint.DedicatedFlow
+	for _, root := range sb.flowRoots() {
+		if root.Flow != nil && root.Flow.Rate != nil {
+			view := *sb
+			view.dedicated = root
+			flows = append(flows, blueprint.DedicatedFlow{Blueprint: &view, Rate: root.Flow.Rate})
+		}
+	}
+	return flows
+}
+
+// flowRoots returns the root tasks of the flows, which are the tasks that have no parent
+func (sb *Blueprint) flowRoots() []*model.Task {
+	var roots []*model.Task
+	for i := range sb.services {
+		for j := range sb.services[i].Tasks {
+			if sb.services[i].Tasks[j].ChildOf == nil {
+				roots = append(roots, &sb.services[i].Tasks[j])
+			}
+		}
+	}
+	return roots
+}
+
+// flowFilter returns the filter of the tasks to interpret.
+// If any flow in the mix has a weight, one flow is picked in proportion to the weights (1 if unspecified).
+// Otherwise, all the flows in the mix are interpreted.
+func (sb *Blueprint) flowFilter() func(task *model.Task) bool {
+	if sb.dedicated != nil {
+		return func(task *model.Task) bool {
+			return task.ChildOf != nil || task == sb.dedicated
+		}
+	}
+	var mix []*model.Task
+	weighted := false
+	for _, root := range sb.flowRoots() {
+		if root.Flow != nil && root.Flow.Rate != nil {
+			continue
+		}
+		mix = append(mix, root)
+		weighted = weighted || root.Flow != nil
+	}
+	if !weighted {
+		return func(task *model.Task) bool {
+			return task.ChildOf != nil || task.Flow == nil || task.Flow.Rate == nil
+		}
+	}
+	picked := pickFlow(mix, sb.randomness)
+	return func(task *model.Task) bool {
+		return task.ChildOf != nil || task == picked
+	}
+}
+
+// pickFlow picks one of the flows in proportion to their weights
+func pickFlow(roots []*model.Task, randomness func() float64) *model.Task {
+	weight := func(root *model.Task) float64 {
+		if root.Flow == nil {
+			return 1
+		}
+		return root.Flow.Weight
+	}
+	total := 0.0
+	for _, root := range roots {
+		total += weight(root)
+	}
+	if total <= 0 {
+		return nil
+	}
+	r := randomness() * total
+	var last *model.Task
+	for _, root := range roots {
+		if weight(root) <= 0 {
+			continue
+		}
+		if r < weight(root) {
+			return root
+		}
+		r -= weight(root)
+		last = root
+	}
+	// guard against floating point errors
+	return last
+}
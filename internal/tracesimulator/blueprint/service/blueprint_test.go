@@ -1,7 +1,9 @@
 package service
 
 import (
+	"github.com/k4ji/tracesimulationreceiver/internal/ratecontrol"
 	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/blueprint/service/model"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/attribute"
 	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
 	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task/taskduration"
 	"github.com/stretchr/testify/assert"
@@ -15,8 +17,8 @@ func TestBlueprint_Interpret(t *testing.T) {
 		services := []model.Service{
 			{
 				Name: "service-a",
-				Resource: map[string]string{
-					"env": "test",
+				Resource: map[string]attribute.Expression{
+					"env": attribute.String("test"),
 				},
 				Tasks: []model.Task{
 					{
@@ -25,8 +27,8 @@ func TestBlueprint_Interpret(t *testing.T) {
 						Delay:      NewAbsoluteDurationDelay(0),
 						Duration:   NewAbsoluteDurationDuration(1000 * time.Millisecond),
 						Kind:       "server",
-						Attributes: map[string]string{
-							"key1": "value1",
+						Attributes: map[string]attribute.Expression{
+							"key1": attribute.String("value1"),
 						},
 						Children: []model.Task{
 							{
@@ -35,8 +37,8 @@ func TestBlueprint_Interpret(t *testing.T) {
 								Delay:      NewAbsoluteDurationDelay(time.Duration(500) * time.Millisecond),
 								Duration:   NewAbsoluteDurationDuration(500 * time.Millisecond),
 								Kind:       "producer",
-								Attributes: map[string]string{
-									"key2": "value2",
+								Attributes: map[string]attribute.Expression{
+									"key2": attribute.String("value2"),
 								},
 								ConditionalDefinition: []task.ConditionalDefinition{
 									task.NewConditionalDefinition(
@@ -69,7 +71,7 @@ func TestBlueprint_Interpret(t *testing.T) {
 			},
 		}
 
-		blueprint := NewServiceBlueprint(services)
+		blueprint := NewServiceBlueprint(services, nil, nil)
 
 		rootTaskNodes, err := blueprint.Interpret()
 
@@ -79,9 +81,9 @@ func TestBlueprint_Interpret(t *testing.T) {
 		taskA1Resource := rootTaskNodes[0].Definition().Resource()
 		assert.Equal(t, "task-a1", rootTaskNodes[0].Definition().Name())
 		assert.Equal(t, "service-a", taskA1Resource.Name())
-		assert.Equal(t, "test", taskA1Resource.Attributes()["env"])
+		assert.Equal(t, attribute.String("test"), taskA1Resource.Attributes()["env"])
 		assert.Equal(t, task.KindServer, rootTaskNodes[0].Definition().Kind())
-		assert.Equal(t, map[string]string{"key1": "value1"}, rootTaskNodes[0].Definition().Attributes())
+		assert.Equal(t, map[string]attribute.Expression{"key1": attribute.String("value1")}, rootTaskNodes[0].Definition().Attributes())
 		assert.Equal(t, NewAbsoluteDurationDelay(0), rootTaskNodes[0].Definition().Delay())
 		assert.Equal(t, NewAbsoluteDurationDuration(time.Duration(1000)*time.Millisecond), rootTaskNodes[0].Definition().Duration())
 		assert.Len(t, rootTaskNodes[0].Definition().ConditionalDefinitions(), 0)
@@ -89,9 +91,9 @@ func TestBlueprint_Interpret(t *testing.T) {
 		taskA1ChildResource := rootTaskNodes[0].Children()[0].Definition().Resource()
 		assert.Equal(t, "task-a1-child", rootTaskNodes[0].Children()[0].Definition().Name())
 		assert.Equal(t, "service-a", taskA1ChildResource.Name())
-		assert.Equal(t, "test", taskA1ChildResource.Attributes()["env"])
+		assert.Equal(t, attribute.String("test"), taskA1ChildResource.Attributes()["env"])
 		assert.Equal(t, task.KindProducer, rootTaskNodes[0].Children()[0].Definition().Kind())
-		assert.Equal(t, map[string]string{"key2": "value2"}, rootTaskNodes[0].Children()[0].Definition().Attributes())
+		assert.Equal(t, map[string]attribute.Expression{"key2": attribute.String("value2")}, rootTaskNodes[0].Children()[0].Definition().Attributes())
 		assert.Equal(t, NewAbsoluteDurationDelay(time.Duration(500)*time.Millisecond), rootTaskNodes[0].Children()[0].Definition().Delay())
 		assert.Equal(t, NewAbsoluteDurationDuration(time.Duration(500)*time.Millisecond), rootTaskNodes[0].Children()[0].Definition().Duration())
 		assert.Equal(t, 0.1, rootTaskNodes[0].Children()[0].Definition().ConditionalDefinitions()[0].Condition().Probabilistic().Threshold())
@@ -100,7 +102,7 @@ func TestBlueprint_Interpret(t *testing.T) {
 		taskA2Resource := rootTaskNodes[1].Definition().Resource()
 		assert.Equal(t, "task-a2", rootTaskNodes[1].Definition().Name())
 		assert.Equal(t, "service-a", taskA2Resource.Name())
-		assert.Equal(t, "test", taskA2Resource.Attributes()["env"])
+		assert.Equal(t, attribute.String("test"), taskA2Resource.Attributes()["env"])
 		assert.Equal(t, task.KindInternal, rootTaskNodes[1].Definition().Kind())
 		assert.Equal(t, NewAbsoluteDurationDelay(0), rootTaskNodes[1].Definition().Delay())
 		assert.Equal(t, NewAbsoluteDurationDuration(time.Duration(100)*time.Millisecond), rootTaskNodes[1].Definition().Duration())
@@ -139,7 +141,7 @@ func TestBlueprint_Interpret(t *testing.T) {
 				},
 			},
 		}
-		blueprint := NewServiceBlueprint(services)
+		blueprint := NewServiceBlueprint(services, nil, nil)
 
 		rootTaskNodes, err := blueprint.Interpret()
 
@@ -182,7 +184,7 @@ func TestBlueprint_Interpret(t *testing.T) {
 				},
 			}
 
-		blueprint := NewServiceBlueprint(services)
+		blueprint := NewServiceBlueprint(services, nil, nil)
 
 		rootTaskNodes, err := blueprint.Interpret()
 
@@ -220,7 +222,7 @@ func TestBlueprint_Interpret(t *testing.T) {
 				},
 			},
 		}
-		blueprint := NewServiceBlueprint(services)
+		blueprint := NewServiceBlueprint(services, nil, nil)
 
 		rootTaskNodes, err := blueprint.Interpret()
 
@@ -237,6 +239,170 @@ func TestBlueprint_Interpret(t *testing.T) {
 		assert.Equal(t, taskANode.Definition().ExternalID(), taskBNode.Definition().LinkedTo()[0])
 	})
 
+	t.Run("drop tasks that are not included along with their descendants and links to them", func(t *testing.T) {
+		cacheRefreshID, _ := task.NewExternalID("cache-refresh")
+		cacheWriteID, _ := task.NewExternalID("cache-write")
+		never := &model.Inclusion{Probability: 0, Randomness: func() float64 { return 0.5 }}
+		always := &model.Inclusion{Probability: 1, Randomness: func() float64 { return 0.5 }}
+
+		services := []model.Service{
+			{
+				Name: "service-a",
+				Tasks: []model.Task{
+					{
+						Name:     "request",
+						Kind:     "server",
+						Delay:    NewAbsoluteDurationDelay(0),
+						Duration: NewAbsoluteDurationDuration(1 * time.Second),
+						Children: []model.Task{
+							{
+								Name:       "cache-refresh",
+								ExternalID: cacheRefreshID,
+								Kind:       "client",
+								Delay:      NewAbsoluteDurationDelay(0),
+								Duration:   NewAbsoluteDurationDuration(100 * time.Millisecond),
+								Inclusion:  never,
+							},
+							{
+								Name:      "render",
+								Kind:      "internal",
+								Delay:     NewAbsoluteDurationDelay(0),
+								Duration:  NewAbsoluteDurationDuration(100 * time.Millisecond),
+								Inclusion: always,
+								LinkedTo:  []*task.ExternalID{cacheRefreshID},
+							},
+						},
+					},
+				},
+			},
+			{
+				Name: "service-b",
+				Tasks: []model.Task{
+					{
+						Name:       "cache-write",
+						ExternalID: cacheWriteID,
+						Kind:       "server",
+						ChildOf:    cacheRefreshID,
+						Delay:      NewAbsoluteDurationDelay(0),
+						Duration:   NewAbsoluteDurationDuration(50 * time.Millisecond),
+					},
+				},
+			},
+			{
+				Name: "service-c",
+				Tasks: []model.Task{
+					{
+						Name:     "cache-write-audit",
+						Kind:     "server",
+						ChildOf:  cacheWriteID,
+						Delay:    NewAbsoluteDurationDelay(0),
+						Duration: NewAbsoluteDurationDuration(10 * time.Millisecond),
+					},
+				},
+			},
+		}
+		blueprint := NewServiceBlueprint(services, nil, nil)
+
+		result, err := blueprint.Interpret()
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 1)
+		assert.Equal(t, "request", result[0].Definition().Name())
+		assert.Len(t, result[0].Children(), 1)
+		render := result[0].Children()[0]
+		assert.Equal(t, "render", render.Definition().Name())
+		assert.Empty(t, render.Definition().LinkedTo())
+	})
+
+	t.Run("pick a flow from the mix according to the weights", func(t *testing.T) {
+		loginID, _ := task.NewExternalID("login")
+		newRoot := func(name string, externalID *task.ExternalID, flow *model.Flow) model.Task {
+			return model.Task{
+				Name:       name,
+				ExternalID: externalID,
+				Kind:       "server",
+				Delay:      NewAbsoluteDurationDelay(0),
+				Duration:   NewAbsoluteDurationDuration(100 * time.Millisecond),
+				Flow:       flow,
+			}
+		}
+		services := []model.Service{
+			{
+				Name: "frontend",
+				Tasks: []model.Task{
+					newRoot("read_timeline", nil, &model.Flow{Weight: 3}),
+					newRoot("send_message", nil, &model.Flow{Weight: 0}),
+					newRoot("login", loginID, nil),
+				},
+			},
+			{
+				Name: "auth",
+				Tasks: []model.Task{
+					{
+						Name:     "verify_credentials",
+						Kind:     "server",
+						ChildOf:  loginID,
+						Delay:    NewAbsoluteDurationDelay(0),
+						Duration: NewAbsoluteDurationDuration(50 * time.Millisecond),
+					},
+				},
+			},
+		}
+
+		testCases := []struct {
+			randomness float64
+			expected   string
+			children   int
+		}{
+			// read_timeline has 3/4 of the traffic, send_message none and login 1/4
+			{randomness: 0, expected: "read_timeline"},
+			{randomness: 0.74, expected: "read_timeline"},
+			{randomness: 0.75, expected: "login", children: 1},
+			{randomness: 0.99, expected: "login", children: 1},
+		}
+		for _, tc := range testCases {
+			blueprint := NewServiceBlueprint(services, nil, func() float64 { return tc.randomness })
+
+			result, err := blueprint.Interpret()
+
+			assert.NoError(t, err)
+			assert.Len(t, result, 1)
+			assert.Equal(t, tc.expected, result[0].Definition().Name())
+			assert.Len(t, result[0].Children(), tc.children)
+		}
+	})
+
+	t.Run("interpret the flows with their own rate separately", func(t *testing.T) {
+		profile, _ := ratecontrol.NewConstantProfile(1)
+		rate, _ := ratecontrol.NewController(profile, ratecontrol.ArrivalConstant, mathRand.Float64)
+		services := []model.Service{
+			{
+				Name: "frontend",
+				Tasks: []model.Task{
+					{Name: "read_timeline", Delay: NewAbsoluteDurationDelay(0), Duration: NewAbsoluteDurationDuration(time.Second)},
+					{Name: "health_check", Delay: NewAbsoluteDurationDelay(0), Duration: NewAbsoluteDurationDuration(time.Second), Flow: &model.Flow{Weight: 1, Rate: rate}},
+					{Name: "login", Delay: NewAbsoluteDurationDelay(0), Duration: NewAbsoluteDurationDuration(time.Second)},
+				},
+			},
+		}
+		blueprint := NewServiceBlueprint(services, nil, mathRand.Float64)
+
+		result, err := blueprint.Interpret()
+		assert.NoError(t, err)
+		assert.Len(t, result, 2)
+		assert.Equal(t, "read_timeline", result[0].Definition().Name())
+		assert.Equal(t, "login", result[1].Definition().Name())
+
+		flows := blueprint.DedicatedFlows()
+		assert.Len(t, flows, 1)
+		assert.Same(t, rate, flows[0].Rate)
+		assert.Empty(t, flows[0].Blueprint.DedicatedFlows())
+		result, err = flows[0].Blueprint.Interpret()
+		assert.NoError(t, err)
+		assert.Len(t, result, 1)
+		assert.Equal(t, "health_check", result[0].Definition().Name())
+	})
+
 	t.Run("return error if the specified parent task is not found", func(t *testing.T) {
 		parentID, _ := task.NewExternalID("non-existent-parent")
 
@@ -252,7 +418,7 @@ func TestBlueprint_Interpret(t *testing.T) {
 				},
 			},
 		}
-		blueprint := NewServiceBlueprint(services)
+		blueprint := NewServiceBlueprint(services, nil, nil)
 
 		_, err := blueprint.Interpret()
 
@@ -285,7 +451,7 @@ func TestBlueprint_Interpret(t *testing.T) {
 				},
 			},
 		}
-		blueprint := NewServiceBlueprint(services)
+		blueprint := NewServiceBlueprint(services, nil, nil)
 
 		_, err := blueprint.Interpret()
 
@@ -321,7 +487,7 @@ func TestBlueprint_Interpret(t *testing.T) {
 				},
 			},
 		}
-		blueprint := NewServiceBlueprint(services)
+		blueprint := NewServiceBlueprint(services, nil, nil)
 
 		_, err := blueprint.Interpret()
 
@@ -0,0 +1,11 @@
+package model
+
+import "github.com/k4ji/tracesimulationreceiver/internal/ratecontrol"
+
+// Flow represents the traffic of the traces whose root is a task, e.g. an endpoint of a user-facing service
+type Flow struct {
+	// Weight is the share of the flow in the mix of flows that are not generated at their own rate
+	Weight float64
+	// Rate decides when the traces of the flow arrive, if the flow is generated at its own rate
+	Rate *ratecontrol.Controller
+}
@@ -0,0 +1,14 @@
+package model
+
+// Inclusion represents the probability of a task being included in a trace, e.g. a retry or a cache refresh
+type Inclusion struct {
+	// Probability is the probability of the task being included, between 0 and 1
+	Probability float64
+	// Randomness is used to decide whether the task is included
+	Randomness func() float64
+}
+
+// Include decides whether the task is included
+func (i *Inclusion) Include() bool {
+	return i.Randomness() < i.Probability
+}
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"math"
+	"time"
+)
+
+// Repeat represents the repetition of a task as siblings, e.g. N+1 queries or a batch of lookups
+type Repeat struct {
+	// Minimum is the minimum number of repetitions
+	Minimum int
+	// Maximum is the maximum number of repetitions
+	Maximum int
+	// Index is the name of the variable that holds the zero-based index of the repetition, if any
+	Index *string
+	// Stagger is the delay added to each repetition relative to the previous one
+	Stagger time.Duration
+	// Randomness is used to draw the number of repetitions between Minimum and Maximum
+	Randomness func() float64
+}
+
+// Count draws the number of repetitions
+func (r *Repeat) Count() int {
+	if r.Maximum <= r.Minimum {
+		return r.Minimum
+	}
+	span := float64(r.Maximum-r.Minimum) + 1
+	return r.Minimum + int(math.Min(math.Floor(r.Randomness()*span), span-1))
+}
@@ -2,26 +2,40 @@ package model
 
 import (
 	"fmt"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/attribute"
 	domainTask "github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
 )
 
 // Service represents a service that executes tasks
 type Service struct {
 	Name     string
-	Resource map[string]string
+	Resource map[string]attribute.Expression
 	Tasks    []Task
+	// LossProbability is the probability of all the spans of the service being lost in a trace
+	LossProbability float64
+	// ClockSkew is how far the clock of the service is off, applied to the timestamps of its spans and events
+	ClockSkew domainTask.ClockSkew
 }
 
-// To converts the Service to a slice of task.TreeNode
-func (s Service) To() ([]*domainTask.TreeNode, error) {
+// To converts the Service to a slice of task.TreeNode.
+// The tasks rejected by the filter are not converted, and the external IDs of them and their descendants are
+// returned along with the ones of the tasks that are not included.
+func (s Service) To(filter func(task *Task) bool) ([]*domainTask.TreeNode, []domainTask.ExternalID, error) {
 	rootTaskNodes := make([]*domainTask.TreeNode, 0)
-	for _, task := range s.Tasks {
-		resource := domainTask.NewResource(s.Name, s.Resource)
-		rootTaskNode, err := task.ToRootNodeWithResource(resource)
+	var dropped []domainTask.ExternalID
+	for i := range s.Tasks {
+		task := &s.Tasks[i]
+		if filter != nil && !filter(task) {
+			task.collectExternalIDs(&dropped)
+			continue
+		}
+		resource := domainTask.NewResource(s.Name, s.Resource).WithLossProbability(s.LossProbability).WithClockSkew(s.ClockSkew)
+		nodes, droppedIDs, err := task.ToRootNodesWithResource(resource)
 		if err != nil {
-			return nil, fmt.Errorf("failed to convert task %s to root node: %w", task.Name, err)
+			return nil, nil, fmt.Errorf("failed to convert task %s to root node: %w", task.Name, err)
 		}
-		rootTaskNodes = append(rootTaskNodes, rootTaskNode)
+		rootTaskNodes = append(rootTaskNodes, nodes...)
+		dropped = append(dropped, droppedIDs...)
 	}
-	return rootTaskNodes, nil
+	return rootTaskNodes, dropped, nil
 }
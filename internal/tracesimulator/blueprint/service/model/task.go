@@ -1,7 +1,10 @@
 package model
 
 import (
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/attribute"
 	domainTask "github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
+	"strings"
+	"time"
 )
 
 // Task represents an operation that can be performed by a service
@@ -11,67 +14,118 @@ type Task struct {
 	Delay                 domainTask.Delay
 	Duration              domainTask.Duration
 	Kind                  string
-	Attributes            map[string]string
+	Attributes            map[string]attribute.Expression
 	Children              []Task
 	ChildOf               *domainTask.ExternalID
 	LinkedTo              []*domainTask.ExternalID
 	Events                []domainTask.Event
 	ConditionalDefinition []domainTask.ConditionalDefinition
+	Repeat                *Repeat
+	Schedule              domainTask.Schedule
+	Variants              *Variants
+	Inclusion             *Inclusion
+	Flow                  *Flow
+	ErrorPropagation      domainTask.ErrorPropagation
+	Retry                 *domainTask.Retry
+	Timeout               *domainTask.Timeout
+	DataQuality           domainTask.DataQuality
 }
 
-// ToRootNodeWithResource converts the Task to a root node with the given resource
-func (t *Task) ToRootNodeWithResource(resource domainTask.Resource) (*domainTask.TreeNode, error) {
-	def := domainTask.NewDefinition(
-		t.Name,
-		true,
-		resource,
-		t.Attributes,
-		domainTask.FromString(t.Kind),
-		t.ExternalID,
-		t.Delay,
-		t.Duration,
-		t.ChildOf,
-		t.LinkedTo,
-		t.Events,
-		t.ConditionalDefinition,
-	)
-	node := domainTask.NewTreeNode(def)
-	for _, child := range t.Children {
-		childNode, err := child.toChildNodeWithResource(resource)
-		if err != nil {
-			return nil, err
+// ToRootNodesWithResource converts the Task to root nodes with the given resource.
+// A repeated task is converted to as many root nodes as the number of repetitions.
+// It also returns the external IDs of the tasks that are not included, so that references to them can be dropped.
+func (t *Task) ToRootNodesWithResource(resource domainTask.Resource) ([]*domainTask.TreeNode, []domainTask.ExternalID, error) {
+	var dropped []domainTask.ExternalID
+	nodes, err := t.toNodesWithResource(resource, true, nil, &dropped)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nodes, dropped, nil
+}
+
+func (t *Task) toNodesWithResource(resource domainTask.Resource, isRoot bool, variables map[string]attribute.Value, dropped *[]domainTask.ExternalID) ([]*domainTask.TreeNode, error) {
+	count := 1
+	if t.Repeat != nil {
+		count = t.Repeat.Count()
+	}
+	nodes := make([]*domainTask.TreeNode, 0, count)
+	for i := 0; i < count; i++ {
+		// the inclusion is decided for each instance, and an excluded task drops its descendants as well
+		if t.Inclusion != nil && !t.Inclusion.Include() {
+			t.collectExternalIDs(dropped)
+			continue
+		}
+		name := t.Name
+		delay := t.Delay
+		attributes := t.Attributes
+		children := t.Children
+		// a variant is picked for each instance, so that repetitions can take different shapes
+		if t.Variants != nil {
+			attributes, children = t.Variants.Pick().apply(attributes, children)
 		}
-		if err = node.AddChild(childNode); err != nil {
-			return nil, err
+		var bound map[string]attribute.Value
+		if t.Repeat != nil {
+			if t.Repeat.Index != nil {
+				bound = map[string]attribute.Value{attribute.Qualify(attribute.ScopeRepeat, *t.Repeat.Index): attribute.Int(int64(i))}
+			}
+			if t.Repeat.Stagger > 0 {
+				delay = delay.WithOffset(t.Repeat.Stagger * time.Duration(i))
+			}
 		}
+		// index variables of this task and its ancestors can be used in the name
+		inScope := attribute.Merge(variables, bound)
+		for k, v := range inScope {
+			name = strings.ReplaceAll(name, "${"+k+"}", v.String())
+		}
+		var childOf *domainTask.ExternalID
+		if isRoot {
+			childOf = t.ChildOf
+		}
+		def := domainTask.NewDefinition(
+			name,
+			isRoot,
+			resource,
+			attributes,
+			domainTask.FromString(t.Kind),
+			t.ExternalID,
+			delay,
+			t.Duration,
+			childOf,
+			t.LinkedTo,
+			t.Events,
+			t.ConditionalDefinition,
+			domainTask.DefinitionOptions{
+				Variables:        bound,
+				Schedule:         t.Schedule,
+				ErrorPropagation: t.ErrorPropagation,
+				Retry:            t.Retry,
+				Timeout:          t.Timeout,
+				DataQuality:      t.DataQuality,
+			},
+		)
+		node := domainTask.NewTreeNode(def)
+		for _, child := range children {
+			childNodes, err := child.toNodesWithResource(resource, false, inScope, dropped)
+			if err != nil {
+				return nil, err
+			}
+			for _, childNode := range childNodes {
+				if err = node.AddChild(childNode); err != nil {
+					return nil, err
+				}
+			}
+		}
+		nodes = append(nodes, node)
 	}
-	return node, nil
+	return nodes, nil
 }
 
-func (t *Task) toChildNodeWithResource(resource domainTask.Resource) (*domainTask.TreeNode, error) {
-	def := domainTask.NewDefinition(
-		t.Name,
-		false,
-		resource,
-		t.Attributes,
-		domainTask.FromString(t.Kind),
-		t.ExternalID,
-		t.Delay,
-		t.Duration,
-		nil,
-		t.LinkedTo,
-		t.Events,
-		t.ConditionalDefinition,
-	)
-	node := domainTask.NewTreeNode(def)
+// collectExternalIDs collects the external IDs of the task and its descendants
+func (t *Task) collectExternalIDs(externalIDs *[]domainTask.ExternalID) {
+	if t.ExternalID != nil {
+		*externalIDs = append(*externalIDs, *t.ExternalID)
+	}
 	for _, child := range t.Children {
-		childNode, err := child.toChildNodeWithResource(resource)
-		if err != nil {
-			return nil, err
-		}
-		if err = node.AddChild(childNode); err != nil {
-			return nil, err
-		}
+		child.collectExternalIDs(externalIDs)
 	}
-	return node, nil
 }
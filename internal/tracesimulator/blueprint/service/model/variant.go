@@ -0,0 +1,64 @@
+package model
+
+import "github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/attribute"
+
+// Variant represents one of the alternative shapes of a task, e.g. a cache hit or a cache miss
+type Variant struct {
+	// Weight is the relative likelihood of the variant being picked
+	Weight float64
+	// Attributes are added to the attributes of the task, overriding the ones with the same key
+	Attributes map[string]attribute.Expression
+	// Children are added after the children of the task
+	Children []Task
+}
+
+// Variants represents the alternative shapes of a task, one of which is picked for each instance of the task
+type Variants struct {
+	// Choices are the variants to pick from
+	Choices []Variant
+	// Randomness is used to pick a variant in proportion to its weight
+	Randomness func() float64
+}
+
+// Pick picks a variant in proportion to its weight
+func (v *Variants) Pick() Variant {
+	total := 0.0
+	for _, c := range v.Choices {
+		total += c.Weight
+	}
+	r := v.Randomness() * total
+	for _, c := range v.Choices {
+		if r < c.Weight {
+			return c
+		}
+		r -= c.Weight
+	}
+	// guard against floating point errors by falling back to the last variant with a positive weight
+	for i := len(v.Choices) - 1; i > 0; i-- {
+		if v.Choices[i].Weight > 0 {
+			return v.Choices[i]
+		}
+	}
+	return v.Choices[0]
+}
+
+// apply returns the attributes and children of the task with the variant applied
+func (v Variant) apply(attributes map[string]attribute.Expression, children []Task) (map[string]attribute.Expression, []Task) {
+	if len(v.Attributes) > 0 {
+		merged := make(map[string]attribute.Expression, len(attributes)+len(v.Attributes))
+		for k, e := range attributes {
+			merged[k] = e
+		}
+		for k, e := range v.Attributes {
+			merged[k] = e
+		}
+		attributes = merged
+	}
+	if len(v.Children) > 0 {
+		merged := make([]Task, 0, len(children)+len(v.Children))
+		merged = append(merged, children...)
+		merged = append(merged, v.Children...)
+		children = merged
+	}
+	return attributes, children
+}
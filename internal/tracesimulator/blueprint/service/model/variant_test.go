@@ -0,0 +1,70 @@
+package model
+
+import (
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/attribute"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestVariants_Pick(t *testing.T) {
+	choices := []Variant{
+		{Weight: 1, Attributes: map[string]attribute.Expression{"cache": attribute.String("hit")}},
+		{Weight: 2, Attributes: map[string]attribute.Expression{"cache": attribute.String("miss")}},
+		{Weight: 0, Attributes: map[string]attribute.Expression{"cache": attribute.String("bypass")}},
+	}
+
+	testCases := []struct {
+		name       string
+		choices    []Variant
+		randomness float64
+		expected   string
+	}{
+		{name: "pick the first variant", choices: choices, randomness: 0, expected: "hit"},
+		{name: "pick the variant in proportion to its weight", choices: choices, randomness: 0.5, expected: "miss"},
+		{name: "fall back to the last variant with a positive weight", choices: choices, randomness: 1, expected: "miss"},
+		{
+			name:       "fall back to the first variant if no variant has a positive weight",
+			choices:    []Variant{choices[2], {Weight: 0, Attributes: map[string]attribute.Expression{"cache": attribute.String("none")}}},
+			randomness: 0.5,
+			expected:   "bypass",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			variants := Variants{Choices: tc.choices, Randomness: func() float64 { return tc.randomness }}
+			assert.Equal(t, attribute.String(tc.expected), variants.Pick().Attributes["cache"])
+		})
+	}
+}
+
+func TestVariant_apply(t *testing.T) {
+	attributes := map[string]attribute.Expression{
+		"cache":      attribute.String("unknown"),
+		"http.route": attribute.String("/items"),
+	}
+	children := []Task{{Name: "auth"}}
+
+	t.Run("merge attributes and append children", func(t *testing.T) {
+		variant := Variant{
+			Attributes: map[string]attribute.Expression{"cache": attribute.String("miss"), "db.system": attribute.String("postgresql")},
+			Children:   []Task{{Name: "query"}},
+		}
+		mergedAttributes, mergedChildren := variant.apply(attributes, children)
+		assert.Equal(t, map[string]attribute.Expression{
+			"cache":      attribute.String("miss"),
+			"http.route": attribute.String("/items"),
+			"db.system":  attribute.String("postgresql"),
+		}, mergedAttributes)
+		assert.Equal(t, []Task{{Name: "auth"}, {Name: "query"}}, mergedChildren)
+		// the attributes and children of the task are left as they are
+		assert.Equal(t, attribute.String("unknown"), attributes["cache"])
+		assert.Len(t, children, 1)
+	})
+
+	t.Run("keep attributes and children without the ones of the variant", func(t *testing.T) {
+		mergedAttributes, mergedChildren := Variant{}.apply(attributes, children)
+		assert.Equal(t, attributes, mergedAttributes)
+		assert.Equal(t, children, mergedChildren)
+	})
+}
@@ -0,0 +1,42 @@
+package attribute
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Expression represents an attribute value that is resolved for each span instance.
+// variables holds the values of the trace-scoped variables of the trace that the span belongs to.
+type Expression interface {
+	Resolve(variables map[string]Value) (*Value, error)
+}
+
+var _ Expression = (*Value)(nil)
+
+// Resolve returns the value itself, since a static value always resolves to the same value
+func (v Value) Resolve(_ map[string]Value) (*Value, error) {
+	return &v, nil
+}
+
+// ResolveAll resolves all the expressions in the map.
+// Expressions are resolved in the order of their keys so that the results are reproducible.
+func ResolveAll(expressions map[string]Expression, variables map[string]Value) (map[string]Value, error) {
+	if expressions == nil {
+		return nil, nil
+	}
+	keys := make([]string, 0, len(expressions))
+	for k := range expressions {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	values := make(map[string]Value, len(expressions))
+	for _, k := range keys {
+		v, err := expressions[k].Resolve(variables)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve attribute %s: %w", k, err)
+		}
+		values[k] = *v
+	}
+	return values, nil
+}
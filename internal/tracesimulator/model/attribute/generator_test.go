@@ -0,0 +1,131 @@
+package attribute
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net"
+	"regexp"
+	"testing"
+)
+
+func fixedRandomness(values ...float64) func() float64 {
+	i := 0
+	return func() float64 {
+		v := values[i%len(values)]
+		i++
+		return v
+	}
+}
+
+func TestRandomInt_Resolve(t *testing.T) {
+	testCases := []struct {
+		name       string
+		randomness float64
+		expected   int64
+	}{
+		{name: "lowest", randomness: 0, expected: 10},
+		{name: "middle", randomness: 0.5, expected: 15},
+		{name: "highest", randomness: 0.999999, expected: 20},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v, err := NewRandomInt(10, 20, fixedRandomness(tc.randomness)).Resolve(nil)
+			assert.NoError(t, err)
+			assert.Equal(t, Int(tc.expected), *v)
+		})
+	}
+}
+
+func TestWeightedEnum_Resolve(t *testing.T) {
+	choices := []WeightedChoice{
+		NewWeightedChoice(String("GET"), 3),
+		NewWeightedChoice(String("POST"), 1),
+	}
+	testCases := []struct {
+		name       string
+		randomness float64
+		expected   Value
+	}{
+		{name: "first choice", randomness: 0.5, expected: String("GET")},
+		{name: "second choice", randomness: 0.8, expected: String("POST")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v, err := NewWeightedEnum(choices, fixedRandomness(tc.randomness)).Resolve(nil)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, *v)
+		})
+	}
+}
+
+func TestUUID_Resolve(t *testing.T) {
+	v, err := NewUUID(fixedRandomness(0.1, 0.7, 0.3)).Resolve(nil)
+	assert.NoError(t, err)
+	assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`), v.AsString())
+}
+
+func TestSequence_Resolve(t *testing.T) {
+	g := NewSequence(100, 5)
+	copied := g
+	for _, expected := range []int64{100, 105, 110} {
+		v, err := g.Resolve(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, Int(expected), *v)
+	}
+	v, err := copied.Resolve(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, Int(115), *v)
+}
+
+func TestRandomIP_Resolve(t *testing.T) {
+	_, network, _ := net.ParseCIDR("192.168.1.0/24")
+	testCases := []struct {
+		name       string
+		randomness float64
+		expected   string
+	}{
+		{name: "lowest", randomness: 0, expected: "192.168.1.0"},
+		{name: "highest", randomness: 0.999999, expected: "192.168.1.255"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v, err := NewRandomIP(network, fixedRandomness(tc.randomness)).Resolve(nil)
+			assert.NoError(t, err)
+			assert.Equal(t, String(tc.expected), *v)
+		})
+	}
+}
+
+func TestUserID_Resolve(t *testing.T) {
+	testCases := []struct {
+		name       string
+		randomness float64
+		expected   string
+	}{
+		{name: "first user", randomness: 0, expected: "user-1"},
+		{name: "last user", randomness: 0.999999, expected: "user-50"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v, err := NewUserID("user-", 50, fixedRandomness(tc.randomness)).Resolve(nil)
+			assert.NoError(t, err)
+			assert.Equal(t, String(tc.expected), *v)
+		})
+	}
+}
+
+func TestResolveAll(t *testing.T) {
+	values, err := ResolveAll(map[string]Expression{
+		"static":   String("value"),
+		"sequence": NewSequence(1, 1),
+	}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]Value{"static": String("value"), "sequence": Int(1)}, values)
+
+	values, err = ResolveAll(nil, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, values)
+}
@@ -0,0 +1,27 @@
+package attribute
+
+import "math"
+
+var _ Expression = (*RandomInt)(nil)
+
+// RandomInt generates an integer uniformly distributed between minimum and maximum (inclusive)
+type RandomInt struct {
+	minimum    int64
+	maximum    int64
+	randomness func() float64
+}
+
+func NewRandomInt(minimum int64, maximum int64, randomness func() float64) RandomInt {
+	return RandomInt{
+		minimum:    minimum,
+		maximum:    maximum,
+		randomness: randomness,
+	}
+}
+
+func (g RandomInt) Resolve(_ map[string]Value) (*Value, error) {
+	span := float64(g.maximum-g.minimum) + 1
+	offset := int64(math.Min(math.Floor(g.randomness()*span), span-1))
+	v := Int(g.minimum + offset)
+	return &v, nil
+}
@@ -0,0 +1,33 @@
+package attribute
+
+import (
+	"encoding/binary"
+	"math"
+	"net"
+)
+
+var _ Expression = (*RandomIP)(nil)
+
+// RandomIP generates an IPv4 address uniformly distributed within a network
+type RandomIP struct {
+	network    *net.IPNet
+	randomness func() float64
+}
+
+func NewRandomIP(network *net.IPNet, randomness func() float64) RandomIP {
+	return RandomIP{
+		network:    network,
+		randomness: randomness,
+	}
+}
+
+func (g RandomIP) Resolve(_ map[string]Value) (*Value, error) {
+	ones, bits := g.network.Mask.Size()
+	size := math.Pow(2, float64(bits-ones))
+	offset := uint32(math.Min(math.Floor(g.randomness()*size), size-1))
+	base := binary.BigEndian.Uint32(g.network.IP.To4())
+	ip := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(ip, base+offset)
+	v := String(ip.String())
+	return &v, nil
+}
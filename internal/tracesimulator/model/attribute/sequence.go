@@ -0,0 +1,27 @@
+package attribute
+
+import "sync/atomic"
+
+var _ Expression = (*Sequence)(nil)
+
+// Sequence generates an incrementing integer, starting from start and increasing by step on each resolution.
+// The counter is shared by all the copies of the Sequence.
+type Sequence struct {
+	start   int64
+	step    int64
+	counter *atomic.Int64
+}
+
+func NewSequence(start int64, step int64) Sequence {
+	return Sequence{
+		start:   start,
+		step:    step,
+		counter: &atomic.Int64{},
+	}
+}
+
+func (g Sequence) Resolve(_ map[string]Value) (*Value, error) {
+	n := g.counter.Add(1) - 1
+	v := Int(g.start + n*g.step)
+	return &v, nil
+}
@@ -0,0 +1,155 @@
+package attribute
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// ScopeTrace is the scope of the variables that are resolved once per trace
+	ScopeTrace = "trace"
+	// ScopeRepeat is the scope of the index variables of repeated spans
+	ScopeRepeat = "repeat"
+
+	templateReferencePrefix = "${"
+	templateReferenceSuffix = "}"
+)
+
+// VariableNamePattern is the pattern that the names of variables must match
+var VariableNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// Qualify returns the name of the variable qualified by the scope, e.g. trace.user_id
+func Qualify(scope string, name string) string {
+	return scope + "." + name
+}
+
+// WithScope qualifies the names of the variables by the scope
+func WithScope(scope string, values map[string]Value) map[string]Value {
+	if values == nil {
+		return nil
+	}
+	scoped := make(map[string]Value, len(values))
+	for k, v := range values {
+		scoped[Qualify(scope, k)] = v
+	}
+	return scoped
+}
+
+// Merge returns the variables of base overridden by the variables of overrides
+func Merge(base map[string]Value, overrides map[string]Value) map[string]Value {
+	if len(overrides) == 0 {
+		return base
+	}
+	merged := make(map[string]Value, len(base)+len(overrides))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range overrides {
+		merged[k] = v
+	}
+	return merged
+}
+
+var _ Expression = (*Template)(nil)
+
+// Template is a string that references variables in the form of ${<scope>.<name>}, e.g. ${trace.user_id} or ${repeat.index}.
+// A template that consists of a single reference resolves to the value of the variable as is,
+// so the type of the value is kept. Otherwise, the references are replaced with the string representation of the values.
+type Template struct {
+	segments []templateSegment
+}
+
+// templateSegment is either a literal string or a reference to a variable by its qualified name
+type templateSegment struct {
+	literal  string
+	variable *string
+}
+
+// ParseTemplate parses the string as a template.
+// Placeholders of unknown scopes are kept as they are.
+// If the string does not reference any variables, it returns a static string value.
+func ParseTemplate(s string) (Expression, error) {
+	var segments []templateSegment
+	hasReference := false
+	literal := ""
+	rest := s
+	for {
+		i := strings.Index(rest, templateReferencePrefix)
+		if i < 0 {
+			break
+		}
+		scope, found := referencedScope(rest[i+len(templateReferencePrefix):])
+		if !found {
+			literal += rest[:i+len(templateReferencePrefix)]
+			rest = rest[i+len(templateReferencePrefix):]
+			continue
+		}
+		end := strings.Index(rest[i:], templateReferenceSuffix)
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated variable reference in %q", s)
+		}
+		name := rest[i+len(templateReferencePrefix)+len(scope)+1 : i+end]
+		if !VariableNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid variable name %q in %q", name, s)
+		}
+		literal += rest[:i]
+		if literal != "" {
+			segments = append(segments, templateSegment{literal: literal})
+			literal = ""
+		}
+		qualified := Qualify(scope, name)
+		segments = append(segments, templateSegment{variable: &qualified})
+		hasReference = true
+		rest = rest[i+end+len(templateReferenceSuffix):]
+	}
+	if !hasReference {
+		return String(s), nil
+	}
+	literal += rest
+	if literal != "" {
+		segments = append(segments, templateSegment{literal: literal})
+	}
+	return Template{segments: segments}, nil
+}
+
+// referencedScope returns the scope that the placeholder refers to, if it is a known one
+func referencedScope(placeholder string) (string, bool) {
+	for _, scope := range []string{ScopeTrace, ScopeRepeat} {
+		if strings.HasPrefix(placeholder, scope+".") {
+			return scope, true
+		}
+	}
+	return "", false
+}
+
+// Variables returns the qualified names of the variables referenced by the template
+func (t Template) Variables() []string {
+	var names []string
+	for _, s := range t.segments {
+		if s.variable != nil {
+			names = append(names, *s.variable)
+		}
+	}
+	return names
+}
+
+func (t Template) Resolve(variables map[string]Value) (*Value, error) {
+	var sb strings.Builder
+	for _, s := range t.segments {
+		if s.variable == nil {
+			sb.WriteString(s.literal)
+			continue
+		}
+		v, ok := variables[*s.variable]
+		if !ok {
+			return nil, fmt.Errorf("undefined variable: %s", *s.variable)
+		}
+		if len(t.segments) == 1 {
+			return &v, nil
+		}
+		sb.WriteString(v.String())
+	}
+	v := String(sb.String())
+	return &v, nil
+}
@@ -0,0 +1,56 @@
+package attribute
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseTemplate(t *testing.T) {
+	variables := map[string]Value{
+		"trace.user_id":  String("user-42"),
+		"trace.order_id": Int(1001),
+		"repeat.index":   Int(3),
+	}
+
+	testCases := []struct {
+		name     string
+		template string
+		expected Value
+	}{
+		{name: "no reference", template: "static", expected: String("static")},
+		{name: "other placeholder", template: "${env:HOME}", expected: String("${env:HOME}")},
+		{name: "single reference keeps the type", template: "${trace.order_id}", expected: Int(1001)},
+		{name: "embedded references", template: "/users/${trace.user_id}/orders/${trace.order_id}", expected: String("/users/user-42/orders/1001")},
+		{name: "references of different scopes", template: "${env:HOME}/${trace.user_id}/page-${repeat.index}", expected: String("${env:HOME}/user-42/page-3")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expr, err := ParseTemplate(tc.template)
+			assert.NoError(t, err)
+			v, err := expr.Resolve(variables)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, *v)
+		})
+	}
+
+	t.Run("undefined variable", func(t *testing.T) {
+		expr, err := ParseTemplate("${trace.tenant_id}")
+		assert.NoError(t, err)
+		_, err = expr.Resolve(variables)
+		assert.EqualError(t, err, "undefined variable: trace.tenant_id")
+	})
+
+	t.Run("invalid templates", func(t *testing.T) {
+		_, err := ParseTemplate("${trace.user_id")
+		assert.EqualError(t, err, `unterminated variable reference in "${trace.user_id"`)
+		_, err = ParseTemplate("${trace.user-id}")
+		assert.EqualError(t, err, `invalid variable name "user-id" in "${trace.user-id}"`)
+	})
+
+	t.Run("referenced variables", func(t *testing.T) {
+		expr, err := ParseTemplate("${trace.user_id}-${repeat.index}")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"trace.user_id", "repeat.index"}, expr.(Template).Variables())
+	})
+}
@@ -0,0 +1,29 @@
+package attribute
+
+import (
+	"math"
+	"strconv"
+)
+
+var _ Expression = (*UserID)(nil)
+
+// UserID picks a user id from a pool of poolSize users, formatted as the prefix followed by a number between 1 and poolSize
+type UserID struct {
+	prefix     string
+	poolSize   int
+	randomness func() float64
+}
+
+func NewUserID(prefix string, poolSize int, randomness func() float64) UserID {
+	return UserID{
+		prefix:     prefix,
+		poolSize:   poolSize,
+		randomness: randomness,
+	}
+}
+
+func (g UserID) Resolve(_ map[string]Value) (*Value, error) {
+	n := int(math.Min(math.Floor(g.randomness()*float64(g.poolSize)), float64(g.poolSize-1))) + 1
+	v := String(g.prefix + strconv.Itoa(n))
+	return &v, nil
+}
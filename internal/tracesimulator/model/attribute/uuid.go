@@ -0,0 +1,27 @@
+package attribute
+
+import "fmt"
+
+var _ Expression = (*UUID)(nil)
+
+// UUID generates a random (version 4) UUID string
+type UUID struct {
+	randomness func() float64
+}
+
+func NewUUID(randomness func() float64) UUID {
+	return UUID{
+		randomness: randomness,
+	}
+}
+
+func (g UUID) Resolve(_ map[string]Value) (*Value, error) {
+	var b [16]byte
+	for i := range b {
+		b[i] = byte(g.randomness() * 256)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	v := String(fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]))
+	return &v, nil
+}
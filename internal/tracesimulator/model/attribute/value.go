@@ -0,0 +1,164 @@
+package attribute
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Kind represents the type of attribute value
+type Kind int
+
+const (
+	// KindUnknown represents an unknown attribute value type
+	KindUnknown Kind = iota
+	// KindString represents a string attribute value
+	KindString
+	// KindInt represents an integer attribute value
+	KindInt
+	// KindDouble represents a floating point attribute value
+	KindDouble
+	// KindBool represents a boolean attribute value
+	KindBool
+	// KindStringSlice represents an array of strings attribute value
+	KindStringSlice
+	// KindIntSlice represents an array of integers attribute value
+	KindIntSlice
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindString:
+		return "string"
+	case KindInt:
+		return "int"
+	case KindDouble:
+		return "double"
+	case KindBool:
+		return "bool"
+	case KindStringSlice:
+		return "string_array"
+	case KindIntSlice:
+		return "int_array"
+	default:
+		return "unknown"
+	}
+}
+
+// Value represents a typed attribute value
+type Value struct {
+	kind        Kind
+	stringValue string
+	intValue    int64
+	doubleValue float64
+	boolValue   bool
+	stringSlice []string
+	intSlice    []int64
+}
+
+// String creates a new string Value
+func String(v string) Value {
+	return Value{kind: KindString, stringValue: v}
+}
+
+// Int creates a new integer Value
+func Int(v int64) Value {
+	return Value{kind: KindInt, intValue: v}
+}
+
+// Double creates a new floating point Value
+func Double(v float64) Value {
+	return Value{kind: KindDouble, doubleValue: v}
+}
+
+// Bool creates a new boolean Value
+func Bool(v bool) Value {
+	return Value{kind: KindBool, boolValue: v}
+}
+
+// StringSlice creates a new array of strings Value
+func StringSlice(v []string) Value {
+	return Value{kind: KindStringSlice, stringSlice: v}
+}
+
+// IntSlice creates a new array of integers Value
+func IntSlice(v []int64) Value {
+	return Value{kind: KindIntSlice, intSlice: v}
+}
+
+// Kind returns the type of the value
+func (v Value) Kind() Kind {
+	return v.kind
+}
+
+// AsString returns the value of a string Value
+func (v Value) AsString() string {
+	return v.stringValue
+}
+
+// AsInt returns the value of an integer Value
+func (v Value) AsInt() int64 {
+	return v.intValue
+}
+
+// AsDouble returns the value of a floating point Value
+func (v Value) AsDouble() float64 {
+	return v.doubleValue
+}
+
+// AsBool returns the value of a boolean Value
+func (v Value) AsBool() bool {
+	return v.boolValue
+}
+
+// AsStringSlice returns a copy of the value of an array of strings Value
+func (v Value) AsStringSlice() []string {
+	cp := make([]string, len(v.stringSlice))
+	copy(cp, v.stringSlice)
+	return cp
+}
+
+// AsIntSlice returns a copy of the value of an array of integers Value
+func (v Value) AsIntSlice() []int64 {
+	cp := make([]int64, len(v.intSlice))
+	copy(cp, v.intSlice)
+	return cp
+}
+
+// AsNumber returns the numeric representation of the value.
+// Strings are parsed as numbers, and false is returned if the value is not numeric.
+func (v Value) AsNumber() (float64, bool) {
+	switch v.kind {
+	case KindInt:
+		return float64(v.intValue), true
+	case KindDouble:
+		return v.doubleValue, true
+	case KindString:
+		f, err := strconv.ParseFloat(v.stringValue, 64)
+		if err != nil {
+			return 0, false
+		}
+		return f, true
+	default:
+		return 0, false
+	}
+}
+
+// String returns the string representation of the value
+func (v Value) String() string {
+	switch v.kind {
+	case KindString:
+		return v.stringValue
+	case KindInt:
+		return strconv.FormatInt(v.intValue, 10)
+	case KindDouble:
+		return strconv.FormatFloat(v.doubleValue, 'g', -1, 64)
+	case KindBool:
+		return strconv.FormatBool(v.boolValue)
+	case KindStringSlice:
+		return fmt.Sprint(v.stringSlice)
+	case KindIntSlice:
+		return fmt.Sprint(v.intSlice)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,50 @@
+package attribute
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestValue_String(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    Value
+		expected string
+	}{
+		{name: "string", value: String("POST"), expected: "POST"},
+		{name: "int", value: Int(200), expected: "200"},
+		{name: "double", value: Double(0.25), expected: "0.25"},
+		{name: "bool", value: Bool(true), expected: "true"},
+		{name: "string slice", value: StringSlice([]string{"a", "b"}), expected: "[a b]"},
+		{name: "int slice", value: IntSlice([]int64{1, 2}), expected: "[1 2]"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.value.String())
+		})
+	}
+}
+
+func TestValue_AsNumber(t *testing.T) {
+	testCases := []struct {
+		name      string
+		value     Value
+		expected  float64
+		isNumeric bool
+	}{
+		{name: "int", value: Int(200), expected: 200, isNumeric: true},
+		{name: "double", value: Double(0.25), expected: 0.25, isNumeric: true},
+		{name: "numeric string", value: String("503"), expected: 503, isNumeric: true},
+		{name: "non-numeric string", value: String("free"), isNumeric: false},
+		{name: "bool", value: Bool(true), isNumeric: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f, ok := tc.value.AsNumber()
+			assert.Equal(t, tc.isNumeric, ok)
+			assert.Equal(t, tc.expected, f)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package attribute
+
+import "fmt"
+
+var _ Expression = (*WeightedEnum)(nil)
+
+// WeightedChoice represents a value that is picked with the probability proportional to its weight
+type WeightedChoice struct {
+	value  Value
+	weight float64
+}
+
+func NewWeightedChoice(value Value, weight float64) WeightedChoice {
+	return WeightedChoice{
+		value:  value,
+		weight: weight,
+	}
+}
+
+// Value returns the value of the choice
+func (c WeightedChoice) Value() Value {
+	return c.value
+}
+
+// Weight returns the weight of the choice
+func (c WeightedChoice) Weight() float64 {
+	return c.weight
+}
+
+// WeightedEnum picks one of the choices according to their weights
+type WeightedEnum struct {
+	choices    []WeightedChoice
+	randomness func() float64
+}
+
+func NewWeightedEnum(choices []WeightedChoice, randomness func() float64) WeightedEnum {
+	return WeightedEnum{
+		choices:    choices,
+		randomness: randomness,
+	}
+}
+
+func (g WeightedEnum) Resolve(_ map[string]Value) (*Value, error) {
+	var total float64
+	for _, c := range g.choices {
+		total += c.weight
+	}
+	if total <= 0 {
+		return nil, fmt.Errorf("weighted enum must have at least one choice with a positive weight")
+	}
+	target := g.randomness() * total
+	for _, c := range g.choices {
+		if target < c.weight {
+			v := c.value
+			return &v, nil
+		}
+		target -= c.weight
+	}
+	// fall back to the last choice with a positive weight to tolerate rounding errors
+	for i := len(g.choices) - 1; i >= 0; i-- {
+		if g.choices[i].weight > 0 {
+			v := g.choices[i].value
+			return &v, nil
+		}
+	}
+	return nil, fmt.Errorf("weighted enum must have at least one choice with a positive weight")
+}
@@ -0,0 +1,25 @@
+package span
+
+var _ Condition = (*AllOfCondition)(nil)
+
+// AllOfCondition represents a condition that requires all inner conditions to be met.
+// Results requiring aggregation are combined element-wise, and the combined result also requires aggregation.
+type AllOfCondition struct {
+	conditions []Condition
+}
+
+func NewAllOf(conditions []Condition) Condition {
+	return AllOfCondition{conditions: conditions}
+}
+
+func (c AllOfCondition) Evaluate(target *TreeNode) (*ConditionEvaluationResult, error) {
+	results := make([]*ConditionEvaluationResult, 0, len(c.conditions))
+	for _, condition := range c.conditions {
+		cr, err := condition.Evaluate(target)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, cr)
+	}
+	return combineConditionEvaluationResults(results, true, func(a, b bool) bool { return a && b })
+}
@@ -0,0 +1,81 @@
+package span
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// ConditionMock is a mock implementation of the Condition interface, which returns the given result.
+type ConditionMock struct {
+	evaluations   []bool
+	mustAggregate bool
+}
+
+func (m *ConditionMock) Evaluate(_ *TreeNode) (*ConditionEvaluationResult, error) {
+	return NewConditionEvaluationResult(m.evaluations, m.mustAggregate), nil
+}
+
+func TestAllOfCondition_Evaluate(t *testing.T) {
+	t.Run("combine single-node results", func(t *testing.T) {
+		condition := NewAllOf([]Condition{
+			&ConditionMock{evaluations: []bool{true}},
+			&ConditionMock{evaluations: []bool{true}},
+		})
+		result, err := condition.Evaluate(&TreeNode{})
+		assert.NoError(t, err)
+		satisfied, err := result.IsSatisfied()
+		assert.NoError(t, err)
+		assert.True(t, satisfied)
+
+		condition = NewAllOf([]Condition{
+			&ConditionMock{evaluations: []bool{true}},
+			&ConditionMock{evaluations: []bool{false}},
+		})
+		result, err = condition.Evaluate(&TreeNode{})
+		assert.NoError(t, err)
+		satisfied, err = result.IsSatisfied()
+		assert.NoError(t, err)
+		assert.False(t, satisfied)
+	})
+
+	t.Run("combine multi-node results element-wise", func(t *testing.T) {
+		condition := NewAllOf([]Condition{
+			&ConditionMock{evaluations: []bool{true, true, false}, mustAggregate: true},
+			&ConditionMock{evaluations: []bool{false, true, true}, mustAggregate: true},
+		})
+		result, err := condition.Evaluate(&TreeNode{})
+		assert.NoError(t, err)
+		assert.Equal(t, []bool{false, true, false}, result.evaluations)
+		assert.Equal(t, true, result.mustAggregate)
+		_, err = result.IsSatisfied()
+		assert.Error(t, err)
+	})
+
+	t.Run("apply single-node results to every element of multi-node results", func(t *testing.T) {
+		condition := NewAllOf([]Condition{
+			&ConditionMock{evaluations: []bool{true}},
+			&ConditionMock{evaluations: []bool{false, true}, mustAggregate: true},
+		})
+		result, err := condition.Evaluate(&TreeNode{})
+		assert.NoError(t, err)
+		assert.Equal(t, []bool{false, true}, result.evaluations)
+		assert.Equal(t, true, result.mustAggregate)
+
+		condition = NewAllOf([]Condition{
+			&ConditionMock{evaluations: []bool{false}},
+			&ConditionMock{evaluations: []bool{false, true}, mustAggregate: true},
+		})
+		result, err = condition.Evaluate(&TreeNode{})
+		assert.NoError(t, err)
+		assert.Equal(t, []bool{false, false}, result.evaluations)
+	})
+
+	t.Run("fail to combine multi-node results of different lengths", func(t *testing.T) {
+		condition := NewAllOf([]Condition{
+			&ConditionMock{evaluations: []bool{true}, mustAggregate: true},
+			&ConditionMock{evaluations: []bool{true, true}, mustAggregate: true},
+		})
+		_, err := condition.Evaluate(&TreeNode{})
+		assert.Error(t, err)
+	})
+}
@@ -1,17 +1,25 @@
 package span
 
-import "github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
+import (
+	"fmt"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/attribute"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
+)
 
 type AnnotateEffect struct {
 	// attributes is a map of attributes to be added to the span.
-	attributes map[string]string
+	attributes map[string]attribute.Expression
 }
 
 func (a AnnotateEffect) Apply(node *TreeNode) error {
 	if node.attributes == nil {
-		node.attributes = make(map[string]string)
+		node.attributes = make(map[string]attribute.Value)
 	}
-	for k, v := range a.attributes {
+	attributes, err := attribute.ResolveAll(a.attributes, node.variables)
+	if err != nil {
+		return fmt.Errorf("failed to resolve attributes: %w", err)
+	}
+	for k, v := range attributes {
 		node.attributes[k] = v
 	}
 	return nil
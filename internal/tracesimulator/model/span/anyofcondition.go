@@ -0,0 +1,25 @@
+package span
+
+var _ Condition = (*AnyOfCondition)(nil)
+
+// AnyOfCondition represents a condition that requires at least one of the inner conditions to be met.
+// Results requiring aggregation are combined element-wise, and the combined result also requires aggregation.
+type AnyOfCondition struct {
+	conditions []Condition
+}
+
+func NewAnyOf(conditions []Condition) Condition {
+	return AnyOfCondition{conditions: conditions}
+}
+
+func (c AnyOfCondition) Evaluate(target *TreeNode) (*ConditionEvaluationResult, error) {
+	results := make([]*ConditionEvaluationResult, 0, len(c.conditions))
+	for _, condition := range c.conditions {
+		cr, err := condition.Evaluate(target)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, cr)
+	}
+	return combineConditionEvaluationResults(results, false, func(a, b bool) bool { return a || b })
+}
@@ -0,0 +1,32 @@
+package span
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAnyOfCondition_Evaluate(t *testing.T) {
+	t.Run("combine single-node results", func(t *testing.T) {
+		condition := NewAnyOf([]Condition{
+			&ConditionMock{evaluations: []bool{false}},
+			&ConditionMock{evaluations: []bool{true}},
+		})
+		result, err := condition.Evaluate(&TreeNode{})
+		assert.NoError(t, err)
+		satisfied, err := result.IsSatisfied()
+		assert.NoError(t, err)
+		assert.True(t, satisfied)
+	})
+
+	t.Run("combine multi-node results element-wise", func(t *testing.T) {
+		condition := NewAnyOf([]Condition{
+			&ConditionMock{evaluations: []bool{false}},
+			&ConditionMock{evaluations: []bool{true, false}, mustAggregate: true},
+			&ConditionMock{evaluations: []bool{false, false}, mustAggregate: true},
+		})
+		result, err := condition.Evaluate(&TreeNode{})
+		assert.NoError(t, err)
+		assert.Equal(t, []bool{true, false}, result.evaluations)
+		assert.Equal(t, true, result.mustAggregate)
+	})
+}
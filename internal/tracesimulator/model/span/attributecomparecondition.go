@@ -0,0 +1,49 @@
+package span
+
+import (
+	"fmt"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
+)
+
+var _ Condition = (*AttributeCompare)(nil)
+
+// AttributeCompare is a condition that compares a numeric attribute of a node with a specific value.
+// Attributes that are missing or not numeric never meet the condition.
+type AttributeCompare struct {
+	key      string
+	operator task.ComparisonOperator
+	value    float64
+}
+
+func NewAttributeCompare(key string, operator task.ComparisonOperator, value float64) AttributeCompare {
+	return AttributeCompare{
+		key:      key,
+		operator: operator,
+		value:    value,
+	}
+}
+
+func (c AttributeCompare) Evaluate(target *TreeNode) (*ConditionEvaluationResult, error) {
+	v, ok := target.Attributes()[c.key]
+	if !ok {
+		return NewConditionEvaluationResult([]bool{false}, false), nil
+	}
+	f, isNumeric := v.AsNumber()
+	if !isNumeric {
+		return NewConditionEvaluationResult([]bool{false}, false), nil
+	}
+	var result bool
+	switch c.operator {
+	case task.ComparisonOperatorGreaterThan:
+		result = f > c.value
+	case task.ComparisonOperatorGreaterThanOrEqual:
+		result = f >= c.value
+	case task.ComparisonOperatorLessThan:
+		result = f < c.value
+	case task.ComparisonOperatorLessThanOrEqual:
+		result = f <= c.value
+	default:
+		return nil, fmt.Errorf("unsupported comparison operator: %s", c.operator)
+	}
+	return NewConditionEvaluationResult([]bool{result}, false), nil
+}
@@ -0,0 +1,52 @@
+package span
+
+import (
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/attribute"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAttributeCompare_Evaluate(t *testing.T) {
+	node := &TreeNode{
+		attributes: map[string]attribute.Value{
+			"http.response.status_code": attribute.String("503"),
+			"tenant.tier":               attribute.String("free"),
+			"db.rows_affected":          attribute.Int(12),
+			"feature.enabled":           attribute.Bool(true),
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		key      string
+		operator task.ComparisonOperator
+		value    float64
+		expected bool
+	}{
+		{name: "greater than", key: "http.response.status_code", operator: task.ComparisonOperatorGreaterThan, value: 499, expected: true},
+		{name: "not greater than", key: "http.response.status_code", operator: task.ComparisonOperatorGreaterThan, value: 503, expected: false},
+		{name: "greater than or equal", key: "http.response.status_code", operator: task.ComparisonOperatorGreaterThanOrEqual, value: 503, expected: true},
+		{name: "less than", key: "http.response.status_code", operator: task.ComparisonOperatorLessThan, value: 600, expected: true},
+		{name: "less than or equal", key: "http.response.status_code", operator: task.ComparisonOperatorLessThanOrEqual, value: 502, expected: false},
+		{name: "missing attribute", key: "missing", operator: task.ComparisonOperatorLessThan, value: 600, expected: false},
+		{name: "typed numeric attribute", key: "db.rows_affected", operator: task.ComparisonOperatorGreaterThanOrEqual, value: 10, expected: true},
+		{name: "boolean attribute", key: "feature.enabled", operator: task.ComparisonOperatorGreaterThan, value: 0, expected: false},
+		{name: "non-numeric attribute", key: "tenant.tier", operator: task.ComparisonOperatorLessThan, value: 600, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := NewAttributeCompare(tc.key, tc.operator, tc.value).Evaluate(node)
+			assert.NoError(t, err)
+			satisfied, err := result.IsSatisfied()
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, satisfied)
+		})
+	}
+
+	t.Run("unsupported operator", func(t *testing.T) {
+		_, err := NewAttributeCompare("http.response.status_code", "eq", 503).Evaluate(node)
+		assert.Error(t, err)
+	})
+}
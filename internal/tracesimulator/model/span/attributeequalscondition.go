@@ -0,0 +1,22 @@
+package span
+
+var _ Condition = (*AttributeEquals)(nil)
+
+// AttributeEquals is a condition that checks if an attribute of a node equals a specific value.
+// Attributes are compared by their string representation.
+type AttributeEquals struct {
+	key   string
+	value string
+}
+
+func NewAttributeEquals(key string, value string) AttributeEquals {
+	return AttributeEquals{
+		key:   key,
+		value: value,
+	}
+}
+
+func (c AttributeEquals) Evaluate(target *TreeNode) (*ConditionEvaluationResult, error) {
+	v, ok := target.Attributes()[c.key]
+	return NewConditionEvaluationResult([]bool{ok && v.String() == c.value}, false), nil
+}
@@ -0,0 +1,39 @@
+package span
+
+import (
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/attribute"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAttributeEquals_Evaluate(t *testing.T) {
+	node := &TreeNode{
+		attributes: map[string]attribute.Value{
+			"http.request.method":       attribute.String("POST"),
+			"http.response.status_code": attribute.Int(201),
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		key      string
+		value    string
+		expected bool
+	}{
+		{name: "matching value", key: "http.request.method", value: "POST", expected: true},
+		{name: "non-matching value", key: "http.request.method", value: "GET", expected: false},
+		{name: "missing attribute", key: "missing", value: "POST", expected: false},
+		{name: "int attribute compared by its string representation", key: "http.response.status_code", value: "201", expected: true},
+		{name: "int attribute with a different string representation", key: "http.response.status_code", value: "201.0", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := NewAttributeEquals(tc.key, tc.value).Evaluate(node)
+			assert.NoError(t, err)
+			satisfied, err := result.IsSatisfied()
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, satisfied)
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package span
+
+import "regexp"
+
+var _ Condition = (*AttributeMatches)(nil)
+
+// AttributeMatches is a condition that checks if the string representation of an attribute of a node matches a regular expression.
+type AttributeMatches struct {
+	key     string
+	pattern *regexp.Regexp
+}
+
+func NewAttributeMatches(key string, pattern *regexp.Regexp) AttributeMatches {
+	return AttributeMatches{
+		key:     key,
+		pattern: pattern,
+	}
+}
+
+func (c AttributeMatches) Evaluate(target *TreeNode) (*ConditionEvaluationResult, error) {
+	v, ok := target.Attributes()[c.key]
+	return NewConditionEvaluationResult([]bool{ok && c.pattern.MatchString(v.String())}, false), nil
+}
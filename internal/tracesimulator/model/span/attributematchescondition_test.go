@@ -0,0 +1,37 @@
+package span
+
+import (
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/attribute"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"testing"
+)
+
+func TestAttributeMatches_Evaluate(t *testing.T) {
+	node := &TreeNode{
+		attributes: map[string]attribute.Value{
+			"url.path": attribute.String("/api/v1/messages"),
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		key      string
+		pattern  string
+		expected bool
+	}{
+		{name: "matching pattern", key: "url.path", pattern: "^/api/v1/", expected: true},
+		{name: "non-matching pattern", key: "url.path", pattern: "^/api/v2/", expected: false},
+		{name: "missing attribute", key: "missing", pattern: ".*", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := NewAttributeMatches(tc.key, regexp.MustCompile(tc.pattern)).Evaluate(node)
+			assert.NoError(t, err)
+			satisfied, err := result.IsSatisfied()
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, satisfied)
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package span
+
+import "time"
+
+// SkewClocks skews the timestamps of the spans of the tree by the clock skews of their resources.
+// It is meant to run once the timing of the trace is final, since the skew only affects how the timestamps are recorded.
+func (n *TreeNode) SkewClocks() {
+	n.skewClocks(make(map[string]time.Duration))
+}
+
+// skews the timestamps of the span and its events by the clock skew of its resource, and does the same for its descendants.
+// The skew of a resource is resolved once for the tree, so that all the spans of the resource in the trace share the same clock.
+func (n *TreeNode) skewClocks(skews map[string]time.Duration) {
+	resource := n.resource.Name()
+	skew, resolved := skews[resource]
+	if !resolved {
+		clockSkew := n.resource.ClockSkew()
+		skew = clockSkew.Resolve()
+		skews[resource] = skew
+	}
+	if skew != 0 {
+		n.startTime = n.startTime.Add(skew)
+		n.endTime = n.endTime.Add(skew)
+		for i := range n.events {
+			n.events[i].ShiftOccurredAt(skew)
+		}
+	}
+	for _, child := range n.children {
+		child.skewClocks(skews)
+	}
+}
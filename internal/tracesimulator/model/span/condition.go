@@ -27,6 +27,25 @@ func FromConditionSpec(spec task.Condition) (Condition, error) {
 			return nil, fmt.Errorf("hasAttribute condition requires a key")
 		}
 		return NewHasAttribute(spec.HasAttribute().Key()), nil
+	case task.ConditionKindAttributeEquals:
+		if spec.AttributeEquals() == nil {
+			return nil, fmt.Errorf("attributeEquals condition requires a key and a value")
+		}
+		return NewAttributeEquals(spec.AttributeEquals().Key(), spec.AttributeEquals().Value()), nil
+	case task.ConditionKindAttributeMatches:
+		if spec.AttributeMatches() == nil || spec.AttributeMatches().Pattern() == nil {
+			return nil, fmt.Errorf("attributeMatches condition requires a key and a pattern")
+		}
+		return NewAttributeMatches(spec.AttributeMatches().Key(), spec.AttributeMatches().Pattern()), nil
+	case task.ConditionKindAttributeCompare:
+		if spec.AttributeCompare() == nil {
+			return nil, fmt.Errorf("attributeCompare condition requires a key, an operator and a value")
+		}
+		return NewAttributeCompare(
+			spec.AttributeCompare().Key(),
+			spec.AttributeCompare().Operator(),
+			spec.AttributeCompare().Value(),
+		), nil
 	case task.ConditionKindChild:
 		if spec.Child() == nil {
 			return nil, fmt.Errorf("child condition requires a child condition")
@@ -53,7 +72,46 @@ func FromConditionSpec(spec task.Condition) (Condition, error) {
 			return nil, fmt.Errorf("markedAsFailed condition requires a message")
 		}
 		return NewMarkedAsFailedCondition(), nil
+	case task.ConditionKindAllOf:
+		if spec.AllOf() == nil {
+			return nil, fmt.Errorf("allOf condition requires conditions")
+		}
+		conditions, err := fromConditionSpecs(spec.AllOf().Conditions())
+		if err != nil {
+			return nil, err
+		}
+		return NewAllOf(conditions), nil
+	case task.ConditionKindAnyOf:
+		if spec.AnyOf() == nil {
+			return nil, fmt.Errorf("anyOf condition requires conditions")
+		}
+		conditions, err := fromConditionSpecs(spec.AnyOf().Conditions())
+		if err != nil {
+			return nil, err
+		}
+		return NewAnyOf(conditions), nil
+	case task.ConditionKindNot:
+		if spec.Not() == nil {
+			return nil, fmt.Errorf("not condition requires an inner condition")
+		}
+		innerCondition, err := FromConditionSpec(spec.Not().Inner())
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert inner condition: %w", err)
+		}
+		return NewNot(innerCondition), nil
 	default:
 		return nil, fmt.Errorf("unsupported condition type: %s", spec.Kind())
 	}
 }
+
+func fromConditionSpecs(specs []task.Condition) ([]Condition, error) {
+	conditions := make([]Condition, 0, len(specs))
+	for _, spec := range specs {
+		condition, err := FromConditionSpec(spec)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert inner condition: %w", err)
+		}
+		conditions = append(conditions, condition)
+	}
+	return conditions, nil
+}
@@ -29,3 +29,39 @@ func (r *ConditionEvaluationResult) IsSatisfied() (bool, error) {
 	}
 	return r.evaluations[0], nil
 }
+
+// MustAggregate returns true if the result holds one evaluation per node and must be aggregated before use.
+func (r *ConditionEvaluationResult) MustAggregate() bool {
+	return r.mustAggregate
+}
+
+// combineConditionEvaluationResults combines the results element-wise with the given operator.
+// If any of the results must be aggregated, so does the combined result, and single-node results are applied to every element.
+func combineConditionEvaluationResults(results []*ConditionEvaluationResult, initial bool, operator func(a, b bool) bool) (*ConditionEvaluationResult, error) {
+	length := 1
+	mustAggregate := false
+	for _, r := range results {
+		if !r.mustAggregate {
+			continue
+		}
+		if mustAggregate && len(r.evaluations) != length {
+			return nil, fmt.Errorf("cannot combine multi-node results of different lengths: %d and %d", length, len(r.evaluations))
+		}
+		length = len(r.evaluations)
+		mustAggregate = true
+	}
+	combined := make([]bool, length)
+	for i := range combined {
+		combined[i] = initial
+	}
+	for _, r := range results {
+		for i := range combined {
+			if r.mustAggregate {
+				combined[i] = operator(combined[i], r.evaluations[i])
+			} else {
+				combined[i] = operator(combined[i], r.evaluations[0])
+			}
+		}
+	}
+	return NewConditionEvaluationResult(combined, mustAggregate), nil
+}
@@ -0,0 +1,35 @@
+package span
+
+// DegradeDataQuality flaws the spans of the tree according to their data quality, deciding each flaw randomly.
+// Whether a resource loses its spans is decided once for the tree, so that none of its spans in the trace is delivered.
+// This is meant to be the last step before delivery, since dropped spans still hold their children.
+func (n *TreeNode) DegradeDataQuality(randomness func() float64, idGen func() ID) {
+	n.degradeDataQuality(randomness, idGen, make(map[string]bool))
+}
+
+func (n *TreeNode) degradeDataQuality(randomness func() float64, idGen func() ID, lostResources map[string]bool) {
+	resource := n.resource.Name()
+	lost, decided := lostResources[resource]
+	if !decided {
+		lost = happens(randomness, n.resource.LossProbability())
+		lostResources[resource] = lost
+	}
+	n.dropped = lost || happens(randomness, n.dataQuality.Drop())
+	if !n.dropped {
+		n.duplicated = happens(randomness, n.dataQuality.Duplicate())
+		if happens(randomness, n.dataQuality.BogusParent()) {
+			// the children keep pointing at the actual ID of the span
+			bogusParentID := idGen()
+			n.parentID = &bogusParentID
+		}
+	}
+	for _, child := range n.children {
+		child.degradeDataQuality(randomness, idGen, lostResources)
+	}
+}
+
+// happens decides whether a flaw with the probability happens.
+// No random number is drawn for flaws that never happen, so that they do not change the sequence of the random numbers.
+func happens(randomness func() float64, probability float64) bool {
+	return probability > 0 && randomness() < probability
+}
@@ -30,6 +30,11 @@ func FromEffectSpec(spec task.Effect) (Effect, error) {
 			return nil, fmt.Errorf("annotate effect is nil")
 		}
 		return FromTaskAnnotateEffect(*spec.AnnotateEffect()), nil
+	case task.EffectKindSlowDown:
+		if spec.SlowDownEffect() == nil {
+			return nil, fmt.Errorf("slow down effect is nil")
+		}
+		return FromTaskSlowDownEffect(*spec.SlowDownEffect()), nil
 	default:
 		return nil, fmt.Errorf("unknown effect type: %s", spec.Kind())
 	}
@@ -0,0 +1,81 @@
+package span
+
+import (
+	"fmt"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/attribute"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
+	"time"
+)
+
+// ExceptionEventName is the name of the event recorded in the spans marked as failed by error propagation
+const ExceptionEventName = "exception"
+
+// propagatedError is a failure propagating from a span to its parent
+type propagatedError struct {
+	message         string
+	toServer        bool // whether the failure goes on up to the nearest server span
+	recordException bool
+	occurredAt      time.Time
+}
+
+// propagateErrors marks the span and its ancestors as failed according to the error propagation policies of the failed spans.
+// A span marked as failed by propagation propagates the failure further according to its own policy.
+// It returns the failure propagating to the parent, if any.
+func (n *TreeNode) propagateErrors() *propagatedError {
+	var incoming *propagatedError
+	for _, child := range n.children {
+		// all the children are visited, but the first failure propagating to the span wins.
+		// Failed attempts followed by another attempt do not propagate their failures.
+		if p := child.propagateErrors(); p != nil && !child.retried && incoming == nil {
+			incoming = p
+			incoming.occurredAt = child.endTime
+		}
+	}
+
+	var message string
+	if n.status.code == StatusCodeError {
+		message = fmt.Sprintf("span %s failed", n.name)
+		if n.status.message != nil {
+			message = fmt.Sprintf("%s: %s", message, *n.status.message)
+		}
+	} else if incoming != nil {
+		message = incoming.message
+		n.status = StatusError(message)
+		if incoming.recordException {
+			n.events = append(n.events, NewEvent(
+				ExceptionEventName,
+				clamp(incoming.occurredAt, n.startTime, n.endTime),
+				map[string]attribute.Value{"exception.message": attribute.String(message)},
+			))
+		}
+	} else {
+		return nil
+	}
+
+	// a failure on its way to the nearest server span goes on regardless of the policy of the span
+	if incoming != nil && incoming.toServer && n.kind != KindServer {
+		return &propagatedError{
+			message:         message,
+			toServer:        true,
+			recordException: incoming.recordException || n.errorPropagation.RecordException(),
+		}
+	}
+	switch n.errorPropagation.Mode() {
+	case task.ErrorPropagationParent:
+		return &propagatedError{message: message, recordException: n.errorPropagation.RecordException()}
+	case task.ErrorPropagationServer:
+		return &propagatedError{message: message, toServer: true, recordException: n.errorPropagation.RecordException()}
+	default:
+		return nil
+	}
+}
+
+func clamp(t, earliest, latest time.Time) time.Time {
+	if t.Before(earliest) {
+		return earliest
+	}
+	if t.After(latest) {
+		return latest
+	}
+	return t
+}
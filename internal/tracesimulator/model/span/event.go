@@ -1,15 +1,18 @@
 package span
 
-import "time"
+import (
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/attribute"
+	"time"
+)
 
 // Event represents an event in a span
 type Event struct {
 	name       string
 	occurredAt time.Time
-	attributes map[string]string
+	attributes map[string]attribute.Value
 }
 
-func NewEvent(name string, occurredAt time.Time, attributes map[string]string) Event {
+func NewEvent(name string, occurredAt time.Time, attributes map[string]attribute.Value) Event {
 	return Event{
 		name:       name,
 		occurredAt: occurredAt,
@@ -33,6 +36,6 @@ func (e *Event) OccurredAt() time.Time {
 }
 
 // Attributes returns the attributes of the event
-func (e *Event) Attributes() map[string]string {
+func (e *Event) Attributes() map[string]attribute.Value {
 	return e.attributes
 }
@@ -0,0 +1,25 @@
+package span
+
+var _ Condition = (*NotCondition)(nil)
+
+// NotCondition represents a condition that negates the inner condition.
+// Each evaluation is negated, so a result requiring aggregation still requires aggregation.
+type NotCondition struct {
+	inner Condition
+}
+
+func NewNot(inner Condition) Condition {
+	return NotCondition{inner: inner}
+}
+
+func (c NotCondition) Evaluate(target *TreeNode) (*ConditionEvaluationResult, error) {
+	cr, err := c.inner.Evaluate(target)
+	if err != nil {
+		return nil, err
+	}
+	rs := cr.Results()
+	for i := range rs {
+		rs[i] = !rs[i]
+	}
+	return NewConditionEvaluationResult(rs, cr.MustAggregate()), nil
+}
@@ -0,0 +1,21 @@
+package span
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNotCondition_Evaluate(t *testing.T) {
+	condition := NewNot(&ConditionMock{evaluations: []bool{true}})
+	result, err := condition.Evaluate(&TreeNode{})
+	assert.NoError(t, err)
+	satisfied, err := result.IsSatisfied()
+	assert.NoError(t, err)
+	assert.False(t, satisfied)
+
+	condition = NewNot(&ConditionMock{evaluations: []bool{true, false}, mustAggregate: true})
+	result, err = condition.Evaluate(&TreeNode{})
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{false, true}, result.evaluations)
+	assert.Equal(t, true, result.mustAggregate)
+}
@@ -2,6 +2,7 @@ package span
 
 import (
 	"fmt"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/attribute"
 	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
 )
 
@@ -21,7 +22,11 @@ func (r *RecordEventEffect) Apply(node *TreeNode) error {
 	if err != nil {
 		return fmt.Errorf("failed to resolve delay: %w", err)
 	}
-	e := NewEvent(r.event.Name(), node.startTime.Add(*delay), r.event.Attributes())
+	attributes, err := attribute.ResolveAll(r.event.Attributes(), node.variables)
+	if err != nil {
+		return fmt.Errorf("failed to resolve event attributes: %w", err)
+	}
+	e := NewEvent(r.event.Name(), node.startTime.Add(*delay), attributes)
 	node.events = append(node.events, e)
 	return nil
 }
@@ -0,0 +1,80 @@
+package span
+
+import (
+	"fmt"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/attribute"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
+	"time"
+)
+
+// RetryAttemptAttributeKey is the key of the attribute holding the number of the attempt, starting from 1.
+// Only the spans of the tasks with retry have it, so that the spans of the other tasks are left as they are.
+const RetryAttemptAttributeKey = "retry.attempt"
+
+// attemptTaskNode converts the task to spans, one for each attempt.
+// Without retry, the task is attempted once. With retry, the task is attempted again after the backoff
+// as long as the attempt fails and the attempts do not run out. The end time of the last attempt is regarded
+// as delayed by the retries, so that the parent and the later siblings follow the change.
+func attemptTaskNode(
+	taskNode *task.TreeNode,
+	traceID TraceID,
+	parentID *ID,
+	parentDuration *time.Duration,
+	baseStartTime time.Time,
+	idGen func() ID,
+	variables map[string]attribute.Value,
+) ([]*TreeNode, error) {
+	first, err := fromTaskNode(taskNode, traceID, parentID, parentDuration, baseStartTime, idGen, variables)
+	if err != nil {
+		return nil, err
+	}
+	retry := taskNode.Definition().Retry()
+	if retry == nil {
+		return []*TreeNode{first}, nil
+	}
+	var failure Condition = NewMarkedAsFailedCondition()
+	if retry.Failure() != nil {
+		failure, err = FromConditionSpec(*retry.Failure())
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert retry failure condition: %w", err)
+		}
+	}
+
+	attempts := []*TreeNode{first}
+	for {
+		last := attempts[len(attempts)-1]
+		if last.attributes == nil {
+			last.attributes = make(map[string]attribute.Value)
+		}
+		last.attributes[RetryAttemptAttributeKey] = attribute.Int(int64(len(attempts)))
+		if len(attempts) >= retry.MaxAttempts() {
+			break
+		}
+		cr, err := failure.Evaluate(last)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate retry failure condition: %w", err)
+		}
+		failed, err := cr.IsSatisfied()
+		if err != nil {
+			return nil, fmt.Errorf("failed to check retry failure condition satisfaction: %w", err)
+		}
+		if !failed {
+			break
+		}
+		next, err := fromTaskNode(taskNode, traceID, parentID, parentDuration, baseStartTime, idGen, variables)
+		if err != nil {
+			return nil, err
+		}
+		// the next attempt starts after the backoff from the end of the failed attempt
+		nextStartTime := last.endTime.Add(retry.Backoff().WaitBefore(len(attempts)))
+		next.ShiftTimestamps(nextStartTime.Sub(next.startTime))
+		last.retried = true
+		attempts = append(attempts, next)
+	}
+
+	if len(attempts) > 1 {
+		last := attempts[len(attempts)-1]
+		last.delayedBy = last.endTime.Sub(first.endTime) + first.delayedBy
+	}
+	return attempts, nil
+}
@@ -0,0 +1,40 @@
+package span
+
+import (
+	"fmt"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
+	"time"
+)
+
+var _ Effect = (*SlowDownEffect)(nil)
+
+// SlowDownEffect is an effect that stretches the end time of the span.
+// The parent and the later siblings in sequential mode follow the change when the parent is constructed.
+type SlowDownEffect struct {
+	by     time.Duration
+	factor float64
+}
+
+func (s SlowDownEffect) Apply(node *TreeNode) error {
+	duration := node.endTime.Sub(node.startTime)
+	extra := time.Duration(float64(duration)*(s.factor-1)) + s.by
+	if extra < 0 {
+		return fmt.Errorf("slow down cannot shorten the span, got %s", extra)
+	}
+	node.endTime = node.endTime.Add(extra)
+	node.delayedBy += extra
+	return nil
+}
+
+// NewSlowDownEffect creates a new SlowDownEffect that multiplies the duration by the factor and then adds the fixed time.
+func NewSlowDownEffect(by time.Duration, factor float64) SlowDownEffect {
+	return SlowDownEffect{
+		by:     by,
+		factor: factor,
+	}
+}
+
+// FromTaskSlowDownEffect converts a task SlowDownEffect to a span SlowDownEffect.
+func FromTaskSlowDownEffect(spec task.SlowDownEffect) SlowDownEffect {
+	return NewSlowDownEffect(spec.By(), spec.Factor())
+}
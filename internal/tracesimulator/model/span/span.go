@@ -2,6 +2,7 @@ package span
 
 import (
 	"fmt"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/attribute"
 	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
 	"time"
 )
@@ -13,7 +14,8 @@ type TreeNode struct {
 	name                 string
 	isResourceEntryPoint bool
 	resource             task.Resource
-	attributes           map[string]string
+	resourceAttributes   map[string]attribute.Value // resolved attributes of the resource, only at resource entry points
+	attributes           map[string]attribute.Value
 	kind                 Kind
 	startTime            time.Time
 	endTime              time.Time
@@ -24,6 +26,14 @@ type TreeNode struct {
 	events               []Event
 	linkedToExternalID   []*task.ExternalID
 	status               Status
+	variables            map[string]attribute.Value // trace-scoped variables used to resolve attribute templates
+	errorPropagation     task.ErrorPropagation      // how the failure of the span propagates to its ancestors
+	delayedBy            time.Duration              // how much the end time is delayed by the slow-downs and retries of the span, its descendants and its earlier siblings
+	retried              bool                       // whether the span is a failed attempt followed by another attempt
+	truncatedExternalIDs []task.ExternalID          // external IDs of the descendants dropped by the timeout of the span
+	dataQuality          task.DataQuality           // flaws of the data the span is delivered with
+	dropped              bool                       // whether the span is not delivered, leaving its children as orphans
+	duplicated           bool                       // whether the span is delivered twice
 }
 
 // FromTaskTree converts a task tree to a span tree
@@ -32,14 +42,17 @@ func FromTaskTree(
 	traceID TraceID,
 	baseStartTime time.Time,
 	idGen func() ID,
+	variables map[string]attribute.Value,
 ) (*TreeNode, error) {
-	rootSpan, err := fromTaskNode(taskTree, traceID, nil, nil, baseStartTime, idGen)
+	rootSpan, err := fromTaskNode(taskTree, traceID, nil, nil, baseStartTime, idGen, variables)
 	if err != nil {
 		return nil, fmt.Errorf("failed to convert task tree to span tree: %w", err)
 	}
 	if err := rootSpan.validate(); err != nil {
 		return nil, err
 	}
+	// failures propagate after the conditional effects of all the spans have been evaluated
+	rootSpan.propagateErrors()
 	return rootSpan, nil
 }
 
@@ -50,8 +63,10 @@ func fromTaskNode(
 	parentDuration *time.Duration,
 	baseStartTime time.Time,
 	idGen func() ID,
+	variables map[string]attribute.Value,
 ) (*TreeNode, error) {
 	spanID := idGen()
+	variables = attribute.Merge(variables, taskNode.Definition().Variables())
 	delay, err := taskNode.Definition().Delay().Resolve(parentDuration)
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve delay: %w", err)
@@ -73,20 +88,39 @@ func fromTaskNode(
 		if *d > *duration {
 			return nil, fmt.Errorf("event delay cannot be greater than task duration")
 		}
+		eventAttributes, err := attribute.ResolveAll(event.Attributes(), variables)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve event attributes: %w", err)
+		}
 		events[i] = NewEvent(
 			event.Name(),
 			startTime.Add(*d),
-			event.Attributes(),
+			eventAttributes,
 		)
 	}
 
+	attributes, err := attribute.ResolveAll(taskNode.Definition().Attributes(), variables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve attributes: %w", err)
+	}
+	// resource attributes are emitted only at resource entry points, so they are resolved only there
+	var resourceAttributes map[string]attribute.Value
+	if taskNode.Definition().IsResourceEntryPoint() {
+		resource := taskNode.Definition().Resource()
+		resourceAttributes, err = attribute.ResolveAll(resource.Attributes(), variables)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve resource: %w", err)
+		}
+	}
+
 	node := TreeNode{
 		id:                   spanID,
 		traceID:              traceID,
 		name:                 taskNode.Definition().Name(),
 		isResourceEntryPoint: taskNode.Definition().IsResourceEntryPoint(),
 		resource:             taskNode.Definition().Resource(),
-		attributes:           taskNode.Definition().Attributes(),
+		resourceAttributes:   resourceAttributes,
+		attributes:           attributes,
 		kind:                 FromTaskKind(taskNode.Definition().Kind()),
 		startTime:            startTime,
 		endTime:              endTime,
@@ -97,15 +131,30 @@ func fromTaskNode(
 		events:               events,
 		linkedToExternalID:   taskNode.Definition().LinkedTo(),
 		status:               StatusOK,
+		variables:            variables,
+		errorPropagation:     taskNode.Definition().ErrorPropagation(),
+		dataQuality:          taskNode.Definition().DataQuality(),
 	}
 
+	schedule := taskNode.Definition().Schedule()
+	childBaseStartTime := startTime
+	var shift time.Duration
 	for _, childTask := range taskNode.Children() {
-		childSpan, err := fromTaskNode(childTask, traceID, &spanID, duration, startTime, idGen)
+		attempts, err := attemptTaskNode(childTask, traceID, &spanID, duration, childBaseStartTime, idGen, variables)
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert child task to span: %w", err)
 		}
-		node.children = append(node.children, childSpan)
+		node.children = append(node.children, attempts...)
+		// in sequential mode, the next child starts after the last attempt of this child ends,
+		// so it is also shifted by the slow-downs and retries of this child
+		if schedule.Mode() == task.ScheduleSequential {
+			childSpan := attempts[len(attempts)-1]
+			childSpan.delayedBy += shift
+			shift = childSpan.delayedBy
+			childBaseStartTime = childSpan.endTime.Add(schedule.Gap())
+		}
 	}
+	node.stretchToDelayedChildren()
 
 	for _, spec := range taskNode.Definition().ConditionalDefinitions() {
 		condition, err := FromConditionSpec(spec.Condition())
@@ -133,9 +182,26 @@ func fromTaskNode(
 		}
 	}
 
+	if timeout := taskNode.Definition().Timeout(); timeout != nil {
+		node.applyTimeout(*timeout)
+	}
+
 	return &node, nil
 }
 
+// stretches the end time of the span so that it still contains the children that have been pushed past its end by slow-downs and retries.
+// Children that end after the span regardless of them only stretch it by the part caused by them.
+func (n *TreeNode) stretchToDelayedChildren() {
+	var stretch time.Duration
+	for _, child := range n.children {
+		overrun := child.endTime.Sub(n.endTime)
+		originalOverrun := max(overrun-child.delayedBy, 0)
+		stretch = max(stretch, overrun-originalOverrun)
+	}
+	n.endTime = n.endTime.Add(stretch)
+	n.delayedBy = stretch
+}
+
 func (n *TreeNode) validate() error {
 	// it returns an error if the externalID is not unique
 	externalIDToSpan := make(map[task.ExternalID]*TreeNode)
@@ -225,7 +291,12 @@ func (n *TreeNode) Resource() task.Resource {
 	return n.resource
 }
 
-func (n *TreeNode) Attributes() map[string]string {
+// ResourceAttributes returns the resolved attributes of the resource, which are only set at resource entry points
+func (n *TreeNode) ResourceAttributes() map[string]attribute.Value {
+	return n.resourceAttributes
+}
+
+func (n *TreeNode) Attributes() map[string]attribute.Value {
 	return n.attributes
 }
 
@@ -273,6 +344,16 @@ func (n *TreeNode) LinkedToExternalID() []*task.ExternalID {
 	return cp
 }
 
+// Dropped returns whether the span is not delivered. Its children are still delivered as orphans.
+func (n *TreeNode) Dropped() bool {
+	return n.dropped
+}
+
+// Duplicated returns whether the span is delivered twice.
+func (n *TreeNode) Duplicated() bool {
+	return n.duplicated
+}
+
 func (n *TreeNode) Status() Status {
 	return n.status
 }
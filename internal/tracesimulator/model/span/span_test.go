@@ -1,6 +1,7 @@
 package span
 
 import (
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/attribute"
 	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task/taskduration"
 	"testing"
 	"time"
@@ -31,8 +32,8 @@ func TestFromTaskTree(t *testing.T) {
 					def := task.NewDefinition(
 						"root-task",
 						true,
-						task.NewResource("service-a", map[string]string{"service.version": "1.0.0"}),
-						map[string]string{"team": "team-a"},
+						task.NewResource("service-a", map[string]attribute.Expression{"service.version": attribute.String("1.0.0")}),
+						map[string]attribute.Expression{"team": attribute.String("team-a")},
 						task.KindServer,
 						func() *task.ExternalID { id, _ := task.NewExternalID("root-task"); return id }(),
 						NewAbsoluteDurationDelay(1*time.Second),
@@ -43,7 +44,7 @@ func TestFromTaskTree(t *testing.T) {
 							task.NewEvent(
 								"root-task-event",
 								NewAbsoluteDurationDelay(1*time.Second),
-								make(map[string]string),
+								make(map[string]attribute.Expression),
 							),
 						},
 						[]task.ConditionalDefinition{
@@ -53,7 +54,7 @@ func TestFromTaskTree(t *testing.T) {
 									task.FromMarkAsFailedEffect(task.NewMarkAsFailedEffect("error")),
 								},
 							),
-						})
+						}, task.DefinitionOptions{})
 					return def
 				}(),
 			),
@@ -65,8 +66,9 @@ func TestFromTaskTree(t *testing.T) {
 				traceID:              traceID,
 				name:                 "root-task",
 				isResourceEntryPoint: true,
-				resource:             task.NewResource("service-a", map[string]string{"service.version": "1.0.0"}),
-				attributes:           map[string]string{"team": "team-a"},
+				resource:             task.NewResource("service-a", map[string]attribute.Expression{"service.version": attribute.String("1.0.0")}),
+				resourceAttributes:   map[string]attribute.Value{"service.version": attribute.String("1.0.0")},
+				attributes:           map[string]attribute.Value{"team": attribute.String("team-a")},
 				kind:                 KindServer,
 				startTime:            baseTime.Add(1 * time.Second),
 				endTime:              baseTime.Add(3 * time.Second),
@@ -76,7 +78,7 @@ func TestFromTaskTree(t *testing.T) {
 				children:             []*TreeNode{},
 				linkedTo:             []*TreeNode{},
 				events: []Event{
-					NewEvent("root-task-event", baseTime.Add(2*time.Second), make(map[string]string)),
+					NewEvent("root-task-event", baseTime.Add(2*time.Second), make(map[string]attribute.Value)),
 				},
 				linkedToExternalID: []*task.ExternalID{},
 			},
@@ -89,8 +91,8 @@ func TestFromTaskTree(t *testing.T) {
 						def := task.NewDefinition(
 							"root-task",
 							true,
-							task.NewResource("service-a", map[string]string{"service.version": "1.0.0"}),
-							map[string]string{"key1": "val1"},
+							task.NewResource("service-a", map[string]attribute.Expression{"service.version": attribute.String("1.0.0")}),
+							map[string]attribute.Expression{"key1": attribute.String("val1")},
 							task.KindInternal,
 							nil,
 							NewAbsoluteDurationDelay(1*time.Second),
@@ -99,6 +101,7 @@ func TestFromTaskTree(t *testing.T) {
 							[]*task.ExternalID{},
 							[]task.Event{},
 							[]task.ConditionalDefinition{},
+							task.DefinitionOptions{},
 						)
 						return def
 					}(),
@@ -110,8 +113,8 @@ func TestFromTaskTree(t *testing.T) {
 							def := task.NewDefinition(
 								"child-task",
 								false,
-								task.NewResource("service-a", map[string]string{"service.version": "1.0.0"}),
-								map[string]string{"key2": "val2"},
+								task.NewResource("service-a", map[string]attribute.Expression{"service.version": attribute.String("1.0.0")}),
+								map[string]attribute.Expression{"key2": attribute.String("val2")},
 								task.KindClient,
 								nil,
 								NewAbsoluteDurationDelay(3*time.Second),
@@ -120,6 +123,7 @@ func TestFromTaskTree(t *testing.T) {
 								[]*task.ExternalID{},
 								[]task.Event{},
 								[]task.ConditionalDefinition{},
+								task.DefinitionOptions{},
 							)
 							return def
 						}(),
@@ -147,8 +151,9 @@ func TestFromTaskTree(t *testing.T) {
 				name:                 "root-task",
 				isResourceEntryPoint: true,
 				kind:                 KindInternal,
-				resource:             task.NewResource("service-a", map[string]string{"service.version": "1.0.0"}),
-				attributes:           map[string]string{"key1": "val1"},
+				resource:             task.NewResource("service-a", map[string]attribute.Expression{"service.version": attribute.String("1.0.0")}),
+				resourceAttributes:   map[string]attribute.Value{"service.version": attribute.String("1.0.0")},
+				attributes:           map[string]attribute.Value{"key1": attribute.String("val1")},
 				startTime:            baseTime.Add(1 * time.Second),
 				endTime:              baseTime.Add(3 * time.Second),
 				events:               []Event{},
@@ -160,8 +165,8 @@ func TestFromTaskTree(t *testing.T) {
 						name:                 "child-task",
 						isResourceEntryPoint: false,
 						kind:                 KindClient,
-						resource:             task.NewResource("service-a", map[string]string{"service.version": "1.0.0"}),
-						attributes:           map[string]string{"key2": "val2"},
+						resource:             task.NewResource("service-a", map[string]attribute.Expression{"service.version": attribute.String("1.0.0")}),
+						attributes:           map[string]attribute.Value{"key2": attribute.String("val2")},
 						startTime:            baseTime.Add(4 * time.Second),
 						endTime:              baseTime.Add(8 * time.Second),
 						status:               StatusOK,
@@ -185,8 +190,8 @@ func TestFromTaskTree(t *testing.T) {
 						def := task.NewDefinition(
 							"root-task",
 							true,
-							task.NewResource("service-a", make(map[string]string)),
-							make(map[string]string),
+							task.NewResource("service-a", make(map[string]attribute.Expression)),
+							make(map[string]attribute.Expression),
 							task.KindInternal,
 							nil,
 							NewAbsoluteDurationDelay(1*time.Second),
@@ -195,6 +200,7 @@ func TestFromTaskTree(t *testing.T) {
 							[]*task.ExternalID{},
 							[]task.Event{},
 							[]task.ConditionalDefinition{},
+							task.DefinitionOptions{},
 						)
 						return def
 					}(),
@@ -206,8 +212,8 @@ func TestFromTaskTree(t *testing.T) {
 							def := task.NewDefinition(
 								"child-task",
 								false,
-								task.NewResource("service-a", make(map[string]string)),
-								make(map[string]string),
+								task.NewResource("service-a", make(map[string]attribute.Expression)),
+								make(map[string]attribute.Expression),
 								task.KindClient,
 								nil,
 								NewAbsoluteDurationDelay(3*time.Second),
@@ -216,6 +222,7 @@ func TestFromTaskTree(t *testing.T) {
 								[]*task.ExternalID{},
 								[]task.Event{},
 								[]task.ConditionalDefinition{},
+								task.DefinitionOptions{},
 							)
 							return def
 						}(),
@@ -244,8 +251,9 @@ func TestFromTaskTree(t *testing.T) {
 				name:                 "root-task",
 				isResourceEntryPoint: true,
 				kind:                 KindInternal,
-				resource:             task.NewResource("service-a", make(map[string]string)),
-				attributes:           make(map[string]string),
+				resource:             task.NewResource("service-a", make(map[string]attribute.Expression)),
+				resourceAttributes:   make(map[string]attribute.Value),
+				attributes:           make(map[string]attribute.Value),
 				startTime:            baseTime.Add(1 * time.Second),
 				endTime:              baseTime.Add(3 * time.Second),
 				events:               []Event{},
@@ -257,8 +265,8 @@ func TestFromTaskTree(t *testing.T) {
 						name:                 "child-task",
 						isResourceEntryPoint: false,
 						kind:                 KindClient,
-						resource:             task.NewResource("service-a", make(map[string]string)),
-						attributes:           make(map[string]string),
+						resource:             task.NewResource("service-a", make(map[string]attribute.Expression)),
+						attributes:           make(map[string]attribute.Value),
 						startTime:            baseTime.Add(4 * time.Second),
 						endTime:              baseTime.Add(8 * time.Second),
 						status:               StatusOK,
@@ -282,8 +290,8 @@ func TestFromTaskTree(t *testing.T) {
 						def := task.NewDefinition(
 							"root-task",
 							true,
-							task.NewResource("service-a", make(map[string]string)),
-							make(map[string]string),
+							task.NewResource("service-a", make(map[string]attribute.Expression)),
+							make(map[string]attribute.Expression),
 							task.KindInternal,
 							nil,
 							NewAbsoluteDurationDelay(0),
@@ -292,6 +300,7 @@ func TestFromTaskTree(t *testing.T) {
 							[]*task.ExternalID{},
 							[]task.Event{},
 							[]task.ConditionalDefinition{},
+							task.DefinitionOptions{},
 						)
 						return def
 					}(),
@@ -303,8 +312,8 @@ func TestFromTaskTree(t *testing.T) {
 							def := task.NewDefinition(
 								"child-task",
 								false,
-								task.NewResource("service-a", make(map[string]string)),
-								make(map[string]string),
+								task.NewResource("service-a", make(map[string]attribute.Expression)),
+								make(map[string]attribute.Expression),
 								task.KindClient,
 								nil,
 								NewRelativeDurationDelay(0.5),
@@ -313,6 +322,7 @@ func TestFromTaskTree(t *testing.T) {
 								[]*task.ExternalID{},
 								[]task.Event{},
 								[]task.ConditionalDefinition{},
+								task.DefinitionOptions{},
 							)
 							return def
 						}(),
@@ -341,8 +351,9 @@ func TestFromTaskTree(t *testing.T) {
 				name:                 "root-task",
 				isResourceEntryPoint: true,
 				kind:                 KindInternal,
-				resource:             task.NewResource("service-a", make(map[string]string)),
-				attributes:           make(map[string]string),
+				resource:             task.NewResource("service-a", make(map[string]attribute.Expression)),
+				resourceAttributes:   make(map[string]attribute.Value),
+				attributes:           make(map[string]attribute.Value),
 				startTime:            baseTime,
 				endTime:              baseTime.Add(10 * time.Second),
 				events:               []Event{},
@@ -354,8 +365,8 @@ func TestFromTaskTree(t *testing.T) {
 						name:                 "child-task",
 						isResourceEntryPoint: false,
 						kind:                 KindClient,
-						resource:             task.NewResource("service-a", make(map[string]string)),
-						attributes:           make(map[string]string),
+						resource:             task.NewResource("service-a", make(map[string]attribute.Expression)),
+						attributes:           make(map[string]attribute.Value),
 						startTime:            baseTime.Add(5 * time.Second),
 						endTime:              baseTime.Add(25 * time.Second),
 						status:               StatusOK,
@@ -379,8 +390,8 @@ func TestFromTaskTree(t *testing.T) {
 						def := task.NewDefinition(
 							"root-task",
 							true,
-							task.NewResource("service-a", make(map[string]string)),
-							make(map[string]string),
+							task.NewResource("service-a", make(map[string]attribute.Expression)),
+							make(map[string]attribute.Expression),
 							task.KindInternal,
 							nil,
 							NewAbsoluteDurationDelay(0),
@@ -391,10 +402,11 @@ func TestFromTaskTree(t *testing.T) {
 								task.NewEvent(
 									"relative-delay-event",
 									NewRelativeDurationDelay(0.5),
-									make(map[string]string),
+									make(map[string]attribute.Expression),
 								),
 							},
 							[]task.ConditionalDefinition{},
+							task.DefinitionOptions{},
 						)
 						return def
 					}(),
@@ -406,8 +418,8 @@ func TestFromTaskTree(t *testing.T) {
 							def := task.NewDefinition(
 								"child-task",
 								false,
-								task.NewResource("service-a", make(map[string]string)),
-								make(map[string]string),
+								task.NewResource("service-a", make(map[string]attribute.Expression)),
+								make(map[string]attribute.Expression),
 								task.KindClient,
 								nil,
 								NewAbsoluteDurationDelay(20*time.Second),
@@ -418,10 +430,11 @@ func TestFromTaskTree(t *testing.T) {
 									task.NewEvent(
 										"absolute-delay-event",
 										NewAbsoluteDurationDelay(5*time.Second),
-										make(map[string]string),
+										make(map[string]attribute.Expression),
 									),
 								},
 								[]task.ConditionalDefinition{},
+								task.DefinitionOptions{},
 							)
 							return def
 						}(),
@@ -450,12 +463,13 @@ func TestFromTaskTree(t *testing.T) {
 				name:                 "root-task",
 				isResourceEntryPoint: true,
 				kind:                 KindInternal,
-				resource:             task.NewResource("service-a", make(map[string]string)),
-				attributes:           make(map[string]string),
+				resource:             task.NewResource("service-a", make(map[string]attribute.Expression)),
+				resourceAttributes:   make(map[string]attribute.Value),
+				attributes:           make(map[string]attribute.Value),
 				startTime:            baseTime,
 				endTime:              baseTime.Add(30 * time.Second),
 				events: []Event{
-					NewEvent("relative-delay-event", baseTime.Add(15*time.Second), make(map[string]string)),
+					NewEvent("relative-delay-event", baseTime.Add(15*time.Second), make(map[string]attribute.Value)),
 				},
 				status: StatusOK,
 				children: []*TreeNode{
@@ -465,8 +479,8 @@ func TestFromTaskTree(t *testing.T) {
 						name:                 "child-task",
 						isResourceEntryPoint: false,
 						kind:                 KindClient,
-						resource:             task.NewResource("service-a", make(map[string]string)),
-						attributes:           make(map[string]string),
+						resource:             task.NewResource("service-a", make(map[string]attribute.Expression)),
+						attributes:           make(map[string]attribute.Value),
 						startTime:            baseTime.Add(20 * time.Second),
 						endTime:              baseTime.Add(30 * time.Second),
 						status:               StatusOK,
@@ -474,7 +488,7 @@ func TestFromTaskTree(t *testing.T) {
 						externalID:           nil,
 						linkedTo:             []*TreeNode{},
 						events: []Event{
-							NewEvent("absolute-delay-event", baseTime.Add(25*time.Second), make(map[string]string)),
+							NewEvent("absolute-delay-event", baseTime.Add(25*time.Second), make(map[string]attribute.Value)),
 						},
 						linkedToExternalID: []*task.ExternalID{},
 						children:           []*TreeNode{},
@@ -492,8 +506,8 @@ func TestFromTaskTree(t *testing.T) {
 						def := task.NewDefinition(
 							"root-task",
 							true,
-							task.NewResource("service-a", make(map[string]string)),
-							make(map[string]string),
+							task.NewResource("service-a", make(map[string]attribute.Expression)),
+							make(map[string]attribute.Expression),
 							task.KindInternal,
 							nil,
 							NewAbsoluteDurationDelay(0),
@@ -502,6 +516,7 @@ func TestFromTaskTree(t *testing.T) {
 							[]*task.ExternalID{},
 							[]task.Event{},
 							[]task.ConditionalDefinition{},
+							task.DefinitionOptions{},
 						)
 						return def
 					}(),
@@ -513,8 +528,8 @@ func TestFromTaskTree(t *testing.T) {
 							def := task.NewDefinition(
 								"child-task",
 								false,
-								task.NewResource("service-a", make(map[string]string)),
-								make(map[string]string),
+								task.NewResource("service-a", make(map[string]attribute.Expression)),
+								make(map[string]attribute.Expression),
 								task.KindClient,
 								nil,
 								NewAbsoluteDurationDelay(0),
@@ -523,6 +538,7 @@ func TestFromTaskTree(t *testing.T) {
 								[]*task.ExternalID{},
 								[]task.Event{},
 								[]task.ConditionalDefinition{},
+								task.DefinitionOptions{},
 							)
 							return def
 						}(),
@@ -550,8 +566,9 @@ func TestFromTaskTree(t *testing.T) {
 				name:                 "root-task",
 				isResourceEntryPoint: true,
 				kind:                 KindInternal,
-				resource:             task.NewResource("service-a", make(map[string]string)),
-				attributes:           make(map[string]string),
+				resource:             task.NewResource("service-a", make(map[string]attribute.Expression)),
+				resourceAttributes:   make(map[string]attribute.Value),
+				attributes:           make(map[string]attribute.Value),
 				startTime:            baseTime,
 				endTime:              baseTime.Add(10 * time.Second),
 				events:               []Event{},
@@ -563,8 +580,8 @@ func TestFromTaskTree(t *testing.T) {
 						name:                 "child-task",
 						isResourceEntryPoint: false,
 						kind:                 KindClient,
-						resource:             task.NewResource("service-a", make(map[string]string)),
-						attributes:           make(map[string]string),
+						resource:             task.NewResource("service-a", make(map[string]attribute.Expression)),
+						attributes:           make(map[string]attribute.Value),
 						startTime:            baseTime.Add(0 * time.Second),
 						endTime:              baseTime.Add(5 * time.Second),
 						status:               StatusOK,
@@ -587,8 +604,8 @@ func TestFromTaskTree(t *testing.T) {
 					def := task.NewDefinition(
 						"root-task",
 						true,
-						task.NewResource("service-a", make(map[string]string)),
-						make(map[string]string),
+						task.NewResource("service-a", make(map[string]attribute.Expression)),
+						make(map[string]attribute.Expression),
 						task.KindInternal,
 						nil,
 						NewAbsoluteDurationDelay(1*time.Second),
@@ -604,12 +621,13 @@ func TestFromTaskTree(t *testing.T) {
 										task.NewEvent(
 											"event-name",
 											NewAbsoluteDurationDelay(1*time.Second),
-											map[string]string{"key": "value"},
+											map[string]attribute.Expression{"key": attribute.String("value")},
 										),
 									)),
 								},
 							),
 						},
+						task.DefinitionOptions{},
 					)
 					return def
 				}(),
@@ -623,14 +641,15 @@ func TestFromTaskTree(t *testing.T) {
 				name:                 "root-task",
 				isResourceEntryPoint: true,
 				kind:                 KindInternal,
-				resource:             task.NewResource("service-a", make(map[string]string)),
-				attributes:           make(map[string]string),
+				resource:             task.NewResource("service-a", make(map[string]attribute.Expression)),
+				resourceAttributes:   make(map[string]attribute.Value),
+				attributes:           make(map[string]attribute.Value),
 				startTime:            baseTime.Add(1 * time.Second),
 				endTime:              baseTime.Add(3 * time.Second),
 				status:               StatusOK,
 				linkedTo:             []*TreeNode{},
 				events: []Event{
-					NewEvent("event-name", baseTime.Add(2*time.Second), map[string]string{"key": "value"}),
+					NewEvent("event-name", baseTime.Add(2*time.Second), map[string]attribute.Value{"key": attribute.String("value")}),
 				},
 				linkedToExternalID: []*task.ExternalID{},
 				children:           []*TreeNode{},
@@ -643,8 +662,8 @@ func TestFromTaskTree(t *testing.T) {
 					def := task.NewDefinition(
 						"root-task",
 						true,
-						task.NewResource("service-a", make(map[string]string)),
-						make(map[string]string),
+						task.NewResource("service-a", make(map[string]attribute.Expression)),
+						make(map[string]attribute.Expression),
 						task.KindInternal,
 						nil,
 						NewAbsoluteDurationDelay(1*time.Second),
@@ -660,12 +679,13 @@ func TestFromTaskTree(t *testing.T) {
 										task.NewEvent(
 											"event-name",
 											NewAbsoluteDurationDelay(1*time.Second),
-											map[string]string{"key": "value"},
+											map[string]attribute.Expression{"key": attribute.String("value")},
 										),
 									)),
 								},
 							),
 						},
+						task.DefinitionOptions{},
 					)
 					return def
 				}(),
@@ -679,8 +699,9 @@ func TestFromTaskTree(t *testing.T) {
 				name:                 "root-task",
 				isResourceEntryPoint: true,
 				kind:                 KindInternal,
-				resource:             task.NewResource("service-a", make(map[string]string)),
-				attributes:           make(map[string]string),
+				resource:             task.NewResource("service-a", make(map[string]attribute.Expression)),
+				resourceAttributes:   make(map[string]attribute.Value),
+				attributes:           make(map[string]attribute.Value),
 				startTime:            baseTime.Add(1 * time.Second),
 				endTime:              baseTime.Add(3 * time.Second),
 				status:               StatusOK,
@@ -697,8 +718,8 @@ func TestFromTaskTree(t *testing.T) {
 					def := task.NewDefinition(
 						"root-task",
 						true,
-						task.NewResource("service-a", make(map[string]string)),
-						make(map[string]string),
+						task.NewResource("service-a", make(map[string]attribute.Expression)),
+						make(map[string]attribute.Expression),
 						task.KindInternal,
 						nil,
 						NewAbsoluteDurationDelay(1*time.Second),
@@ -714,6 +735,7 @@ func TestFromTaskTree(t *testing.T) {
 								},
 							),
 						},
+						task.DefinitionOptions{},
 					)
 					return def
 				}(),
@@ -727,8 +749,9 @@ func TestFromTaskTree(t *testing.T) {
 				name:                 "root-task",
 				isResourceEntryPoint: true,
 				kind:                 KindInternal,
-				resource:             task.NewResource("service-a", make(map[string]string)),
-				attributes:           make(map[string]string),
+				resource:             task.NewResource("service-a", make(map[string]attribute.Expression)),
+				resourceAttributes:   make(map[string]attribute.Value),
+				attributes:           make(map[string]attribute.Value),
 				startTime:            baseTime.Add(1 * time.Second),
 				endTime:              baseTime.Add(3 * time.Second),
 				status:               StatusError("error"),
@@ -745,8 +768,8 @@ func TestFromTaskTree(t *testing.T) {
 					def := task.NewDefinition(
 						"root-task",
 						true,
-						task.NewResource("service-a", make(map[string]string)),
-						map[string]string{"key1": "val1"},
+						task.NewResource("service-a", make(map[string]attribute.Expression)),
+						map[string]attribute.Expression{"key1": attribute.String("val1")},
 						task.KindInternal,
 						nil,
 						NewAbsoluteDurationDelay(1*time.Second),
@@ -759,11 +782,12 @@ func TestFromTaskTree(t *testing.T) {
 								task.NewProbabilisticCondition(1.0, func() float64 { return 0.0 }),
 								[]task.Effect{
 									task.FromAnnotateEffect(task.NewAnnotateEffect(
-										map[string]string{"key2": "val2"},
+										map[string]attribute.Expression{"key2": attribute.String("val2")},
 									)),
 								},
 							),
 						},
+						task.DefinitionOptions{},
 					)
 					return def
 				}(),
@@ -777,8 +801,9 @@ func TestFromTaskTree(t *testing.T) {
 				name:                 "root-task",
 				isResourceEntryPoint: true,
 				kind:                 KindInternal,
-				resource:             task.NewResource("service-a", make(map[string]string)),
-				attributes:           map[string]string{"key1": "val1", "key2": "val2"},
+				resource:             task.NewResource("service-a", make(map[string]attribute.Expression)),
+				resourceAttributes:   make(map[string]attribute.Value),
+				attributes:           map[string]attribute.Value{"key1": attribute.String("val1"), "key2": attribute.String("val2")},
 				startTime:            baseTime.Add(1 * time.Second),
 				endTime:              baseTime.Add(3 * time.Second),
 				status:               StatusOK,
@@ -795,7 +820,7 @@ func TestFromTaskTree(t *testing.T) {
 					def := task.NewDefinition(
 						"root-task",
 						true,
-						task.NewResource("service-a", make(map[string]string)),
+						task.NewResource("service-a", make(map[string]attribute.Expression)),
 						nil,
 						task.KindInternal,
 						nil,
@@ -809,11 +834,12 @@ func TestFromTaskTree(t *testing.T) {
 								task.NewProbabilisticCondition(1.0, func() float64 { return 0.0 }),
 								[]task.Effect{
 									task.FromAnnotateEffect(task.NewAnnotateEffect(
-										map[string]string{"key": "value"},
+										map[string]attribute.Expression{"key": attribute.String("value")},
 									)),
 								},
 							),
 						},
+						task.DefinitionOptions{},
 					)
 					return def
 				}(),
@@ -827,8 +853,9 @@ func TestFromTaskTree(t *testing.T) {
 				name:                 "root-task",
 				isResourceEntryPoint: true,
 				kind:                 KindInternal,
-				resource:             task.NewResource("service-a", make(map[string]string)),
-				attributes:           map[string]string{"key": "value"},
+				resource:             task.NewResource("service-a", make(map[string]attribute.Expression)),
+				resourceAttributes:   make(map[string]attribute.Value),
+				attributes:           map[string]attribute.Value{"key": attribute.String("value")},
 				startTime:            baseTime.Add(1 * time.Second),
 				endTime:              baseTime.Add(3 * time.Second),
 				status:               StatusOK,
@@ -846,8 +873,8 @@ func TestFromTaskTree(t *testing.T) {
 						def := task.NewDefinition(
 							"root-task",
 							true,
-							task.NewResource("service-a", make(map[string]string)),
-							make(map[string]string),
+							task.NewResource("service-a", make(map[string]attribute.Expression)),
+							make(map[string]attribute.Expression),
 							task.KindInternal,
 							nil,
 							NewAbsoluteDurationDelay(0),
@@ -866,6 +893,7 @@ func TestFromTaskTree(t *testing.T) {
 									},
 								),
 							},
+							task.DefinitionOptions{},
 						)
 						return def
 					}(),
@@ -877,8 +905,8 @@ func TestFromTaskTree(t *testing.T) {
 							def := task.NewDefinition(
 								"child-task-1",
 								false,
-								task.NewResource("service-a", make(map[string]string)),
-								map[string]string{"key1": "val1"},
+								task.NewResource("service-a", make(map[string]attribute.Expression)),
+								map[string]attribute.Expression{"key1": attribute.String("val1")},
 								task.KindClient,
 								nil,
 								NewAbsoluteDurationDelay(0),
@@ -887,6 +915,7 @@ func TestFromTaskTree(t *testing.T) {
 								[]*task.ExternalID{},
 								[]task.Event{},
 								[]task.ConditionalDefinition{},
+								task.DefinitionOptions{},
 							)
 							return def
 						}(),
@@ -899,8 +928,8 @@ func TestFromTaskTree(t *testing.T) {
 							def := task.NewDefinition(
 								"child-task-2",
 								false,
-								task.NewResource("service-a", make(map[string]string)),
-								map[string]string{"key2": "val2"},
+								task.NewResource("service-a", make(map[string]attribute.Expression)),
+								map[string]attribute.Expression{"key2": attribute.String("val2")},
 								task.KindClient,
 								nil,
 								NewAbsoluteDurationDelay(0),
@@ -909,6 +938,7 @@ func TestFromTaskTree(t *testing.T) {
 								[]*task.ExternalID{},
 								[]task.Event{},
 								[]task.ConditionalDefinition{},
+								task.DefinitionOptions{},
 							)
 							return def
 						}(),
@@ -937,8 +967,9 @@ func TestFromTaskTree(t *testing.T) {
 				name:                 "root-task",
 				isResourceEntryPoint: true,
 				kind:                 KindInternal,
-				resource:             task.NewResource("service-a", make(map[string]string)),
-				attributes:           make(map[string]string),
+				resource:             task.NewResource("service-a", make(map[string]attribute.Expression)),
+				resourceAttributes:   make(map[string]attribute.Value),
+				attributes:           make(map[string]attribute.Value),
 				startTime:            baseTime,
 				endTime:              baseTime.Add(10 * time.Second),
 				events:               []Event{},
@@ -950,8 +981,8 @@ func TestFromTaskTree(t *testing.T) {
 						name:                 "child-task-1",
 						isResourceEntryPoint: false,
 						kind:                 KindClient,
-						resource:             task.NewResource("service-a", make(map[string]string)),
-						attributes:           map[string]string{"key1": "val1"},
+						resource:             task.NewResource("service-a", make(map[string]attribute.Expression)),
+						attributes:           map[string]attribute.Value{"key1": attribute.String("val1")},
 						startTime:            baseTime.Add(0 * time.Second),
 						endTime:              baseTime.Add(5 * time.Second),
 						status:               StatusOK,
@@ -968,8 +999,8 @@ func TestFromTaskTree(t *testing.T) {
 						name:                 "child-task-2",
 						isResourceEntryPoint: false,
 						kind:                 KindClient,
-						resource:             task.NewResource("service-a", make(map[string]string)),
-						attributes:           map[string]string{"key2": "val2"},
+						resource:             task.NewResource("service-a", make(map[string]attribute.Expression)),
+						attributes:           map[string]attribute.Value{"key2": attribute.String("val2")},
 						startTime:            baseTime.Add(0 * time.Second),
 						endTime:              baseTime.Add(5 * time.Second),
 						status:               StatusOK,
@@ -993,8 +1024,8 @@ func TestFromTaskTree(t *testing.T) {
 						def := task.NewDefinition(
 							"root-task",
 							true,
-							task.NewResource("service-a", make(map[string]string)),
-							make(map[string]string),
+							task.NewResource("service-a", make(map[string]attribute.Expression)),
+							make(map[string]attribute.Expression),
 							task.KindInternal,
 							nil,
 							NewAbsoluteDurationDelay(0),
@@ -1013,6 +1044,7 @@ func TestFromTaskTree(t *testing.T) {
 									},
 								),
 							},
+							task.DefinitionOptions{},
 						)
 						return def
 					}(),
@@ -1024,8 +1056,8 @@ func TestFromTaskTree(t *testing.T) {
 							def := task.NewDefinition(
 								"child-task",
 								false,
-								task.NewResource("service-a", make(map[string]string)),
-								make(map[string]string),
+								task.NewResource("service-a", make(map[string]attribute.Expression)),
+								make(map[string]attribute.Expression),
 								task.KindClient,
 								nil,
 								NewAbsoluteDurationDelay(0),
@@ -1041,6 +1073,7 @@ func TestFromTaskTree(t *testing.T) {
 										},
 									),
 								},
+								task.DefinitionOptions{},
 							)
 							return def
 						}(),
@@ -1068,8 +1101,9 @@ func TestFromTaskTree(t *testing.T) {
 				name:                 "root-task",
 				isResourceEntryPoint: true,
 				kind:                 KindInternal,
-				resource:             task.NewResource("service-a", make(map[string]string)),
-				attributes:           make(map[string]string),
+				resource:             task.NewResource("service-a", make(map[string]attribute.Expression)),
+				resourceAttributes:   make(map[string]attribute.Value),
+				attributes:           make(map[string]attribute.Value),
 				startTime:            baseTime,
 				endTime:              baseTime.Add(10 * time.Second),
 				events:               []Event{},
@@ -1081,8 +1115,8 @@ func TestFromTaskTree(t *testing.T) {
 						name:                 "child-task",
 						isResourceEntryPoint: false,
 						kind:                 KindClient,
-						resource:             task.NewResource("service-a", make(map[string]string)),
-						attributes:           make(map[string]string),
+						resource:             task.NewResource("service-a", make(map[string]attribute.Expression)),
+						attributes:           make(map[string]attribute.Value),
 						startTime:            baseTime.Add(0 * time.Second),
 						endTime:              baseTime.Add(5 * time.Second),
 						status:               StatusError("error"),
@@ -1105,8 +1139,8 @@ func TestFromTaskTree(t *testing.T) {
 					def := task.NewDefinition(
 						"root-task",
 						true,
-						task.NewResource("service-a", make(map[string]string)),
-						make(map[string]string),
+						task.NewResource("service-a", make(map[string]attribute.Expression)),
+						make(map[string]attribute.Expression),
 						task.KindInternal,
 						nil,
 						NewAbsoluteDurationDelay(1*time.Second),
@@ -1123,12 +1157,13 @@ func TestFromTaskTree(t *testing.T) {
 										task.NewEvent(
 											"event-name",
 											NewAbsoluteDurationDelay(1*time.Second),
-											map[string]string{"key": "value"},
+											map[string]attribute.Expression{"key": attribute.String("value")},
 										),
 									)),
 								},
 							),
 						},
+						task.DefinitionOptions{},
 					)
 					return def
 				}(),
@@ -1142,14 +1177,15 @@ func TestFromTaskTree(t *testing.T) {
 				name:                 "root-task",
 				isResourceEntryPoint: true,
 				kind:                 KindInternal,
-				resource:             task.NewResource("service-a", make(map[string]string)),
-				attributes:           make(map[string]string),
+				resource:             task.NewResource("service-a", make(map[string]attribute.Expression)),
+				resourceAttributes:   make(map[string]attribute.Value),
+				attributes:           make(map[string]attribute.Value),
 				startTime:            baseTime.Add(1 * time.Second),
 				endTime:              baseTime.Add(3 * time.Second),
 				status:               StatusError("error"),
 				linkedTo:             []*TreeNode{},
 				events: []Event{
-					NewEvent("event-name", baseTime.Add(2*time.Second), map[string]string{"key": "value"}),
+					NewEvent("event-name", baseTime.Add(2*time.Second), map[string]attribute.Value{"key": attribute.String("value")}),
 				},
 				linkedToExternalID: []*task.ExternalID{},
 				children:           []*TreeNode{},
@@ -1159,7 +1195,7 @@ func TestFromTaskTree(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			span, err := FromTaskTree(tc.taskTree, tc.traceID, tc.baseEndTime, tc.idGen)
+			span, err := FromTaskTree(tc.taskTree, tc.traceID, tc.baseEndTime, tc.idGen, nil)
 			assert.NoError(t, err)
 			assert.Equal(t, tc.expected, span)
 		})
@@ -1181,8 +1217,8 @@ func TestFromTaskTreeError(t *testing.T) {
 					def := task.NewDefinition(
 						"root-task",
 						true,
-						task.NewResource("service-a", make(map[string]string)),
-						make(map[string]string),
+						task.NewResource("service-a", make(map[string]attribute.Expression)),
+						make(map[string]attribute.Expression),
 						task.KindInternal,
 						nil,
 						NewRelativeDurationDelay(0.5),
@@ -1191,6 +1227,7 @@ func TestFromTaskTreeError(t *testing.T) {
 						[]*task.ExternalID{},
 						[]task.Event{},
 						[]task.ConditionalDefinition{},
+						task.DefinitionOptions{},
 					)
 					return def
 				}(),
@@ -1204,8 +1241,8 @@ func TestFromTaskTreeError(t *testing.T) {
 					def := task.NewDefinition(
 						"root-task",
 						true,
-						task.NewResource("service-a", make(map[string]string)),
-						make(map[string]string),
+						task.NewResource("service-a", make(map[string]attribute.Expression)),
+						make(map[string]attribute.Expression),
 						task.KindInternal,
 						nil,
 						NewAbsoluteDurationDelay(0),
@@ -1216,10 +1253,11 @@ func TestFromTaskTreeError(t *testing.T) {
 							task.NewEvent(
 								"event-name",
 								NewAbsoluteDurationDelay(3*time.Second),
-								make(map[string]string),
+								make(map[string]attribute.Expression),
 							),
 						},
 						[]task.ConditionalDefinition{},
+						task.DefinitionOptions{},
 					)
 					return def
 				}(),
@@ -1230,12 +1268,628 @@ func TestFromTaskTreeError(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			_, err := FromTaskTree(tc.taskTree, tc.traceID, time.Now(), func() ID { return NewSpanID([8]byte{0x01}) })
+			_, err := FromTaskTree(tc.taskTree, tc.traceID, time.Now(), func() ID { return NewSpanID([8]byte{0x01}) }, nil)
 			assert.Error(t, err)
 		})
 	}
 }
 
+func TestFromTaskTreeResolvesAttributeGenerators(t *testing.T) {
+	sequence := attribute.NewSequence(1, 1)
+	root := task.NewTreeNode(
+		task.NewDefinition(
+			"root-task",
+			true,
+			task.NewResource("service-a", map[string]attribute.Expression{"service.instance.id": sequence}),
+			map[string]attribute.Expression{"request.id": sequence, "team": attribute.String("team-a")},
+			task.KindServer,
+			nil,
+			NewAbsoluteDurationDelay(0),
+			NewAbsoluteDurationDuration(1*time.Second),
+			nil,
+			[]*task.ExternalID{},
+			[]task.Event{
+				task.NewEvent("event", NewAbsoluteDurationDelay(0), map[string]attribute.Expression{"event.id": sequence}),
+			},
+			[]task.ConditionalDefinition{},
+			task.DefinitionOptions{},
+		),
+	)
+	idGen := func() ID { return NewSpanID([8]byte{0x01}) }
+
+	first, err := FromTaskTree(root, NewTraceID([16]byte{0x01}), time.Now(), idGen, nil)
+	assert.NoError(t, err)
+	second, err := FromTaskTree(root, NewTraceID([16]byte{0x02}), time.Now(), idGen, nil)
+	assert.NoError(t, err)
+
+	// generators are resolved for each span instance, in the order of events, attributes and resource
+	assert.Equal(t, map[string]attribute.Value{"event.id": attribute.Int(1)}, first.Events()[0].Attributes())
+	assert.Equal(t, map[string]attribute.Value{"request.id": attribute.Int(2), "team": attribute.String("team-a")}, first.Attributes())
+	assert.Equal(t, map[string]attribute.Value{"service.instance.id": attribute.Int(3)}, first.ResourceAttributes())
+	assert.Equal(t, map[string]attribute.Value{"request.id": attribute.Int(5), "team": attribute.String("team-a")}, second.Attributes())
+	assert.Equal(t, map[string]attribute.Value{"service.instance.id": attribute.Int(6)}, second.ResourceAttributes())
+}
+
+func TestFromTaskTreeSchedulesChildren(t *testing.T) {
+	newTask := func(name string, delay time.Duration, duration time.Duration, schedule task.Schedule) *task.TreeNode {
+		return task.NewTreeNode(
+			task.NewDefinition(
+				name,
+				false,
+				task.NewResource("service-a", nil),
+				nil,
+				task.KindInternal,
+				nil,
+				NewAbsoluteDurationDelay(delay),
+				NewAbsoluteDurationDuration(duration),
+				nil,
+				[]*task.ExternalID{},
+				[]task.Event{},
+				[]task.ConditionalDefinition{},
+				task.DefinitionOptions{
+					Schedule: schedule,
+				},
+			),
+		)
+	}
+	newTree := func(schedule task.Schedule) *task.TreeNode {
+		root := newTask("root", 0, 10*time.Second, schedule)
+		//nolint:errcheck
+		root.AddChild(newTask("auth", 0, 1*time.Second, task.Schedule{}))
+		//nolint:errcheck
+		root.AddChild(newTask("load", 500*time.Millisecond, 2*time.Second, task.Schedule{}))
+		//nolint:errcheck
+		root.AddChild(newTask("render", 0, 3*time.Second, task.Schedule{}))
+		return root
+	}
+	baseTime := time.Now()
+
+	testCases := []struct {
+		name     string
+		schedule task.Schedule
+		expected [][2]time.Duration
+	}{
+		{
+			name:     "start every child relative to the parent in parallel mode",
+			schedule: task.Schedule{},
+			expected: [][2]time.Duration{{0, 1 * time.Second}, {500 * time.Millisecond, 2500 * time.Millisecond}, {0, 3 * time.Second}},
+		},
+		{
+			name:     "start every child after the previous sibling in sequential mode",
+			schedule: task.NewSchedule(task.ScheduleSequential, 0),
+			expected: [][2]time.Duration{{0, 1 * time.Second}, {1500 * time.Millisecond, 3500 * time.Millisecond}, {3500 * time.Millisecond, 6500 * time.Millisecond}},
+		},
+		{
+			name:     "wait for the gap between children in sequential mode",
+			schedule: task.NewSchedule(task.ScheduleSequential, 100*time.Millisecond),
+			expected: [][2]time.Duration{{0, 1 * time.Second}, {1600 * time.Millisecond, 3600 * time.Millisecond}, {3700 * time.Millisecond, 6700 * time.Millisecond}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			root, err := FromTaskTree(newTree(tc.schedule), NewTraceID([16]byte{0x01}), baseTime, func() ID { return NewSpanID([8]byte{0x01}) }, nil)
+			assert.NoError(t, err)
+			assert.Len(t, root.Children(), len(tc.expected))
+			for i, child := range root.Children() {
+				assert.Equal(t, baseTime.Add(tc.expected[i][0]), child.StartTime(), child.Name())
+				assert.Equal(t, baseTime.Add(tc.expected[i][1]), child.EndTime(), child.Name())
+			}
+		})
+	}
+}
+
+func TestFromTaskTreeSlowsDownSpans(t *testing.T) {
+	slowDown := func(by time.Duration, factor float64) []task.ConditionalDefinition {
+		return []task.ConditionalDefinition{
+			task.NewConditionalDefinition(
+				task.NewProbabilisticCondition(1.0, func() float64 { return 0 }),
+				[]task.Effect{task.FromSlowDownEffect(task.NewSlowDownEffect(by, factor))},
+			),
+		}
+	}
+	newTask := func(name string, delay time.Duration, duration time.Duration, schedule task.Schedule, conditionalDefinitions []task.ConditionalDefinition) *task.TreeNode {
+		return task.NewTreeNode(
+			task.NewDefinition(
+				name,
+				false,
+				task.NewResource("service-a", nil),
+				nil,
+				task.KindInternal,
+				nil,
+				NewAbsoluteDurationDelay(delay),
+				NewAbsoluteDurationDuration(duration),
+				nil,
+				[]*task.ExternalID{},
+				[]task.Event{},
+				conditionalDefinitions,
+				task.DefinitionOptions{
+					Schedule: schedule,
+				},
+			),
+		)
+	}
+	newTree := func(schedule task.Schedule, slowedDown string, effect []task.ConditionalDefinition) *task.TreeNode {
+		conditionalDefinitions := func(name string) []task.ConditionalDefinition {
+			if name == slowedDown {
+				return effect
+			}
+			return []task.ConditionalDefinition{}
+		}
+		root := newTask("root", 0, 4*time.Second, schedule, conditionalDefinitions("root"))
+		//nolint:errcheck
+		root.AddChild(newTask("auth", 0, 1*time.Second, task.Schedule{}, conditionalDefinitions("auth")))
+		//nolint:errcheck
+		root.AddChild(newTask("load", 500*time.Millisecond, 2*time.Second, task.Schedule{}, conditionalDefinitions("load")))
+		//nolint:errcheck
+		root.AddChild(newTask("render", 0, 3*time.Second, task.Schedule{}, conditionalDefinitions("render")))
+		return root
+	}
+	baseTime := time.Now()
+
+	testCases := []struct {
+		name       string
+		schedule   task.Schedule
+		slowedDown string
+		effect     []task.ConditionalDefinition
+		expected   [][2]time.Duration
+	}{
+		{
+			name:       "add the fixed time to the span",
+			schedule:   task.Schedule{},
+			slowedDown: "load",
+			effect:     slowDown(500*time.Millisecond, 1),
+			expected:   [][2]time.Duration{{0, 4 * time.Second}, {0, 1 * time.Second}, {500 * time.Millisecond, 3 * time.Second}, {0, 3 * time.Second}},
+		},
+		{
+			name:       "multiply the duration of the span",
+			schedule:   task.Schedule{},
+			slowedDown: "root",
+			effect:     slowDown(0, 1.5),
+			expected:   [][2]time.Duration{{0, 6 * time.Second}, {0, 1 * time.Second}, {500 * time.Millisecond, 2500 * time.Millisecond}, {0, 3 * time.Second}},
+		},
+		{
+			name:       "stretch the parent so that it still contains the slowed down child",
+			schedule:   task.Schedule{},
+			slowedDown: "render",
+			effect:     slowDown(time.Second, 1.5),
+			expected:   [][2]time.Duration{{0, 5500 * time.Millisecond}, {0, 1 * time.Second}, {500 * time.Millisecond, 2500 * time.Millisecond}, {0, 5500 * time.Millisecond}},
+		},
+		{
+			name:       "shift the later siblings and stretch the parent only by the slow-down in sequential mode",
+			schedule:   task.NewSchedule(task.ScheduleSequential, 0),
+			slowedDown: "auth",
+			effect:     slowDown(time.Second, 1),
+			expected:   [][2]time.Duration{{0, 5 * time.Second}, {0, 2 * time.Second}, {2500 * time.Millisecond, 4500 * time.Millisecond}, {4500 * time.Millisecond, 7500 * time.Millisecond}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			root, err := FromTaskTree(newTree(tc.schedule, tc.slowedDown, tc.effect), NewTraceID([16]byte{0x01}), baseTime, func() ID { return NewSpanID([8]byte{0x01}) }, nil)
+			assert.NoError(t, err)
+			spans := append([]*TreeNode{root}, root.Children()...)
+			assert.Len(t, spans, len(tc.expected))
+			for i, s := range spans {
+				assert.Equal(t, baseTime.Add(tc.expected[i][0]), s.StartTime(), s.Name())
+				assert.Equal(t, baseTime.Add(tc.expected[i][1]), s.EndTime(), s.Name())
+			}
+		})
+	}
+}
+
+func TestFromTaskTreePropagatesErrors(t *testing.T) {
+	markAsFailed := []task.ConditionalDefinition{
+		task.NewConditionalDefinition(
+			task.NewProbabilisticCondition(1.0, func() float64 { return 0 }),
+			[]task.Effect{task.FromMarkAsFailedEffect(task.NewMarkAsFailedEffect("connection refused"))},
+		),
+	}
+	newTask := func(name string, kind task.Kind, duration time.Duration, errorPropagation task.ErrorPropagation, conditionalDefinitions []task.ConditionalDefinition) *task.TreeNode {
+		return task.NewTreeNode(
+			task.NewDefinition(
+				name,
+				false,
+				task.NewResource("service-a", nil),
+				nil,
+				kind,
+				nil,
+				NewAbsoluteDurationDelay(0),
+				NewAbsoluteDurationDuration(duration),
+				nil,
+				[]*task.ExternalID{},
+				[]task.Event{},
+				conditionalDefinitions,
+				task.DefinitionOptions{
+					ErrorPropagation: errorPropagation,
+				},
+			),
+		)
+	}
+	// gateway (server) -> api (server) -> handler (internal) -> db (client, failed)
+	newTree := func(db task.ErrorPropagation, handler task.ErrorPropagation) *task.TreeNode {
+		gateway := newTask("gateway", task.KindServer, 4*time.Second, task.ErrorPropagation{}, nil)
+		api := newTask("api", task.KindServer, 3*time.Second, task.ErrorPropagation{}, nil)
+		h := newTask("handler", task.KindInternal, 2*time.Second, handler, nil)
+		//nolint:errcheck
+		h.AddChild(newTask("db", task.KindClient, 1*time.Second, db, markAsFailed))
+		//nolint:errcheck
+		api.AddChild(h)
+		//nolint:errcheck
+		gateway.AddChild(api)
+		return gateway
+	}
+	baseTime := time.Now()
+	propagated := StatusError("span db failed: connection refused")
+
+	testCases := []struct {
+		name     string
+		db       task.ErrorPropagation
+		handler  task.ErrorPropagation
+		expected []Status // statuses of gateway, api, handler and db
+	}{
+		{
+			name:     "not propagate by default",
+			expected: []Status{StatusOK, StatusOK, StatusOK, StatusError("connection refused")},
+		},
+		{
+			name:     "propagate to the parent",
+			db:       task.NewErrorPropagation(task.ErrorPropagationParent, false),
+			expected: []Status{StatusOK, StatusOK, propagated, StatusError("connection refused")},
+		},
+		{
+			name:     "propagate further according to the policy of the parent",
+			db:       task.NewErrorPropagation(task.ErrorPropagationParent, false),
+			handler:  task.NewErrorPropagation(task.ErrorPropagationParent, false),
+			expected: []Status{StatusOK, propagated, propagated, StatusError("connection refused")},
+		},
+		{
+			name:     "propagate up to the nearest server span",
+			db:       task.NewErrorPropagation(task.ErrorPropagationServer, false),
+			expected: []Status{StatusOK, propagated, propagated, StatusError("connection refused")},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			root, err := FromTaskTree(newTree(tc.db, tc.handler), NewTraceID([16]byte{0x01}), baseTime, func() ID { return NewSpanID([8]byte{0x01}) }, nil)
+			assert.NoError(t, err)
+			spans := []*TreeNode{root}
+			for len(spans[len(spans)-1].Children()) > 0 {
+				spans = append(spans, spans[len(spans)-1].Children()[0])
+			}
+			assert.Len(t, spans, len(tc.expected))
+			for i, s := range spans {
+				assert.Equal(t, tc.expected[i], s.Status(), s.Name())
+				assert.Empty(t, s.Events(), s.Name())
+			}
+		})
+	}
+
+	t.Run("record an exception event when the child ends", func(t *testing.T) {
+		root, err := FromTaskTree(newTree(task.NewErrorPropagation(task.ErrorPropagationParent, true), task.ErrorPropagation{}), NewTraceID([16]byte{0x01}), baseTime, func() ID { return NewSpanID([8]byte{0x01}) }, nil)
+		assert.NoError(t, err)
+		handler := root.Children()[0].Children()[0]
+		assert.Equal(t, []Event{
+			NewEvent(ExceptionEventName, baseTime.Add(1*time.Second), map[string]attribute.Value{"exception.message": attribute.String("span db failed: connection refused")}),
+		}, handler.Events())
+	})
+}
+
+func TestFromTaskTreeRetriesFailedAttempts(t *testing.T) {
+	newTask := func(name string, kind task.Kind, duration time.Duration, schedule task.Schedule, conditionalDefinitions []task.ConditionalDefinition, errorPropagation task.ErrorPropagation, retry *task.Retry) *task.TreeNode {
+		return task.NewTreeNode(
+			task.NewDefinition(
+				name,
+				false,
+				task.NewResource("service-a", nil),
+				nil,
+				kind,
+				nil,
+				NewAbsoluteDurationDelay(0),
+				NewAbsoluteDurationDuration(duration),
+				nil,
+				[]*task.ExternalID{},
+				[]task.Event{},
+				conditionalDefinitions,
+				task.DefinitionOptions{
+					Schedule:         schedule,
+					ErrorPropagation: errorPropagation,
+					Retry:            retry,
+				},
+			),
+		)
+	}
+	// root (sequential) -> call (client, retried) -> render
+	newTree := func(outcomes []float64) *task.TreeNode {
+		i := 0
+		randomness := func() float64 {
+			v := outcomes[i%len(outcomes)]
+			i++
+			return v
+		}
+		markAsFailed := []task.ConditionalDefinition{
+			task.NewConditionalDefinition(
+				task.NewProbabilisticCondition(0.5, randomness),
+				[]task.Effect{task.FromMarkAsFailedEffect(task.NewMarkAsFailedEffect("unavailable"))},
+			),
+		}
+		retry := task.NewRetry(3, task.NewBackoff(task.BackoffFixed, 100*time.Millisecond, 0, 0, 0, nil), nil)
+		root := newTask("root", task.KindServer, 4*time.Second, task.NewSchedule(task.ScheduleSequential, 0), nil, task.ErrorPropagation{}, nil)
+		//nolint:errcheck
+		root.AddChild(newTask("call", task.KindClient, 1*time.Second, task.Schedule{}, markAsFailed, task.NewErrorPropagation(task.ErrorPropagationParent, false), &retry))
+		//nolint:errcheck
+		root.AddChild(newTask("render", task.KindInternal, 1*time.Second, task.Schedule{}, nil, task.ErrorPropagation{}, nil))
+		return root
+	}
+	baseTime := time.Now()
+
+	t.Run("attempt again until the attempt succeeds", func(t *testing.T) {
+		root, err := FromTaskTree(newTree([]float64{0, 0, 1}), NewTraceID([16]byte{0x01}), baseTime, func() ID { return NewSpanID([8]byte{0x01}) }, nil)
+		assert.NoError(t, err)
+		children := root.Children()
+		assert.Len(t, children, 4)
+		expected := []struct {
+			name     string
+			start    time.Duration
+			end      time.Duration
+			status   Status
+			attempts *int64
+		}{
+			{name: "call", start: 0, end: 1 * time.Second, status: StatusError("unavailable"), attempts: ptrInt64(1)},
+			{name: "call", start: 1100 * time.Millisecond, end: 2100 * time.Millisecond, status: StatusError("unavailable"), attempts: ptrInt64(2)},
+			{name: "call", start: 2200 * time.Millisecond, end: 3200 * time.Millisecond, status: StatusOK, attempts: ptrInt64(3)},
+			{name: "render", start: 3200 * time.Millisecond, end: 4200 * time.Millisecond, status: StatusOK},
+		}
+		for i, e := range expected {
+			assert.Equal(t, e.name, children[i].Name())
+			assert.Equal(t, baseTime.Add(e.start), children[i].StartTime(), i)
+			assert.Equal(t, baseTime.Add(e.end), children[i].EndTime(), i)
+			assert.Equal(t, e.status, children[i].Status(), i)
+			if e.attempts != nil {
+				assert.Equal(t, attribute.Int(*e.attempts), children[i].Attributes()[RetryAttemptAttributeKey], i)
+			} else {
+				assert.NotContains(t, children[i].Attributes(), RetryAttemptAttributeKey, i)
+			}
+		}
+		// the parent is stretched to contain the children shifted by the retries, and the failures of the retried attempts do not propagate
+		assert.Equal(t, baseTime.Add(4200*time.Millisecond), root.EndTime())
+		assert.Equal(t, StatusOK, root.Status())
+	})
+
+	t.Run("not record the attempt of the spans without retry", func(t *testing.T) {
+		markAsFailed := []task.ConditionalDefinition{
+			task.NewConditionalDefinition(
+				task.NewProbabilisticCondition(0.5, func() float64 { return 0 }),
+				[]task.Effect{task.FromMarkAsFailedEffect(task.NewMarkAsFailedEffect("unavailable"))},
+			),
+		}
+		taskTree := newTask("root", task.KindServer, 4*time.Second, task.Schedule{}, nil, task.ErrorPropagation{}, nil)
+		//nolint:errcheck
+		taskTree.AddChild(newTask("call", task.KindClient, 1*time.Second, task.Schedule{}, markAsFailed, task.ErrorPropagation{}, nil))
+		root, err := FromTaskTree(taskTree, NewTraceID([16]byte{0x01}), baseTime, func() ID { return NewSpanID([8]byte{0x01}) }, nil)
+		assert.NoError(t, err)
+		assert.Len(t, root.Children(), 1)
+		// the failed span is not attempted again, and neither span carries the attempt
+		assert.Equal(t, StatusError("unavailable"), root.Children()[0].Status())
+		for _, s := range []*TreeNode{root, root.Children()[0]} {
+			assert.NotContains(t, s.Attributes(), RetryAttemptAttributeKey, s.Name())
+		}
+	})
+
+	t.Run("give up when the attempts run out", func(t *testing.T) {
+		root, err := FromTaskTree(newTree([]float64{0}), NewTraceID([16]byte{0x01}), baseTime, func() ID { return NewSpanID([8]byte{0x01}) }, nil)
+		assert.NoError(t, err)
+		children := root.Children()
+		assert.Len(t, children, 4)
+		for _, child := range children[:3] {
+			assert.Equal(t, StatusError("unavailable"), child.Status())
+		}
+		assert.Equal(t, StatusError("span call failed: unavailable"), root.Status())
+	})
+}
+
+func TestFromTaskTreeAppliesTimeout(t *testing.T) {
+	newTask := func(name string, duration time.Duration, schedule task.Schedule, timeout *task.Timeout) *task.TreeNode {
+		return task.NewTreeNode(
+			task.NewDefinition(
+				name,
+				false,
+				task.NewResource("service-a", nil),
+				nil,
+				task.KindInternal,
+				nil,
+				NewAbsoluteDurationDelay(0),
+				NewAbsoluteDurationDuration(duration),
+				nil,
+				[]*task.ExternalID{},
+				[]task.Event{
+					task.NewEvent("done", NewRelativeDurationDelay(1.0), nil),
+				},
+				[]task.ConditionalDefinition{},
+				task.DefinitionOptions{
+					Schedule: schedule,
+					Timeout:  timeout,
+				},
+			),
+		)
+	}
+	// root (sequential) -> a (0s-1s), b (1s-2.5s), c (2.5s-3.5s)
+	newTree := func(timeout *task.Timeout) *task.TreeNode {
+		root := newTask("root", 4*time.Second, task.NewSchedule(task.ScheduleSequential, 0), timeout)
+		//nolint:errcheck
+		root.AddChild(newTask("a", 1*time.Second, task.Schedule{}, nil))
+		//nolint:errcheck
+		root.AddChild(newTask("b", 1500*time.Millisecond, task.Schedule{}, nil))
+		//nolint:errcheck
+		root.AddChild(newTask("c", 1*time.Second, task.Schedule{}, nil))
+		return root
+	}
+	timeout := func(after time.Duration, overrun task.TimeoutOverrun) *task.Timeout {
+		t := task.NewTimeout(after, overrun)
+		return &t
+	}
+	deadlineExceeded := StatusError(DeadlineExceededMessage)
+	baseTime := time.Now()
+
+	type expectedSpan struct {
+		name   string
+		end    time.Duration
+		status Status
+		events int
+	}
+	testCases := []struct {
+		name     string
+		timeout  *task.Timeout
+		expected []expectedSpan
+	}{
+		{
+			name:    "do nothing if the span ends before the deadline",
+			timeout: timeout(5*time.Second, task.TimeoutTruncate),
+			expected: []expectedSpan{
+				{name: "root", end: 4 * time.Second, status: StatusOK, events: 1},
+				{name: "a", end: 1 * time.Second, status: StatusOK, events: 1},
+				{name: "b", end: 2500 * time.Millisecond, status: StatusOK, events: 1},
+				{name: "c", end: 3500 * time.Millisecond, status: StatusOK, events: 1},
+			},
+		},
+		{
+			name:    "truncate the children running past the deadline",
+			timeout: timeout(2*time.Second, task.TimeoutTruncate),
+			expected: []expectedSpan{
+				{name: "root", end: 2 * time.Second, status: deadlineExceeded, events: 0},
+				{name: "a", end: 1 * time.Second, status: StatusOK, events: 1},
+				{name: "b", end: 2 * time.Second, status: deadlineExceeded, events: 0},
+			},
+		},
+		{
+			name:    "leave the children running past the deadline dangling",
+			timeout: timeout(2*time.Second, task.TimeoutDangle),
+			expected: []expectedSpan{
+				{name: "root", end: 2 * time.Second, status: deadlineExceeded, events: 0},
+				{name: "a", end: 1 * time.Second, status: StatusOK, events: 1},
+				{name: "b", end: 2500 * time.Millisecond, status: StatusOK, events: 1},
+				{name: "c", end: 3500 * time.Millisecond, status: StatusOK, events: 1},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			root, err := FromTaskTree(newTree(tc.timeout), NewTraceID([16]byte{0x01}), baseTime, func() ID { return NewSpanID([8]byte{0x01}) }, nil)
+			assert.NoError(t, err)
+			spans := append([]*TreeNode{root}, root.Children()...)
+			assert.Len(t, spans, len(tc.expected))
+			for i, e := range tc.expected {
+				assert.Equal(t, e.name, spans[i].Name())
+				assert.Equal(t, baseTime.Add(e.end), spans[i].EndTime(), e.name)
+				assert.Equal(t, e.status, spans[i].Status(), e.name)
+				assert.Len(t, spans[i].Events(), e.events, e.name)
+			}
+		})
+	}
+}
+
+func TestDegradeDataQuality(t *testing.T) {
+	newTask := func(name string, resource task.Resource, dataQuality task.DataQuality) *task.TreeNode {
+		return task.NewTreeNode(
+			task.NewDefinition(
+				name,
+				false,
+				resource,
+				nil,
+				task.KindInternal,
+				nil,
+				NewAbsoluteDurationDelay(0),
+				NewAbsoluteDurationDuration(time.Second),
+				nil,
+				[]*task.ExternalID{},
+				[]task.Event{},
+				[]task.ConditionalDefinition{},
+				task.DefinitionOptions{
+					DataQuality: dataQuality,
+				},
+			),
+		)
+	}
+	dataQuality := func(drop, duplicate, bogusParent float64) task.DataQuality {
+		q, err := task.NewDataQuality(drop, duplicate, bogusParent)
+		assert.NoError(t, err)
+		return *q
+	}
+	// root (service-a) -> middle (service-b) -> leaf (service-b)
+	newTree := func(serviceBLoss float64, middleDataQuality task.DataQuality) *TreeNode {
+		serviceA := task.NewResource("service-a", nil)
+		serviceB := task.NewResource("service-b", nil).WithLossProbability(serviceBLoss)
+		root := newTask("root", serviceA, task.DataQuality{})
+		middle := newTask("middle", serviceB, middleDataQuality)
+		//nolint:errcheck
+		middle.AddChild(newTask("leaf", serviceB, task.DataQuality{}))
+		//nolint:errcheck
+		root.AddChild(middle)
+		var next byte
+		tree, err := FromTaskTree(root, NewTraceID([16]byte{0x01}), time.Now(), func() ID { next++; return NewSpanID([8]byte{next}) }, nil)
+		assert.NoError(t, err)
+		return tree
+	}
+	bogusID := NewSpanID([8]byte{0xff})
+	degrade := func(tree *TreeNode) int {
+		draws := 0
+		tree.DegradeDataQuality(func() float64 { draws++; return 0.5 }, func() ID { return bogusID })
+		return draws
+	}
+
+	t.Run("deliver spans without flaws as they are", func(t *testing.T) {
+		tree := newTree(0, task.DataQuality{})
+		assert.Equal(t, 0, degrade(tree))
+		middle := tree.Children()[0]
+		for _, span := range []*TreeNode{tree, middle, middle.Children()[0]} {
+			assert.False(t, span.Dropped())
+			assert.False(t, span.Duplicated())
+		}
+		assert.Equal(t, tree.ID(), *middle.ParentID())
+	})
+
+	t.Run("drop the span and keep its children as orphans", func(t *testing.T) {
+		tree := newTree(0, dataQuality(1, 0, 0))
+		degrade(tree)
+		middle := tree.Children()[0]
+		leaf := middle.Children()[0]
+		assert.True(t, middle.Dropped())
+		assert.False(t, leaf.Dropped())
+		assert.Equal(t, middle.ID(), *leaf.ParentID())
+	})
+
+	t.Run("duplicate the span", func(t *testing.T) {
+		tree := newTree(0, dataQuality(0, 1, 0))
+		degrade(tree)
+		assert.True(t, tree.Children()[0].Duplicated())
+		assert.False(t, tree.Children()[0].Children()[0].Duplicated())
+	})
+
+	t.Run("point the span at a parent that does not exist", func(t *testing.T) {
+		tree := newTree(0, dataQuality(0, 0, 1))
+		degrade(tree)
+		middle := tree.Children()[0]
+		assert.Equal(t, bogusID, *middle.ParentID())
+		assert.Equal(t, middle.ID(), *middle.Children()[0].ParentID())
+	})
+
+	t.Run("lose all the spans of a resource, deciding once per trace", func(t *testing.T) {
+		tree := newTree(0.7, task.DataQuality{})
+		assert.Equal(t, 1, degrade(tree))
+		middle := tree.Children()[0]
+		assert.False(t, tree.Dropped())
+		assert.True(t, middle.Dropped())
+		assert.True(t, middle.Children()[0].Dropped())
+	})
+
+	t.Run("reject invalid probabilities", func(t *testing.T) {
+		_, err := task.NewDataQuality(0, 1.5, 0)
+		assert.EqualError(t, err, "duplicate probability must be between 0 and 1")
+	})
+}
+
 func TestShiftTimestamps(t *testing.T) {
 	now := time.Now()
 	rootNodeStartTime := now.Add(0 * time.Second)
@@ -1285,3 +1939,77 @@ func NewRelativeDurationDuration(v float64) task.Duration {
 	d, _ := task.NewDuration(e)
 	return *d
 }
+
+func TestSkewClocks(t *testing.T) {
+	newTask := func(name string, resource task.Resource, events []task.Event) *task.TreeNode {
+		return task.NewTreeNode(
+			task.NewDefinition(
+				name,
+				false,
+				resource,
+				nil,
+				task.KindInternal,
+				nil,
+				NewAbsoluteDurationDelay(0),
+				NewAbsoluteDurationDuration(time.Second),
+				nil,
+				[]*task.ExternalID{},
+				events,
+				[]task.ConditionalDefinition{},
+				task.DefinitionOptions{},
+			),
+		)
+	}
+	clockSkew := func(minimum, maximum time.Duration, randomness func() float64) task.ClockSkew {
+		s, err := task.NewClockSkew(minimum, maximum, randomness)
+		assert.NoError(t, err)
+		return *s
+	}
+	baseStartTime := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	// root (service-a) -> middle (service-b, with an event) -> leaf (service-b)
+	newTree := func(serviceBClockSkew task.ClockSkew) *TreeNode {
+		serviceA := task.NewResource("service-a", nil)
+		serviceB := task.NewResource("service-b", nil).WithClockSkew(serviceBClockSkew)
+		root := newTask("root", serviceA, []task.Event{})
+		middle := newTask("middle", serviceB, []task.Event{task.NewEvent("event", NewAbsoluteDurationDelay(100*time.Millisecond), nil)})
+		//nolint:errcheck
+		middle.AddChild(newTask("leaf", serviceB, []task.Event{}))
+		//nolint:errcheck
+		root.AddChild(middle)
+		tree, err := FromTaskTree(root, NewTraceID([16]byte{0x01}), baseStartTime, func() ID { return NewSpanID([8]byte{0x01}) }, nil)
+		assert.NoError(t, err)
+		tree.SkewClocks()
+		return tree
+	}
+
+	t.Run("shift the spans and events of the service by the fixed offset", func(t *testing.T) {
+		tree := newTree(clockSkew(-200*time.Millisecond, -200*time.Millisecond, nil))
+		middle := tree.Children()[0]
+		leaf := middle.Children()[0]
+		assert.Equal(t, baseStartTime, tree.StartTime())
+		assert.Equal(t, baseStartTime.Add(time.Second), tree.EndTime())
+		// the children of the service start before their parent in another service
+		assert.Equal(t, baseStartTime.Add(-200*time.Millisecond), middle.StartTime())
+		assert.Equal(t, baseStartTime.Add(800*time.Millisecond), middle.EndTime())
+		assert.Equal(t, baseStartTime.Add(-100*time.Millisecond), middle.Events()[0].OccurredAt())
+		assert.Equal(t, baseStartTime.Add(-200*time.Millisecond), leaf.StartTime())
+	})
+
+	t.Run("draw the offset once for all the spans of the service", func(t *testing.T) {
+		draws := 0
+		tree := newTree(clockSkew(0, time.Second, func() float64 { draws++; return 0.5 }))
+		middle := tree.Children()[0]
+		assert.Equal(t, 1, draws)
+		assert.Equal(t, baseStartTime.Add(500*time.Millisecond), middle.StartTime())
+		assert.Equal(t, baseStartTime.Add(500*time.Millisecond), middle.Children()[0].StartTime())
+	})
+
+	t.Run("reject a range whose min is greater than max", func(t *testing.T) {
+		_, err := task.NewClockSkew(time.Second, 0, nil)
+		assert.EqualError(t, err, "clock skew min must be less than or equal to max")
+	})
+}
+
+func ptrInt64(i int64) *int64 {
+	return &i
+}
@@ -0,0 +1,79 @@
+package span
+
+import (
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
+	"time"
+)
+
+// DeadlineExceededMessage is the status message of the spans cut off by a timeout
+const DeadlineExceededMessage = "deadline exceeded"
+
+// applyTimeout ends the span at the deadline with an error if it runs past the deadline.
+// The descendants running past the deadline are truncated or left dangling according to the timeout.
+func (n *TreeNode) applyTimeout(timeout task.Timeout) {
+	deadline := n.startTime.Add(timeout.After())
+	if !n.endTime.After(deadline) {
+		return
+	}
+	n.cutOffAt(deadline)
+	if timeout.Overrun() == task.TimeoutTruncate {
+		n.truncateDescendantsAt(deadline)
+	}
+}
+
+// truncates the descendants running past the deadline and drops the ones that would start after it
+func (n *TreeNode) truncateDescendantsAt(deadline time.Time) {
+	children := make([]*TreeNode, 0, len(n.children))
+	for _, child := range n.children {
+		if !child.startTime.Before(deadline) {
+			// the child would start after the deadline, so it never runs
+			for id := range child.ExternalIDToSpan() {
+				n.truncatedExternalIDs = append(n.truncatedExternalIDs, id)
+			}
+			continue
+		}
+		if child.endTime.After(deadline) {
+			child.cutOffAt(deadline)
+		}
+		child.truncateDescendantsAt(deadline)
+		children = append(children, child)
+	}
+	n.children = children
+}
+
+// ends the span at the deadline with an error, dropping the events after it
+func (n *TreeNode) cutOffAt(deadline time.Time) {
+	n.delayedBy = max(n.delayedBy-n.endTime.Sub(deadline), 0)
+	n.endTime = deadline
+	n.status = StatusError(DeadlineExceededMessage)
+	events := make([]Event, 0, len(n.events))
+	for _, e := range n.events {
+		if !e.OccurredAt().After(deadline) {
+			events = append(events, e)
+		}
+	}
+	n.events = events
+}
+
+// TruncatedExternalIDs returns the external IDs of the spans dropped from the tree by timeouts
+func (n *TreeNode) TruncatedExternalIDs() []task.ExternalID {
+	ids := append([]task.ExternalID{}, n.truncatedExternalIDs...)
+	for _, child := range n.children {
+		ids = append(ids, child.TruncatedExternalIDs()...)
+	}
+	return ids
+}
+
+// RemoveLinksTo removes the links of the span and its descendants to the given external IDs, e.g. the ones of the dropped spans
+func (n *TreeNode) RemoveLinksTo(externalIDs map[task.ExternalID]struct{}) {
+	linkedToExternalID := make([]*task.ExternalID, 0, len(n.linkedToExternalID))
+	for _, id := range n.linkedToExternalID {
+		if _, removed := externalIDs[*id]; !removed {
+			linkedToExternalID = append(linkedToExternalID, id)
+		}
+	}
+	n.linkedToExternalID = linkedToExternalID
+	for _, child := range n.children {
+		child.RemoveLinksTo(externalIDs)
+	}
+}
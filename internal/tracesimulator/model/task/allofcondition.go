@@ -0,0 +1,11 @@
+package task
+
+// AllOfCondition represents a condition that requires all inner conditions to be met.
+type AllOfCondition struct {
+	conditions []Condition
+}
+
+// Conditions returns the inner conditions that must be met.
+func (a *AllOfCondition) Conditions() []Condition {
+	return a.conditions
+}
@@ -1,18 +1,20 @@
 package task
 
+import "github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/attribute"
+
 type AnnotateEffect struct {
 	// attributes is a map of attributes to be added to the task.
-	attributes map[string]string
+	attributes map[string]attribute.Expression
 }
 
 // NewAnnotateEffect creates a new AnnotateEffect with the given attributes.
-func NewAnnotateEffect(attributes map[string]string) AnnotateEffect {
+func NewAnnotateEffect(attributes map[string]attribute.Expression) AnnotateEffect {
 	return AnnotateEffect{
 		attributes: attributes,
 	}
 }
 
 // Attributes returns the attributes to be added to the task.
-func (a AnnotateEffect) Attributes() map[string]string {
+func (a AnnotateEffect) Attributes() map[string]attribute.Expression {
 	return a.attributes
 }
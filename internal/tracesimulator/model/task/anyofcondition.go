@@ -0,0 +1,11 @@
+package task
+
+// AnyOfCondition represents a condition that requires at least one of the inner conditions to be met.
+type AnyOfCondition struct {
+	conditions []Condition
+}
+
+// Conditions returns the inner conditions of which at least one must be met.
+func (a *AnyOfCondition) Conditions() []Condition {
+	return a.conditions
+}
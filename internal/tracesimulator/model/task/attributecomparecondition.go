@@ -0,0 +1,30 @@
+package task
+
+// ComparisonOperator defines how a numeric attribute is compared with a value.
+type ComparisonOperator string
+
+const (
+	ComparisonOperatorGreaterThan        ComparisonOperator = "gt"
+	ComparisonOperatorGreaterThanOrEqual ComparisonOperator = "gte"
+	ComparisonOperatorLessThan           ComparisonOperator = "lt"
+	ComparisonOperatorLessThanOrEqual    ComparisonOperator = "lte"
+)
+
+// AttributeCompareCondition is a condition that compares a numeric attribute of a task with a specific value.
+type AttributeCompareCondition struct {
+	key      string
+	operator ComparisonOperator
+	value    float64
+}
+
+func (c AttributeCompareCondition) Key() string {
+	return c.key
+}
+
+func (c AttributeCompareCondition) Operator() ComparisonOperator {
+	return c.operator
+}
+
+func (c AttributeCompareCondition) Value() float64 {
+	return c.value
+}
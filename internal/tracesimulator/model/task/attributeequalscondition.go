@@ -0,0 +1,15 @@
+package task
+
+// AttributeEqualsCondition is a condition that checks if an attribute of a task equals a specific value.
+type AttributeEqualsCondition struct {
+	key   string
+	value string
+}
+
+func (c AttributeEqualsCondition) Key() string {
+	return c.key
+}
+
+func (c AttributeEqualsCondition) Value() string {
+	return c.value
+}
@@ -0,0 +1,17 @@
+package task
+
+import "regexp"
+
+// AttributeMatchesCondition is a condition that checks if an attribute of a task matches a regular expression.
+type AttributeMatchesCondition struct {
+	key     string
+	pattern *regexp.Regexp
+}
+
+func (c AttributeMatchesCondition) Key() string {
+	return c.key
+}
+
+func (c AttributeMatchesCondition) Pattern() *regexp.Regexp {
+	return c.pattern
+}
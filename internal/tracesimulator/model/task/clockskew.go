@@ -0,0 +1,43 @@
+package task
+
+import (
+	"fmt"
+	"time"
+)
+
+// ClockSkew represents how far the clock of a resource is off from the true time.
+// The offset is drawn uniformly from the range for each trace, or is fixed if the range is a single value.
+// The zero value has no skew.
+type ClockSkew struct {
+	min        time.Duration
+	max        time.Duration
+	randomness func() float64 // Source of randomness for the offset
+}
+
+// NewClockSkew creates a new clock skew
+func NewClockSkew(min, max time.Duration, randomness func() float64) (*ClockSkew, error) {
+	if min > max {
+		return nil, fmt.Errorf("clock skew min must be less than or equal to max")
+	}
+	return &ClockSkew{
+		min:        min,
+		max:        max,
+		randomness: randomness,
+	}, nil
+}
+
+func (s ClockSkew) Min() time.Duration {
+	return s.min
+}
+
+func (s ClockSkew) Max() time.Duration {
+	return s.max
+}
+
+// Resolve returns the offset of the clock. No random number is drawn if the offset is fixed.
+func (s ClockSkew) Resolve() time.Duration {
+	if s.min == s.max || s.randomness == nil {
+		return s.min
+	}
+	return s.min + time.Duration(s.randomness()*float64(s.max-s.min))
+}
@@ -1,13 +1,21 @@
 package task
 
+import "regexp"
+
 type ConditionKind string
 
 const (
-	ConditionKindProbabilistic  ConditionKind = "probabilistic"
-	ConditionKindAtLeast        ConditionKind = "atLeast"
-	ConditionKindChild          ConditionKind = "child"
-	ConditionKindHasAttribute   ConditionKind = "hasAttribute"
-	ConditionKindMarkedAsFailed ConditionKind = "markedAsFailed"
+	ConditionKindProbabilistic    ConditionKind = "probabilistic"
+	ConditionKindAtLeast          ConditionKind = "atLeast"
+	ConditionKindChild            ConditionKind = "child"
+	ConditionKindHasAttribute     ConditionKind = "hasAttribute"
+	ConditionKindAttributeEquals  ConditionKind = "attributeEquals"
+	ConditionKindAttributeMatches ConditionKind = "attributeMatches"
+	ConditionKindAttributeCompare ConditionKind = "attributeCompare"
+	ConditionKindMarkedAsFailed   ConditionKind = "markedAsFailed"
+	ConditionKindAllOf            ConditionKind = "allOf"
+	ConditionKindAnyOf            ConditionKind = "anyOf"
+	ConditionKindNot              ConditionKind = "not"
 )
 
 // Condition is an interface for evaluating whether an effect should be applied.
@@ -22,8 +30,20 @@ type Condition struct {
 	child *ChildCondition
 	// hasAttribute is the attribute that must be present.
 	hasAttribute *HasAttributeCondition
+	// attributeEquals is the attribute that must be equal to a value.
+	attributeEquals *AttributeEqualsCondition
+	// attributeMatches is the attribute that must match a regular expression.
+	attributeMatches *AttributeMatchesCondition
+	// attributeCompare is the numeric attribute that must satisfy a comparison.
+	attributeCompare *AttributeCompareCondition
 	// markedAsFailed is the condition that checks if the task is marked as failed.
 	markedAsFailed *MarkedAsFailedCondition
+	// allOf is the list of conditions that must all be met.
+	allOf *AllOfCondition
+	// anyOf is the list of conditions of which at least one must be met.
+	anyOf *AnyOfCondition
+	// not is the condition to be negated.
+	not *NotCondition
 }
 
 // NewProbabilisticCondition creates a new Condition with the given probability.
@@ -68,6 +88,40 @@ func NewHasAttributeCondition(key string) Condition {
 	}
 }
 
+// NewAttributeEqualsCondition creates a new Condition that checks if the attribute of the given key equals the value.
+func NewAttributeEqualsCondition(key string, value string) Condition {
+	return Condition{
+		kind: ConditionKindAttributeEquals,
+		attributeEquals: &AttributeEqualsCondition{
+			key:   key,
+			value: value,
+		},
+	}
+}
+
+// NewAttributeMatchesCondition creates a new Condition that checks if the attribute of the given key matches the pattern.
+func NewAttributeMatchesCondition(key string, pattern *regexp.Regexp) Condition {
+	return Condition{
+		kind: ConditionKindAttributeMatches,
+		attributeMatches: &AttributeMatchesCondition{
+			key:     key,
+			pattern: pattern,
+		},
+	}
+}
+
+// NewAttributeCompareCondition creates a new Condition that compares the numeric attribute of the given key with the value.
+func NewAttributeCompareCondition(key string, operator ComparisonOperator, value float64) Condition {
+	return Condition{
+		kind: ConditionKindAttributeCompare,
+		attributeCompare: &AttributeCompareCondition{
+			key:      key,
+			operator: operator,
+			value:    value,
+		},
+	}
+}
+
 // NewMarkedAsFailedCondition creates a new Condition that checks if the task is marked as failed.
 func NewMarkedAsFailedCondition() Condition {
 	return Condition{
@@ -76,6 +130,36 @@ func NewMarkedAsFailedCondition() Condition {
 	}
 }
 
+// NewAllOfCondition creates a new Condition that is met when all the given conditions are met.
+func NewAllOfCondition(conditions []Condition) Condition {
+	return Condition{
+		kind: ConditionKindAllOf,
+		allOf: &AllOfCondition{
+			conditions: conditions,
+		},
+	}
+}
+
+// NewAnyOfCondition creates a new Condition that is met when at least one of the given conditions is met.
+func NewAnyOfCondition(conditions []Condition) Condition {
+	return Condition{
+		kind: ConditionKindAnyOf,
+		anyOf: &AnyOfCondition{
+			conditions: conditions,
+		},
+	}
+}
+
+// NewNotCondition creates a new Condition that is met when the inner condition is not met.
+func NewNotCondition(inner Condition) Condition {
+	return Condition{
+		kind: ConditionKindNot,
+		not: &NotCondition{
+			inner: inner,
+		},
+	}
+}
+
 func (c Condition) Kind() ConditionKind {
 	return c.kind
 }
@@ -96,6 +180,30 @@ func (c Condition) HasAttribute() *HasAttributeCondition {
 	return c.hasAttribute
 }
 
+func (c Condition) AttributeEquals() *AttributeEqualsCondition {
+	return c.attributeEquals
+}
+
+func (c Condition) AttributeMatches() *AttributeMatchesCondition {
+	return c.attributeMatches
+}
+
+func (c Condition) AttributeCompare() *AttributeCompareCondition {
+	return c.attributeCompare
+}
+
 func (c Condition) MarkedAsFailed() *MarkedAsFailedCondition {
 	return c.markedAsFailed
 }
+
+func (c Condition) AllOf() *AllOfCondition {
+	return c.allOf
+}
+
+func (c Condition) AnyOf() *AnyOfCondition {
+	return c.anyOf
+}
+
+func (c Condition) Not() *NotCondition {
+	return c.not
+}
@@ -0,0 +1,45 @@
+package task
+
+import "fmt"
+
+// DataQuality represents the flaws of the data the spans of a task are delivered with, as probabilities per span.
+// The zero value delivers the spans as they are.
+type DataQuality struct {
+	drop        float64 // Probability of the span being dropped while its descendants are kept as orphans
+	duplicate   float64 // Probability of the span being delivered twice
+	bogusParent float64 // Probability of the span pointing at a parent that does not exist
+}
+
+// NewDataQuality creates a new data quality
+func NewDataQuality(drop, duplicate, bogusParent float64) (*DataQuality, error) {
+	probabilities := []struct {
+		name  string
+		value float64
+	}{
+		{"drop", drop},
+		{"duplicate", duplicate},
+		{"bogus parent", bogusParent},
+	}
+	for _, p := range probabilities {
+		if p.value < 0 || p.value > 1 {
+			return nil, fmt.Errorf("%s probability must be between 0 and 1", p.name)
+		}
+	}
+	return &DataQuality{
+		drop:        drop,
+		duplicate:   duplicate,
+		bogusParent: bogusParent,
+	}, nil
+}
+
+func (q DataQuality) Drop() float64 {
+	return q.drop
+}
+
+func (q DataQuality) Duplicate() float64 {
+	return q.duplicate
+}
+
+func (q DataQuality) BogusParent() float64 {
+	return q.bogusParent
+}
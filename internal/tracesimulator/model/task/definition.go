@@ -1,23 +1,41 @@
 package task
 
+import "github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/attribute"
+
 // Definition represents a task in the trace
 type Definition struct {
 	name                   string
 	isResourceEntryPoint   bool
 	resource               Resource
-	attributes             map[string]string
+	attributes             map[string]attribute.Expression
 	kind                   Kind
 	externalID             *ExternalID
-	delay                  Delay                   // Relative time from the start of the parent task
-	duration               Duration                // Relative time from the start of the parent task
-	childOf                *ExternalID             // ID of the parent task (if any)
-	linkedTo               []*ExternalID           // IDs of linked spans (for producer/consumer relationships)
-	events                 []Event                 // Events associated with the task
-	conditionalDefinitions []ConditionalDefinition // Conditional definitions for the task
+	delay                  Delay                      // Relative time from the start of the parent task
+	duration               Duration                   // Relative time from the start of the parent task
+	childOf                *ExternalID                // ID of the parent task (if any)
+	linkedTo               []*ExternalID              // IDs of linked spans (for producer/consumer relationships)
+	events                 []Event                    // Events associated with the task
+	conditionalDefinitions []ConditionalDefinition    // Conditional definitions for the task
+	variables              map[string]attribute.Value // Variables bound to the task and its descendants (e.g. repeat index)
+	schedule               Schedule                   // Schedule of the children of the task
+	errorPropagation       ErrorPropagation           // How the failure of the task propagates to its ancestors
+	retry                  *Retry                     // How the task is attempted again when it fails (if any)
+	timeout                *Timeout                   // Deadline of the task (if any)
+	dataQuality            DataQuality                // Flaws of the data the spans of the task are delivered with
+}
+
+// DefinitionOptions holds the optional settings of a task definition. The zero value of each setting leaves it out.
+type DefinitionOptions struct {
+	Variables        map[string]attribute.Value // Variables bound to the task and its descendants (e.g. repeat index)
+	Schedule         Schedule                   // Schedule of the children of the task
+	ErrorPropagation ErrorPropagation           // How the failure of the task propagates to its ancestors
+	Retry            *Retry                     // How the task is attempted again when it fails (if any)
+	Timeout          *Timeout                   // Deadline of the task (if any)
+	DataQuality      DataQuality                // Flaws of the data the spans of the task are delivered with
 }
 
 // NewDefinition creates a new task definition
-func NewDefinition(name string, isResourceEntryPoint bool, resource Resource, attributes map[string]string, kind Kind, externalID *ExternalID, delay Delay, duration Duration, childOf *ExternalID, linkedTo []*ExternalID, events []Event, conditionalDefinitions []ConditionalDefinition) Definition {
+func NewDefinition(name string, isResourceEntryPoint bool, resource Resource, attributes map[string]attribute.Expression, kind Kind, externalID *ExternalID, delay Delay, duration Duration, childOf *ExternalID, linkedTo []*ExternalID, events []Event, conditionalDefinitions []ConditionalDefinition, options DefinitionOptions) Definition {
 	return Definition{
 		name:                   name,
 		isResourceEntryPoint:   isResourceEntryPoint,
@@ -31,6 +49,12 @@ func NewDefinition(name string, isResourceEntryPoint bool, resource Resource, at
 		linkedTo:               linkedTo,
 		events:                 events,
 		conditionalDefinitions: conditionalDefinitions,
+		variables:              options.Variables,
+		schedule:               options.Schedule,
+		errorPropagation:       options.ErrorPropagation,
+		retry:                  options.Retry,
+		timeout:                options.Timeout,
+		dataQuality:            options.DataQuality,
 	}
 }
 
@@ -42,7 +66,7 @@ func (d *Definition) Resource() Resource {
 	return d.resource
 }
 
-func (d *Definition) Attributes() map[string]string {
+func (d *Definition) Attributes() map[string]attribute.Expression {
 	return d.attributes
 }
 
@@ -66,6 +90,27 @@ func (d *Definition) Duration() Duration {
 	return d.duration
 }
 
+// RemoveLinksTo removes the links to the given external IDs, e.g. the ones of the tasks that are not included
+func (d *Definition) RemoveLinksTo(externalIDs map[ExternalID]struct{}) {
+	if d.linkedTo == nil {
+		return
+	}
+	linkedTo := make([]*ExternalID, 0, len(d.linkedTo))
+	for _, id := range d.linkedTo {
+		if _, removed := externalIDs[*id]; !removed {
+			linkedTo = append(linkedTo, id)
+		}
+	}
+	d.linkedTo = linkedTo
+}
+
+// AddConditionalDefinition adds a conditional definition evaluated after the existing ones
+func (d *Definition) AddConditionalDefinition(conditionalDefinition ConditionalDefinition) {
+	conditionalDefinitions := make([]ConditionalDefinition, 0, len(d.conditionalDefinitions)+1)
+	conditionalDefinitions = append(conditionalDefinitions, d.conditionalDefinitions...)
+	d.conditionalDefinitions = append(conditionalDefinitions, conditionalDefinition)
+}
+
 func (d *Definition) ChildOf() *ExternalID {
 	return d.childOf
 }
@@ -81,3 +126,27 @@ func (d *Definition) Events() []Event {
 func (d *Definition) ConditionalDefinitions() []ConditionalDefinition {
 	return d.conditionalDefinitions
 }
+
+func (d *Definition) Variables() map[string]attribute.Value {
+	return d.variables
+}
+
+func (d *Definition) Schedule() Schedule {
+	return d.schedule
+}
+
+func (d *Definition) ErrorPropagation() ErrorPropagation {
+	return d.errorPropagation
+}
+
+func (d *Definition) Retry() *Retry {
+	return d.retry
+}
+
+func (d *Definition) Timeout() *Timeout {
+	return d.timeout
+}
+
+func (d *Definition) DataQuality() DataQuality {
+	return d.dataQuality
+}
@@ -35,7 +35,20 @@ func (d Delay) Resolve(context interface{}) (*time.Duration, error) {
 			return nil, fmt.Errorf("duration cannot be negative, got %s", delay)
 		}
 		return delay, nil
-	case *taskduration.AbsoluteDuration:
+	case *taskduration.OffsetDuration:
+		offset := d.expr.(*taskduration.OffsetDuration)
+		delay, err := Delay{expr: offset.Inner()}.Resolve(context)
+		if err != nil {
+			return nil, err
+		}
+		r := *delay + offset.Offset()
+		return &r, nil
+	case *taskduration.AbsoluteDuration,
+		*taskduration.NormalDuration,
+		*taskduration.LogNormalDuration,
+		*taskduration.ExponentialDuration,
+		*taskduration.UniformDuration,
+		*taskduration.EmpiricalDuration:
 		delay, err := d.expr.Resolve(nil)
 		if err != nil {
 			return nil, fmt.Errorf("failed to resolve delay: %w", err)
@@ -48,3 +61,8 @@ func (d Delay) Resolve(context interface{}) (*time.Duration, error) {
 		return nil, fmt.Errorf("unsupported delay type: %T", d.expr)
 	}
 }
+
+// WithOffset returns a delay that is longer than the delay by the offset
+func (d Delay) WithOffset(offset time.Duration) Delay {
+	return Delay{expr: taskduration.NewOffsetDuration(d.expr, offset)}
+}
@@ -35,7 +35,23 @@ func (d Duration) Resolve(context interface{}) (*time.Duration, error) {
 			return nil, fmt.Errorf("duration must be greater than 0, got %s", duration)
 		}
 		return duration, nil
-	case *taskduration.AbsoluteDuration:
+	case *taskduration.ScaledDuration:
+		scaled := d.expr.(*taskduration.ScaledDuration)
+		duration, err := Duration{expr: scaled.Inner()}.Resolve(context)
+		if err != nil {
+			return nil, err
+		}
+		r := time.Duration(float64(*duration) * scaled.Factor())
+		if r <= 0 {
+			return nil, fmt.Errorf("duration must be greater than 0, got %s", r)
+		}
+		return &r, nil
+	case *taskduration.AbsoluteDuration,
+		*taskduration.NormalDuration,
+		*taskduration.LogNormalDuration,
+		*taskduration.ExponentialDuration,
+		*taskduration.UniformDuration,
+		*taskduration.EmpiricalDuration:
 		duration, err := d.expr.Resolve(nil)
 		if err != nil {
 			return nil, fmt.Errorf("failed to resolve duration: %w", err)
@@ -48,3 +64,8 @@ func (d Duration) Resolve(context interface{}) (*time.Duration, error) {
 		return nil, fmt.Errorf("unsupported duration type: %T", d.expr)
 	}
 }
+
+// Scaled returns a duration that is the duration multiplied by the factor
+func (d Duration) Scaled(factor float64) Duration {
+	return Duration{expr: taskduration.NewScaledDuration(d.expr, factor)}
+}
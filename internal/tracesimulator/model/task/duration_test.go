@@ -92,4 +92,16 @@ func TestDuration_Resolve(t *testing.T) {
 		assert.Nil(t, result)
 		assert.Contains(t, err.Error(), "duration must be greater than 0")
 	})
+
+	t.Run("resolve scaled duration", func(t *testing.T) {
+		baseDuration := 10 * time.Second
+		expr, _ := taskduration.NewRelativeDuration(0.5)
+		duration, _ := task.NewDuration(expr)
+
+		result, err := duration.Scaled(3).Resolve(&baseDuration)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, result)
+		assert.Equal(t, 15*time.Second, *result)
+	})
 }
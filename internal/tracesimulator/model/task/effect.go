@@ -7,6 +7,7 @@ const (
 	EffectKindMarkAsFailed EffectKind = "markAsFailed"
 	EffectKindRecordEvent  EffectKind = "recordEvent"
 	EffectKindAnnotate     EffectKind = "annotate"
+	EffectKindSlowDown     EffectKind = "slowDown"
 )
 
 type Effect struct {
@@ -14,6 +15,7 @@ type Effect struct {
 	markAsFailed *MarkAsFailedEffect
 	recordEvent  *RecordEventEffect
 	annotate     *AnnotateEffect
+	slowDown     *SlowDownEffect
 }
 
 func FromMarkAsFailedEffect(markAsFailed MarkAsFailedEffect) Effect {
@@ -37,6 +39,13 @@ func FromAnnotateEffect(annotate AnnotateEffect) Effect {
 	}
 }
 
+func FromSlowDownEffect(slowDown SlowDownEffect) Effect {
+	return Effect{
+		kind:     EffectKindSlowDown,
+		slowDown: &slowDown,
+	}
+}
+
 func (e *Effect) Kind() EffectKind {
 	return e.kind
 }
@@ -52,3 +61,7 @@ func (e *Effect) RecordEventEffect() *RecordEventEffect {
 func (e *Effect) AnnotateEffect() *AnnotateEffect {
 	return e.annotate
 }
+
+func (e *Effect) SlowDownEffect() *SlowDownEffect {
+	return e.slowDown
+}
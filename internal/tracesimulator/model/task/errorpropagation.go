@@ -0,0 +1,47 @@
+package task
+
+// ErrorPropagationMode represents how far the failure of a task propagates to its ancestors
+type ErrorPropagationMode int
+
+const (
+	// ErrorPropagationNone does not propagate the failure
+	ErrorPropagationNone ErrorPropagationMode = iota
+	// ErrorPropagationParent marks the parent task as failed
+	ErrorPropagationParent
+	// ErrorPropagationServer marks the ancestors as failed up to the nearest server task
+	ErrorPropagationServer
+)
+
+func (m ErrorPropagationMode) String() string {
+	switch m {
+	case ErrorPropagationParent:
+		return "parent"
+	case ErrorPropagationServer:
+		return "server"
+	default:
+		return "none"
+	}
+}
+
+// ErrorPropagation represents how the failure of a task propagates to its ancestors.
+// The zero value does not propagate the failure.
+type ErrorPropagation struct {
+	mode            ErrorPropagationMode
+	recordException bool // Whether the ancestors marked as failed record an exception event
+}
+
+// NewErrorPropagation creates a new error propagation
+func NewErrorPropagation(mode ErrorPropagationMode, recordException bool) ErrorPropagation {
+	return ErrorPropagation{
+		mode:            mode,
+		recordException: recordException,
+	}
+}
+
+func (e ErrorPropagation) Mode() ErrorPropagationMode {
+	return e.mode
+}
+
+func (e ErrorPropagation) RecordException() bool {
+	return e.recordException
+}
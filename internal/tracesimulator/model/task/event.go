@@ -1,13 +1,15 @@
 package task
 
+import "github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/attribute"
+
 // Event represents an event associated with a task
 type Event struct {
 	name       string
 	delay      Delay
-	attributes map[string]string
+	attributes map[string]attribute.Expression
 }
 
-func NewEvent(name string, delay Delay, attributes map[string]string) Event {
+func NewEvent(name string, delay Delay, attributes map[string]attribute.Expression) Event {
 	return Event{
 		name:       name,
 		delay:      delay,
@@ -26,6 +28,6 @@ func (e *Event) Delay() Delay {
 }
 
 // Attributes returns the attributes of the event
-func (e *Event) Attributes() map[string]string {
+func (e *Event) Attributes() map[string]attribute.Expression {
 	return e.attributes
 }
@@ -0,0 +1,11 @@
+package task
+
+// NotCondition represents a condition that negates the inner condition.
+type NotCondition struct {
+	inner Condition
+}
+
+// Inner returns the inner condition to be negated.
+func (n *NotCondition) Inner() Condition {
+	return n.inner
+}
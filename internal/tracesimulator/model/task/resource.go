@@ -1,13 +1,17 @@
 package task
 
+import "github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/attribute"
+
 // Resource represents an entity that emits spans
 type Resource struct {
-	name       string            // Name of the resource
-	attributes map[string]string // Attributes of the resource
+	name       string                          // Name of the resource
+	attributes map[string]attribute.Expression // Attributes of the resource
+	loss       float64                         // Probability of all the spans of the resource being lost in a trace
+	clockSkew  ClockSkew                       // How far the clock of the resource is off
 }
 
 // NewResource creates a new Resource with the given name and attributes
-func NewResource(name string, attributes map[string]string) Resource {
+func NewResource(name string, attributes map[string]attribute.Expression) Resource {
 	return Resource{
 		name:       name,
 		attributes: attributes,
@@ -18,6 +22,26 @@ func (r *Resource) Name() string {
 	return r.name
 }
 
-func (r *Resource) Attributes() map[string]string {
+func (r *Resource) Attributes() map[string]attribute.Expression {
 	return r.attributes
 }
+
+// WithLossProbability returns a copy of the Resource whose spans are all lost in a trace with the given probability
+func (r Resource) WithLossProbability(probability float64) Resource {
+	r.loss = probability
+	return r
+}
+
+func (r *Resource) LossProbability() float64 {
+	return r.loss
+}
+
+// WithClockSkew returns a copy of the Resource whose clock is off by the given skew
+func (r Resource) WithClockSkew(skew ClockSkew) Resource {
+	r.clockSkew = skew
+	return r
+}
+
+func (r *Resource) ClockSkew() ClockSkew {
+	return r.clockSkew
+}
@@ -0,0 +1,90 @@
+package task
+
+import (
+	"math"
+	"time"
+)
+
+// BackoffKind represents how the wait time between attempts grows
+type BackoffKind int
+
+const (
+	// BackoffFixed waits the same time before every retry
+	BackoffFixed BackoffKind = iota
+	// BackoffExponential multiplies the wait time by the multiplier for every retry
+	BackoffExponential
+)
+
+func (k BackoffKind) String() string {
+	switch k {
+	case BackoffExponential:
+		return "exponential"
+	default:
+		return "fixed"
+	}
+}
+
+// Backoff represents the wait time between the end of a failed attempt and the start of the next one
+type Backoff struct {
+	kind       BackoffKind
+	initial    time.Duration  // Wait time before the first retry
+	multiplier float64        // Factor the wait time is multiplied by for every retry in exponential mode
+	max        time.Duration  // Upper limit of the wait time in exponential mode. No limit if 0
+	jitter     float64        // Fraction of the wait time that is randomly added or subtracted, between 0 and 1
+	randomness func() float64 // Source of randomness for the jitter
+}
+
+// NewBackoff creates a new backoff
+func NewBackoff(kind BackoffKind, initial time.Duration, multiplier float64, max time.Duration, jitter float64, randomness func() float64) Backoff {
+	return Backoff{
+		kind:       kind,
+		initial:    initial,
+		multiplier: multiplier,
+		max:        max,
+		jitter:     jitter,
+		randomness: randomness,
+	}
+}
+
+// WaitBefore returns the wait time before the given retry, where the first retry is 1
+func (b Backoff) WaitBefore(retry int) time.Duration {
+	wait := float64(b.initial)
+	if b.kind == BackoffExponential {
+		wait *= math.Pow(b.multiplier, float64(retry-1))
+		if b.max > 0 && wait > float64(b.max) {
+			wait = float64(b.max)
+		}
+	}
+	if b.jitter > 0 && b.randomness != nil {
+		wait *= 1 + b.jitter*(2*b.randomness()-1)
+	}
+	return time.Duration(wait)
+}
+
+// Retry represents how a task is attempted again when it fails
+type Retry struct {
+	maxAttempts int
+	backoff     Backoff
+	failure     *Condition // Condition for an attempt to count as failed. If nil, an attempt fails when it is marked as failed
+}
+
+// NewRetry creates a new retry
+func NewRetry(maxAttempts int, backoff Backoff, failure *Condition) Retry {
+	return Retry{
+		maxAttempts: maxAttempts,
+		backoff:     backoff,
+		failure:     failure,
+	}
+}
+
+func (r *Retry) MaxAttempts() int {
+	return r.maxAttempts
+}
+
+func (r *Retry) Backoff() Backoff {
+	return r.backoff
+}
+
+func (r *Retry) Failure() *Condition {
+	return r.failure
+}
@@ -0,0 +1,49 @@
+package task
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestBackoff_WaitBefore(t *testing.T) {
+	testCases := []struct {
+		name     string
+		backoff  Backoff
+		expected []time.Duration
+	}{
+		{
+			name:     "fixed",
+			backoff:  NewBackoff(BackoffFixed, 100*time.Millisecond, 0, 0, 0, nil),
+			expected: []time.Duration{100 * time.Millisecond, 100 * time.Millisecond, 100 * time.Millisecond},
+		},
+		{
+			name:     "exponential",
+			backoff:  NewBackoff(BackoffExponential, 100*time.Millisecond, 2, 0, 0, nil),
+			expected: []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond},
+		},
+		{
+			name:     "exponential up to the max",
+			backoff:  NewBackoff(BackoffExponential, 100*time.Millisecond, 3, 500*time.Millisecond, 0, nil),
+			expected: []time.Duration{100 * time.Millisecond, 300 * time.Millisecond, 500 * time.Millisecond},
+		},
+		{
+			name:     "subtract the jitter",
+			backoff:  NewBackoff(BackoffFixed, 100*time.Millisecond, 0, 0, 0.2, func() float64 { return 0 }),
+			expected: []time.Duration{80 * time.Millisecond},
+		},
+		{
+			name:     "add the jitter",
+			backoff:  NewBackoff(BackoffExponential, 100*time.Millisecond, 2, 0, 0.5, func() float64 { return 1 }),
+			expected: []time.Duration{150 * time.Millisecond, 300 * time.Millisecond},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for i, expected := range tc.expected {
+				assert.Equal(t, expected, tc.backoff.WaitBefore(i+1), i+1)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package task
+
+import "time"
+
+// ScheduleMode represents how the children of a task are scheduled
+type ScheduleMode int
+
+const (
+	// ScheduleParallel starts every child relative to the start of the parent task
+	ScheduleParallel ScheduleMode = iota
+	// ScheduleSequential starts every child relative to the end of the previous sibling
+	ScheduleSequential
+)
+
+func (m ScheduleMode) String() string {
+	switch m {
+	case ScheduleSequential:
+		return "sequential"
+	default:
+		return "parallel"
+	}
+}
+
+// Schedule represents how the children of a task are scheduled.
+// The zero value schedules the children in parallel.
+type Schedule struct {
+	mode ScheduleMode
+	gap  time.Duration // Wait time between the end of a child and the start of the next one in sequential mode
+}
+
+// NewSchedule creates a new schedule
+func NewSchedule(mode ScheduleMode, gap time.Duration) Schedule {
+	return Schedule{
+		mode: mode,
+		gap:  gap,
+	}
+}
+
+func (s Schedule) Mode() ScheduleMode {
+	return s.mode
+}
+
+func (s Schedule) Gap() time.Duration {
+	return s.gap
+}
@@ -0,0 +1,27 @@
+package task
+
+import "time"
+
+// SlowDownEffect represents an effect that makes the task take longer.
+type SlowDownEffect struct {
+	// by is the fixed time added to the duration of the task.
+	by time.Duration
+	// factor is the factor the duration of the task is multiplied by.
+	factor float64
+}
+
+// NewSlowDownEffect creates a new SlowDownEffect that multiplies the duration by the factor and then adds the fixed time.
+func NewSlowDownEffect(by time.Duration, factor float64) SlowDownEffect {
+	return SlowDownEffect{
+		by:     by,
+		factor: factor,
+	}
+}
+
+func (s *SlowDownEffect) By() time.Duration {
+	return s.by
+}
+
+func (s *SlowDownEffect) Factor() float64 {
+	return s.factor
+}
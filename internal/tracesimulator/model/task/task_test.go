@@ -1,6 +1,7 @@
 package task
 
 import (
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/attribute"
 	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task/taskduration"
 	"testing"
 	"time"
@@ -10,8 +11,8 @@ func makeDefaultDefinition(name string) Definition {
 	def := NewDefinition(
 		name,
 		false,
-		NewResource("test_service", make(map[string]string)),
-		make(map[string]string),
+		NewResource("test_service", make(map[string]attribute.Expression)),
+		make(map[string]attribute.Expression),
 		KindInternal,
 		nil,
 		NewAbsoluteDurationDelay(0),
@@ -28,6 +29,7 @@ func makeDefaultDefinition(name string) Definition {
 				make([]Effect, 0),
 			),
 		},
+		DefinitionOptions{},
 	)
 	return def
 }
@@ -0,0 +1,81 @@
+package taskduration
+
+import (
+	"fmt"
+	"time"
+)
+
+var _ Expression = EmpiricalDuration{}
+
+// Percentile represents a point of an empirical distribution, where value is the duration at the given percentile.
+type Percentile struct {
+	percentile float64
+	value      time.Duration
+}
+
+func NewPercentile(percentile float64, value time.Duration) Percentile {
+	return Percentile{percentile: percentile, value: value}
+}
+
+// Percentile returns the percentile between 0 and 100.
+func (p Percentile) Percentile() float64 {
+	return p.percentile
+}
+
+// Value returns the duration at the percentile.
+func (p Percentile) Value() time.Duration {
+	return p.value
+}
+
+// EmpiricalDuration represents a duration sampled from a percentile table.
+// Samples between two percentiles are linearly interpolated, and samples outside the table are clamped to its edges.
+type EmpiricalDuration struct {
+	percentiles []Percentile
+	// randomness is a function that returns a random value between 0 and 1.
+	randomness func() float64
+}
+
+func NewEmpiricalDuration(percentiles []Percentile, randomness func() float64) (*EmpiricalDuration, error) {
+	if len(percentiles) == 0 {
+		return nil, fmt.Errorf("empirical duration requires at least one percentile")
+	}
+	for i, p := range percentiles {
+		if p.percentile < 0 || p.percentile > 100 {
+			return nil, fmt.Errorf("empirical duration percentile must be between 0 and 100, got %f", p.percentile)
+		}
+		if p.value < 0 {
+			return nil, fmt.Errorf("empirical duration value cannot be negative, got %s", p.value)
+		}
+		if i > 0 && p.percentile <= percentiles[i-1].percentile {
+			return nil, fmt.Errorf("empirical duration percentiles must be in strictly increasing order")
+		}
+		if i > 0 && p.value < percentiles[i-1].value {
+			return nil, fmt.Errorf("empirical duration values must be in non-decreasing order")
+		}
+	}
+	if randomness == nil {
+		return nil, fmt.Errorf("empirical duration requires a randomness function")
+	}
+	return &EmpiricalDuration{percentiles: percentiles, randomness: randomness}, nil
+}
+
+func (d EmpiricalDuration) Resolve(_ interface{}) (*time.Duration, error) {
+	r := d.at(d.randomness() * 100)
+	return &r, nil
+}
+
+// at returns the duration at the given percentile.
+func (d EmpiricalDuration) at(percentile float64) time.Duration {
+	first := d.percentiles[0]
+	if percentile <= first.percentile {
+		return first.value
+	}
+	for i := 1; i < len(d.percentiles); i++ {
+		lower, upper := d.percentiles[i-1], d.percentiles[i]
+		if percentile <= upper.percentile {
+			ratio := (percentile - lower.percentile) / (upper.percentile - lower.percentile)
+			return lower.value + time.Duration(float64(upper.value-lower.value)*ratio)
+		}
+	}
+	return d.percentiles[len(d.percentiles)-1].value
+}
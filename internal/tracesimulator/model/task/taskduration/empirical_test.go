@@ -0,0 +1,63 @@
+package taskduration
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEmpiricalDuration_Resolve(t *testing.T) {
+	percentiles := []Percentile{
+		NewPercentile(0, 10*time.Millisecond),
+		NewPercentile(50, 20*time.Millisecond),
+		NewPercentile(90, 100*time.Millisecond),
+	}
+	tests := []struct {
+		name     string
+		random   float64
+		expected time.Duration
+	}{
+		{name: "Lowest percentile", random: 0, expected: 10 * time.Millisecond},
+		{name: "Exact percentile", random: 0.5, expected: 20 * time.Millisecond},
+		{name: "Interpolated between percentiles", random: 0.25, expected: 15 * time.Millisecond},
+		{name: "Interpolated between upper percentiles", random: 0.7, expected: 60 * time.Millisecond},
+		{name: "Clamped above the highest percentile", random: 0.95, expected: 100 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ed, err := NewEmpiricalDuration(percentiles, func() float64 { return tt.random })
+			if err != nil {
+				t.Fatalf("failed to create EmpiricalDuration: %v", err)
+			}
+			result, err := ed.Resolve(nil)
+			if err != nil {
+				t.Errorf("did not expect an error but got: %v", err)
+			}
+			if result == nil || *result != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestNewEmpiricalDuration(t *testing.T) {
+	tests := []struct {
+		name        string
+		percentiles []Percentile
+	}{
+		{name: "Empty table", percentiles: []Percentile{}},
+		{name: "Percentile out of range", percentiles: []Percentile{NewPercentile(101, time.Millisecond)}},
+		{name: "Negative value", percentiles: []Percentile{NewPercentile(50, -time.Millisecond)}},
+		{name: "Unordered percentiles", percentiles: []Percentile{NewPercentile(50, time.Millisecond), NewPercentile(10, 2*time.Millisecond)}},
+		{name: "Decreasing values", percentiles: []Percentile{NewPercentile(10, 2*time.Millisecond), NewPercentile(50, time.Millisecond)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewEmpiricalDuration(tt.percentiles, func() float64 { return 0 })
+			if err == nil {
+				t.Errorf("expected an error but got none")
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package taskduration
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+var _ Expression = ExponentialDuration{}
+
+// ExponentialDuration represents a duration sampled from an exponential distribution with the given mean.
+// Samples shorter than 1ns are clamped to 1ns.
+type ExponentialDuration struct {
+	mean time.Duration
+	// randomness is a function that returns a random value between 0 and 1.
+	randomness func() float64
+}
+
+func NewExponentialDuration(mean time.Duration, randomness func() float64) (*ExponentialDuration, error) {
+	if mean <= 0 {
+		return nil, fmt.Errorf("exponential duration mean must be greater than 0, got %s", mean)
+	}
+	if randomness == nil {
+		return nil, fmt.Errorf("exponential duration requires a randomness function")
+	}
+	return &ExponentialDuration{mean: mean, randomness: randomness}, nil
+}
+
+func (d ExponentialDuration) Resolve(_ interface{}) (*time.Duration, error) {
+	r := max(time.Duration(-float64(d.mean)*math.Log(1-d.randomness())), minSample)
+	return &r, nil
+}
@@ -0,0 +1,37 @@
+package taskduration
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExponentialDuration_Resolve(t *testing.T) {
+	t.Run("Sample the mean", func(t *testing.T) {
+		// 1 - 1/e makes the sample the mean
+		ed, err := NewExponentialDuration(time.Second, func() float64 { return 1 - 1/2.718281828459045 })
+		if err != nil {
+			t.Fatalf("failed to create ExponentialDuration: %v", err)
+		}
+		result, err := ed.Resolve(nil)
+		if err != nil {
+			t.Errorf("did not expect an error but got: %v", err)
+		}
+		if result == nil || (*result-time.Second).Abs() > time.Microsecond {
+			t.Errorf("expected %v, got %v", time.Second, result)
+		}
+	})
+
+	t.Run("Clamp samples truncated to 0", func(t *testing.T) {
+		ed, err := NewExponentialDuration(time.Millisecond, func() float64 { return 0 })
+		if err != nil {
+			t.Fatalf("failed to create ExponentialDuration: %v", err)
+		}
+		result, err := ed.Resolve(nil)
+		if err != nil {
+			t.Errorf("did not expect an error but got: %v", err)
+		}
+		if result == nil || *result != time.Nanosecond {
+			t.Errorf("expected %v, got %v", time.Nanosecond, result)
+		}
+	})
+}
@@ -0,0 +1,37 @@
+package taskduration
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+var _ Expression = LogNormalDuration{}
+
+// LogNormalDuration represents a duration sampled from a log-normal distribution,
+// which is parameterized by its median and the standard deviation of the underlying normal distribution.
+// Samples shorter than 1ns are clamped to 1ns.
+type LogNormalDuration struct {
+	median time.Duration
+	sigma  float64
+	// randomness is a function that returns a random value between 0 and 1.
+	randomness func() float64
+}
+
+func NewLogNormalDuration(median time.Duration, sigma float64, randomness func() float64) (*LogNormalDuration, error) {
+	if median <= 0 {
+		return nil, fmt.Errorf("log-normal duration median must be greater than 0, got %s", median)
+	}
+	if sigma < 0 {
+		return nil, fmt.Errorf("log-normal duration sigma cannot be negative, got %f", sigma)
+	}
+	if randomness == nil {
+		return nil, fmt.Errorf("log-normal duration requires a randomness function")
+	}
+	return &LogNormalDuration{median: median, sigma: sigma, randomness: randomness}, nil
+}
+
+func (d LogNormalDuration) Resolve(_ interface{}) (*time.Duration, error) {
+	r := max(time.Duration(float64(d.median)*math.Exp(d.sigma*standardNormal(d.randomness))), minSample)
+	return &r, nil
+}
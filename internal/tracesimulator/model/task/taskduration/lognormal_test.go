@@ -0,0 +1,50 @@
+package taskduration
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLogNormalDuration_Resolve(t *testing.T) {
+	t.Run("Sample the median", func(t *testing.T) {
+		// u1 = 1 - 0 and u2 = 0.25 make the standard normal sample 0
+		values := []float64{0, 0.25}
+		index := 0
+		ld, err := NewLogNormalDuration(100*time.Millisecond, 1, func() float64 {
+			v := values[index%len(values)]
+			index++
+			return v
+		})
+		if err != nil {
+			t.Fatalf("failed to create LogNormalDuration: %v", err)
+		}
+		result, err := ld.Resolve(nil)
+		if err != nil {
+			t.Errorf("did not expect an error but got: %v", err)
+		}
+		if result == nil || *result != 100*time.Millisecond {
+			t.Errorf("expected %v, got %v", 100*time.Millisecond, result)
+		}
+	})
+
+	t.Run("Clamp samples truncated to 0", func(t *testing.T) {
+		// u1 = 1 - 0.99 and u2 = 0.5 give a large negative standard normal sample
+		values := []float64{0.99, 0.5}
+		index := 0
+		ld, err := NewLogNormalDuration(time.Nanosecond, 10, func() float64 {
+			v := values[index%len(values)]
+			index++
+			return v
+		})
+		if err != nil {
+			t.Fatalf("failed to create LogNormalDuration: %v", err)
+		}
+		result, err := ld.Resolve(nil)
+		if err != nil {
+			t.Errorf("did not expect an error but got: %v", err)
+		}
+		if result == nil || *result != time.Nanosecond {
+			t.Errorf("expected %v, got %v", time.Nanosecond, result)
+		}
+	})
+}
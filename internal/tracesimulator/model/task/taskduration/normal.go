@@ -0,0 +1,88 @@
+package taskduration
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+var _ Expression = NormalDuration{}
+
+// maxNormalDurationAttempts is the maximum number of draws before falling back to the mean.
+const maxNormalDurationAttempts = 10
+
+// maxNormalDurationFallbackProbability is the highest accepted probability of falling back to the mean,
+// which is the probability that all the draws are non-positive.
+const maxNormalDurationFallbackProbability = 1e-6
+
+// NormalDuration represents a duration sampled from a normal distribution.
+// The distribution is either truncated at zero, where non-positive samples are drawn again,
+// or clamped at zero, where negative samples become 0.
+type NormalDuration struct {
+	mean   time.Duration
+	stddev time.Duration
+	// clamped is whether negative samples become 0 instead of being drawn again.
+	clamped bool
+	// randomness is a function that returns a random value between 0 and 1.
+	randomness func() float64
+}
+
+// NewNormalDuration creates a normal distribution truncated at zero, whose samples are always greater than 0.
+// The mean must be large enough for its stddev that the draws rarely run out, so that the distribution keeps its shape.
+func NewNormalDuration(mean, stddev time.Duration, randomness func() float64) (*NormalDuration, error) {
+	d, err := newNormalDuration(mean, stddev, false, randomness)
+	if err != nil {
+		return nil, err
+	}
+	if NormalDurationRunsOut(mean, stddev) {
+		return nil, fmt.Errorf("normal duration mean %s is too small for stddev %s, which makes non-positive samples too likely", mean, stddev)
+	}
+	return d, nil
+}
+
+// NewClampedNormalDuration creates a normal distribution clamped at zero, whose samples can be 0 as delays can.
+func NewClampedNormalDuration(mean, stddev time.Duration, randomness func() float64) (*NormalDuration, error) {
+	return newNormalDuration(mean, stddev, true, randomness)
+}
+
+func newNormalDuration(mean, stddev time.Duration, clamped bool, randomness func() float64) (*NormalDuration, error) {
+	if mean < 0 {
+		return nil, fmt.Errorf("normal duration mean cannot be negative, got %s", mean)
+	}
+	if stddev < 0 {
+		return nil, fmt.Errorf("normal duration stddev cannot be negative, got %s", stddev)
+	}
+	if randomness == nil {
+		return nil, fmt.Errorf("normal duration requires a randomness function")
+	}
+	return &NormalDuration{mean: mean, stddev: stddev, clamped: clamped, randomness: randomness}, nil
+}
+
+// NormalDurationRunsOut reports whether a normal distribution truncated at zero with the mean and stddev
+// is likely to run out of draws and fall back to the mean.
+func NormalDurationRunsOut(mean, stddev time.Duration) bool {
+	if mean <= 0 {
+		return true
+	}
+	if stddev == 0 {
+		return false
+	}
+	// the probability that a sample is non-positive
+	p := 0.5 * math.Erfc(float64(mean)/float64(stddev)/math.Sqrt2)
+	return math.Pow(p, maxNormalDurationAttempts) > maxNormalDurationFallbackProbability
+}
+
+func (d NormalDuration) Resolve(_ interface{}) (*time.Duration, error) {
+	if d.clamped {
+		r := max(time.Duration(float64(d.mean)+float64(d.stddev)*standardNormal(d.randomness)), 0)
+		return &r, nil
+	}
+	for i := 0; i < maxNormalDurationAttempts; i++ {
+		r := time.Duration(float64(d.mean) + float64(d.stddev)*standardNormal(d.randomness))
+		if r > 0 {
+			return &r, nil
+		}
+	}
+	r := d.mean
+	return &r, nil
+}
@@ -0,0 +1,101 @@
+package taskduration
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNormalDuration_Resolve(t *testing.T) {
+	t.Run("Sample around the mean", func(t *testing.T) {
+		// u1 = 1 - 0 and u2 = 0.25 make the standard normal sample 0
+		values := []float64{0, 0.25}
+		index := 0
+		nd, err := NewNormalDuration(100*time.Millisecond, 10*time.Millisecond, func() float64 {
+			v := values[index%len(values)]
+			index++
+			return v
+		})
+		if err != nil {
+			t.Fatalf("failed to create NormalDuration: %v", err)
+		}
+		result, err := nd.Resolve(nil)
+		if err != nil {
+			t.Errorf("did not expect an error but got: %v", err)
+		}
+		if result == nil || *result != 100*time.Millisecond {
+			t.Errorf("expected %v, got %v", 100*time.Millisecond, result)
+		}
+	})
+
+	t.Run("Redraw non-positive samples", func(t *testing.T) {
+		// the first pair of values gives a large negative sample and the second pair gives the mean
+		values := []float64{0.99, 0.5, 0, 0.25}
+		index := 0
+		nd, err := NewNormalDuration(time.Second, time.Second, func() float64 {
+			v := values[index%len(values)]
+			index++
+			return v
+		})
+		if err != nil {
+			t.Fatalf("failed to create NormalDuration: %v", err)
+		}
+		result, err := nd.Resolve(nil)
+		if err != nil {
+			t.Errorf("did not expect an error but got: %v", err)
+		}
+		if result == nil || *result != time.Second {
+			t.Errorf("expected %v, got %v", time.Second, result)
+		}
+	})
+
+	t.Run("Clamp negative samples to zero", func(t *testing.T) {
+		// the values give a large negative sample
+		values := []float64{0.99, 0.5}
+		index := 0
+		nd, err := NewClampedNormalDuration(0, time.Second, func() float64 {
+			v := values[index%len(values)]
+			index++
+			return v
+		})
+		if err != nil {
+			t.Fatalf("failed to create NormalDuration: %v", err)
+		}
+		result, err := nd.Resolve(nil)
+		if err != nil {
+			t.Errorf("did not expect an error but got: %v", err)
+		}
+		if result == nil || *result != 0 {
+			t.Errorf("expected %v, got %v", time.Duration(0), result)
+		}
+		if index != 2 {
+			t.Errorf("expected a single draw, got %d values", index)
+		}
+	})
+
+	t.Run("Reject a mean too small for the stddev", func(t *testing.T) {
+		if _, err := NewNormalDuration(time.Millisecond, time.Second, func() float64 { return 0 }); err == nil {
+			t.Errorf("expected an error for a mean too small for the stddev but got none")
+		}
+		if _, err := NewNormalDuration(0, 0, func() float64 { return 0 }); err == nil {
+			t.Errorf("expected an error for a zero mean but got none")
+		}
+		if _, err := NewNormalDuration(700*time.Millisecond, time.Second, func() float64 { return 0 }); err != nil {
+			t.Errorf("did not expect an error but got: %v", err)
+		}
+		if _, err := NewClampedNormalDuration(0, time.Second, func() float64 { return 0 }); err != nil {
+			t.Errorf("did not expect an error but got: %v", err)
+		}
+	})
+
+	t.Run("Invalid parameters", func(t *testing.T) {
+		if _, err := NewNormalDuration(-time.Millisecond, time.Millisecond, func() float64 { return 0 }); err == nil {
+			t.Errorf("expected an error for negative mean but got none")
+		}
+		if _, err := NewNormalDuration(time.Millisecond, -time.Millisecond, func() float64 { return 0 }); err == nil {
+			t.Errorf("expected an error for negative stddev but got none")
+		}
+		if _, err := NewNormalDuration(time.Millisecond, time.Millisecond, nil); err == nil {
+			t.Errorf("expected an error for nil randomness but got none")
+		}
+	})
+}
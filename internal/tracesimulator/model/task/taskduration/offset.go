@@ -0,0 +1,37 @@
+package taskduration
+
+import (
+	"fmt"
+	"time"
+)
+
+var _ Expression = OffsetDuration{}
+
+// OffsetDuration represents a duration that is longer than the inner duration by a fixed offset.
+type OffsetDuration struct {
+	inner  Expression
+	offset time.Duration
+}
+
+func NewOffsetDuration(inner Expression, offset time.Duration) *OffsetDuration {
+	return &OffsetDuration{inner: inner, offset: offset}
+}
+
+// Inner returns the duration expression that the offset is added to
+func (d OffsetDuration) Inner() Expression {
+	return d.inner
+}
+
+// Offset returns the offset added to the inner duration
+func (d OffsetDuration) Offset() time.Duration {
+	return d.offset
+}
+
+func (d OffsetDuration) Resolve(context interface{}) (*time.Duration, error) {
+	base, err := d.inner.Resolve(context)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve offset duration: %w", err)
+	}
+	r := *base + d.offset
+	return &r, nil
+}
@@ -0,0 +1,17 @@
+package taskduration
+
+import (
+	"math"
+	"time"
+)
+
+// minSample is the shortest duration sampled from the exponential and log-normal distributions.
+// Their samples can be truncated to 0, which is not a valid span duration.
+const minSample = time.Nanosecond
+
+// standardNormal returns a sample of the standard normal distribution using the Box-Muller transform.
+func standardNormal(randomness func() float64) float64 {
+	u1 := 1 - randomness()
+	u2 := randomness()
+	return math.Sqrt(-2*math.Log(u1)) * math.Cos(2*math.Pi*u2)
+}
@@ -0,0 +1,37 @@
+package taskduration
+
+import (
+	"fmt"
+	"time"
+)
+
+var _ Expression = ScaledDuration{}
+
+// ScaledDuration represents a duration that is the inner duration multiplied by a factor.
+type ScaledDuration struct {
+	inner  Expression
+	factor float64
+}
+
+func NewScaledDuration(inner Expression, factor float64) *ScaledDuration {
+	return &ScaledDuration{inner: inner, factor: factor}
+}
+
+// Inner returns the duration expression that is scaled
+func (d ScaledDuration) Inner() Expression {
+	return d.inner
+}
+
+// Factor returns the factor the inner duration is multiplied by
+func (d ScaledDuration) Factor() float64 {
+	return d.factor
+}
+
+func (d ScaledDuration) Resolve(context interface{}) (*time.Duration, error) {
+	base, err := d.inner.Resolve(context)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve scaled duration: %w", err)
+	}
+	r := time.Duration(float64(*base) * d.factor)
+	return &r, nil
+}
@@ -0,0 +1,34 @@
+package taskduration
+
+import (
+	"fmt"
+	"time"
+)
+
+var _ Expression = UniformDuration{}
+
+// UniformDuration represents a duration sampled uniformly between min and max.
+type UniformDuration struct {
+	minimum time.Duration
+	maximum time.Duration
+	// randomness is a function that returns a random value between 0 and 1.
+	randomness func() float64
+}
+
+func NewUniformDuration(minimum, maximum time.Duration, randomness func() float64) (*UniformDuration, error) {
+	if minimum < 0 {
+		return nil, fmt.Errorf("uniform duration min cannot be negative, got %s", minimum)
+	}
+	if maximum < minimum {
+		return nil, fmt.Errorf("uniform duration max must be greater than or equal to min, got min %s and max %s", minimum, maximum)
+	}
+	if randomness == nil {
+		return nil, fmt.Errorf("uniform duration requires a randomness function")
+	}
+	return &UniformDuration{minimum: minimum, maximum: maximum, randomness: randomness}, nil
+}
+
+func (d UniformDuration) Resolve(_ interface{}) (*time.Duration, error) {
+	r := d.minimum + time.Duration(float64(d.maximum-d.minimum)*d.randomness())
+	return &r, nil
+}
@@ -0,0 +1,44 @@
+package task
+
+import "time"
+
+// TimeoutOverrun represents what happens to the descendants running past the deadline
+type TimeoutOverrun int
+
+const (
+	// TimeoutTruncate ends the descendants at the deadline and drops the ones that would start after it
+	TimeoutTruncate TimeoutOverrun = iota
+	// TimeoutDangle leaves the descendants running past the deadline as they are
+	TimeoutDangle
+)
+
+func (o TimeoutOverrun) String() string {
+	switch o {
+	case TimeoutDangle:
+		return "dangle"
+	default:
+		return "truncate"
+	}
+}
+
+// Timeout represents the deadline of a task, relative to its start
+type Timeout struct {
+	after   time.Duration
+	overrun TimeoutOverrun
+}
+
+// NewTimeout creates a new timeout
+func NewTimeout(after time.Duration, overrun TimeoutOverrun) Timeout {
+	return Timeout{
+		after:   after,
+		overrun: overrun,
+	}
+}
+
+func (t *Timeout) After() time.Duration {
+	return t.after
+}
+
+func (t *Timeout) Overrun() TimeoutOverrun {
+	return t.overrun
+}
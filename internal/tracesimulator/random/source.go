@@ -0,0 +1,37 @@
+package random
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"sync"
+)
+
+// Source is a seeded source of pseudo-random numbers, which is safe for concurrent use.
+// The same seed yields the same sequence of numbers, as long as they are drawn in the same order.
+type Source struct {
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// NewSource creates a new Source seeded with the given value.
+func NewSource(seed int64) *Source {
+	return &Source{rnd: rand.New(rand.NewSource(seed))}
+}
+
+// Float64 returns a pseudo-random number in [0.0, 1.0).
+func (s *Source) Float64() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rnd.Float64()
+}
+
+// Read fills p with pseudo-random bytes.
+func (s *Source) Read(p []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var buf [8]byte
+	for i := 0; i < len(p); i += len(buf) {
+		binary.BigEndian.PutUint64(buf[:], s.rnd.Uint64())
+		copy(p[i:], buf[:])
+	}
+}
@@ -0,0 +1,44 @@
+package random
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSource(t *testing.T) {
+	t.Run("same seed yields same sequence", func(t *testing.T) {
+		a := NewSource(42)
+		b := NewSource(42)
+		for i := 0; i < 10; i++ {
+			assert.Equal(t, a.Float64(), b.Float64())
+		}
+		pa := make([]byte, 13)
+		pb := make([]byte, 13)
+		a.Read(pa)
+		b.Read(pb)
+		assert.Equal(t, pa, pb)
+	})
+
+	t.Run("different seeds yield different sequences", func(t *testing.T) {
+		pa := make([]byte, 16)
+		pb := make([]byte, 16)
+		NewSource(1).Read(pa)
+		NewSource(2).Read(pb)
+		assert.NotEqual(t, pa, pb)
+	})
+
+	t.Run("read fills all bytes", func(t *testing.T) {
+		p := make([]byte, 20)
+		NewSource(42).Read(p)
+		assert.NotEqual(t, make([]byte, 4), p[16:])
+	})
+
+	t.Run("float64 is in [0, 1)", func(t *testing.T) {
+		s := NewSource(42)
+		for i := 0; i < 1000; i++ {
+			f := s.Float64()
+			assert.GreaterOrEqual(t, f, 0.0)
+			assert.Less(t, f, 1.0)
+		}
+	})
+}
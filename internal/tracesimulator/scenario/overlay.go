@@ -0,0 +1,62 @@
+package scenario
+
+import "github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
+
+// Overlay represents a change to the spans of a service while a scenario is active
+type Overlay struct {
+	service           string
+	errorRate         float64
+	errorMessage      string
+	latencyMultiplier float64
+	randomness        func() float64
+}
+
+// NewOverlay creates a new overlay.
+// The spans of the service are marked as failed with the probability of errorRate, and their durations are multiplied by latencyMultiplier,
+// which must be at least 1. The spans are slowed down like the slow_down effect, so that their parents and later siblings follow them.
+func NewOverlay(service string, errorRate float64, errorMessage string, latencyMultiplier float64, randomness func() float64) Overlay {
+	return Overlay{
+		service:           service,
+		errorRate:         errorRate,
+		errorMessage:      errorMessage,
+		latencyMultiplier: latencyMultiplier,
+		randomness:        randomness,
+	}
+}
+
+// apply applies the overlay to the task if it belongs to the service
+func (o Overlay) apply(def *task.Definition) {
+	resource := def.Resource()
+	if resource.Name() != o.service {
+		return
+	}
+	if o.latencyMultiplier != 1 {
+		// all of no conditions is always met
+		def.AddConditionalDefinition(task.NewConditionalDefinition(
+			task.NewAllOfCondition(nil),
+			[]task.Effect{task.FromSlowDownEffect(task.NewSlowDownEffect(0, o.latencyMultiplier))},
+		))
+	}
+	if o.errorRate > 0 {
+		def.AddConditionalDefinition(task.NewConditionalDefinition(
+			task.NewProbabilisticCondition(o.errorRate, o.randomness),
+			[]task.Effect{task.FromMarkAsFailedEffect(task.NewMarkAsFailedEffect(o.errorMessage))},
+		))
+	}
+}
+
+func (o Overlay) Service() string {
+	return o.service
+}
+
+func (o Overlay) ErrorRate() float64 {
+	return o.errorRate
+}
+
+func (o Overlay) ErrorMessage() string {
+	return o.errorMessage
+}
+
+func (o Overlay) LatencyMultiplier() float64 {
+	return o.latencyMultiplier
+}
@@ -0,0 +1,94 @@
+package scenario
+
+import (
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/blueprint"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/attribute"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
+	"time"
+)
+
+// Scenario represents an incident that changes the spans of services during a window, e.g. an outage of a service
+type Scenario struct {
+	name     string
+	window   Window
+	overlays []Overlay
+}
+
+// NewScenario creates a new scenario
+func NewScenario(name string, window Window, overlays []Overlay) Scenario {
+	return Scenario{
+		name:     name,
+		window:   window,
+		overlays: overlays,
+	}
+}
+
+func (s Scenario) Name() string {
+	return s.name
+}
+
+func (s Scenario) Window() Window {
+	return s.window
+}
+
+func (s Scenario) Overlays() []Overlay {
+	return s.overlays
+}
+
+// Timeline represents the scenarios that are applied to a blueprint while the generation runs
+type Timeline struct {
+	scenarios []Scenario
+}
+
+// NewTimeline creates a new timeline
+func NewTimeline(scenarios []Scenario) *Timeline {
+	return &Timeline{scenarios: scenarios}
+}
+
+// Apply returns the blueprint with the overlays of the scenarios active at the time applied
+func (t *Timeline) Apply(bp blueprint.Blueprint, elapsed time.Duration, now time.Time) blueprint.Blueprint {
+	var overlays []Overlay
+	for _, s := range t.scenarios {
+		if s.window.Contains(elapsed, now) {
+			overlays = append(overlays, s.overlays...)
+		}
+	}
+	if len(overlays) == 0 {
+		return bp
+	}
+	return &overlaidBlueprint{base: bp, overlays: overlays}
+}
+
+// overlaidBlueprint is a blueprint whose task trees have overlays applied
+type overlaidBlueprint struct {
+	base     blueprint.Blueprint
+	overlays []Overlay
+}
+
+func (b *overlaidBlueprint) Interpret() ([]*task.TreeNode, error) {
+	roots, err := b.base.Interpret()
+	if err != nil {
+		return nil, err
+	}
+	var apply func(node *task.TreeNode)
+	apply = func(node *task.TreeNode) {
+		for _, o := range b.overlays {
+			o.apply(node.Definition())
+		}
+		for _, child := range node.Children() {
+			apply(child)
+		}
+	}
+	for _, root := range roots {
+		apply(root)
+	}
+	return roots, nil
+}
+
+func (b *overlaidBlueprint) Variables() map[string]attribute.Expression {
+	return b.base.Variables()
+}
+
+func (b *overlaidBlueprint) DedicatedFlows() []blueprint.DedicatedFlow {
+	return b.base.DedicatedFlows()
+}
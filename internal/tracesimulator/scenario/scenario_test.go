@@ -0,0 +1,103 @@
+package scenario
+
+import (
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/blueprint/service"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/blueprint/service/model"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/span"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task/taskduration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestWindow_Contains(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	end := start.Add(5 * time.Minute)
+	to := 15 * time.Minute
+
+	testCases := []struct {
+		name     string
+		window   Window
+		elapsed  time.Duration
+		now      time.Time
+		expected bool
+	}{
+		{name: "before elapsed window", window: NewElapsedWindow(10*time.Minute, &to), elapsed: 9 * time.Minute, expected: false},
+		{name: "at start of elapsed window", window: NewElapsedWindow(10*time.Minute, &to), elapsed: 10 * time.Minute, expected: true},
+		{name: "at end of elapsed window", window: NewElapsedWindow(10*time.Minute, &to), elapsed: 15 * time.Minute, expected: false},
+		{name: "after start of open elapsed window", window: NewElapsedWindow(10*time.Minute, nil), elapsed: 100 * time.Hour, expected: true},
+		{name: "before wall clock window", window: NewWallClockWindow(start, &end), now: start.Add(-time.Second), expected: false},
+		{name: "within wall clock window", window: NewWallClockWindow(start, &end), now: start.Add(time.Minute), expected: true},
+		{name: "after wall clock window", window: NewWallClockWindow(start, &end), now: end, expected: false},
+		{name: "after start of open wall clock window", window: NewWallClockWindow(start, nil), now: end, expected: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.window.Contains(tc.elapsed, tc.now))
+		})
+	}
+}
+
+func TestTimeline_Apply(t *testing.T) {
+	newTask := func(name string) model.Task {
+		delay, _ := taskduration.NewAbsoluteDuration(0)
+		d, _ := task.NewDelay(delay)
+		duration, _ := taskduration.NewAbsoluteDuration(100 * time.Millisecond)
+		du, _ := task.NewDuration(duration)
+		return model.Task{Name: name, Kind: "server", Delay: *d, Duration: *du}
+	}
+	getProfileID, _ := task.NewExternalID("get_profile")
+	frontend := newTask("get_profile")
+	frontend.ExternalID = getProfileID
+	frontend.Children = []model.Task{newTask("verify_token")}
+	login := newTask("login")
+	login.ChildOf = getProfileID
+	bp := service.NewServiceBlueprint([]model.Service{
+		{Name: "frontend", Tasks: []model.Task{frontend}},
+		{Name: "auth_service", Tasks: []model.Task{login}},
+	}, nil, nil)
+	to := 15 * time.Minute
+	timeline := NewTimeline([]Scenario{
+		NewScenario("auth outage", NewElapsedWindow(10*time.Minute, &to), []Overlay{
+			NewOverlay("auth_service", 0.6, "auth outage", 3, func() float64 { return 0.5 }),
+		}),
+	})
+
+	t.Run("return the blueprint as it is when no scenario is active", func(t *testing.T) {
+		assert.Same(t, &bp, timeline.Apply(&bp, 5*time.Minute, time.Now()))
+	})
+
+	t.Run("apply the overlays of the active scenarios to the spans of the service", func(t *testing.T) {
+		roots, err := timeline.Apply(&bp, 12*time.Minute, time.Now()).Interpret()
+		assert.NoError(t, err)
+		require.Len(t, roots, 1)
+		require.Len(t, roots[0].Children(), 2)
+
+		for _, node := range []*task.TreeNode{roots[0], roots[0].Children()[0]} {
+			resource := node.Definition().Resource()
+			assert.Equal(t, "frontend", resource.Name())
+			assert.Empty(t, node.Definition().ConditionalDefinitions())
+		}
+
+		login := roots[0].Children()[1].Definition()
+		resource := login.Resource()
+		assert.Equal(t, "auth_service", resource.Name())
+		conditionalDefinitions := login.ConditionalDefinitions()
+		assert.Len(t, conditionalDefinitions, 2)
+		assert.Equal(t, task.ConditionKindAllOf, conditionalDefinitions[0].Condition().Kind())
+		assert.Equal(t, task.EffectKindSlowDown, conditionalDefinitions[0].Effects()[0].Kind())
+		assert.Equal(t, task.ConditionKindProbabilistic, conditionalDefinitions[1].Condition().Kind())
+
+		start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		root, err := span.FromTaskTree(roots[0], span.NewTraceID([16]byte{0x01}), start, func() span.ID { return span.NewSpanID([8]byte{0x01}) }, nil)
+		require.NoError(t, err)
+		slowed := root.Children()[1]
+		assert.Equal(t, "login", slowed.Name())
+		assert.Equal(t, start.Add(300*time.Millisecond), slowed.EndTime())
+		// the parent still contains the slowed child
+		assert.Equal(t, slowed.EndTime(), root.EndTime())
+	})
+}
@@ -0,0 +1,35 @@
+package scenario
+
+import "time"
+
+// Window represents the period in which a scenario is active.
+// It is given either by the time elapsed since the start of the generation or by the wall clock time.
+type Window struct {
+	from  *time.Duration
+	to    *time.Duration
+	start *time.Time
+	end   *time.Time
+}
+
+// NewElapsedWindow creates a window between the times elapsed since the start of the generation.
+// If to is nil, the window never ends.
+func NewElapsedWindow(from time.Duration, to *time.Duration) Window {
+	return Window{from: &from, to: to}
+}
+
+// NewWallClockWindow creates a window between the wall clock times.
+// If end is nil, the window never ends.
+func NewWallClockWindow(start time.Time, end *time.Time) Window {
+	return Window{start: &start, end: end}
+}
+
+// Contains reports whether the window contains the time, inclusive of the start and exclusive of the end
+func (w Window) Contains(elapsed time.Duration, now time.Time) bool {
+	if w.from != nil {
+		return elapsed >= *w.from && (w.to == nil || elapsed < *w.to)
+	}
+	if w.start != nil {
+		return !now.Before(*w.start) && (w.end == nil || now.Before(*w.end))
+	}
+	return false
+}
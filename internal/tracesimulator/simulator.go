@@ -1,27 +1,63 @@
 package simulator
 
 import (
-	"crypto/rand"
 	"fmt"
 	simulator "github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/adapter"
 	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/blueprint"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/attribute"
 	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/span"
 	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/random"
 	"time"
 )
 
 // Simulator is a struct that simulates traces based on a blueprint and export them to a specific format using an adapter.
 type Simulator[T any] struct {
 	adapter simulator.Adapter[T]
+	// source is the source of all the randomness in the simulation, including trace and span IDs
+	source *random.Source
 }
 
-// New creates a new Simulator instance with the provided adapter.
-func New[T any](adapter simulator.Adapter[T]) *Simulator[T] {
-	return &Simulator[T]{adapter: adapter}
+// New creates a new Simulator instance with the provided adapter and source of randomness.
+func New[T any](adapter simulator.Adapter[T], source *random.Source) *Simulator[T] {
+	return &Simulator[T]{adapter: adapter, source: source}
+}
+
+// Randomness returns the source of randomness of the simulator, which the blueprint should draw from
+// so that the simulation is reproducible with the same seed.
+func (s *Simulator[T]) Randomness() func() float64 {
+	return s.source.Float64
 }
 
 // Run executes the simulation by interpreting the blueprint, generating spans, and transforming them using the adapter.
+// The spans are shifted so that none of them ends after baseEndTime.
 func (s *Simulator[T]) Run(blueprint blueprint.Blueprint, baseEndTime time.Time) (T, error) {
+	return s.run(blueprint, baseEndTime, func(rootSpans []*span.TreeNode) time.Duration {
+		latestEndTime := baseEndTime
+		for _, rootSpan := range rootSpans {
+			latestEndTime = s.findLatestEndTime(rootSpan, latestEndTime)
+		}
+		return baseEndTime.Sub(latestEndTime)
+	})
+}
+
+// RunStartingAt executes the simulation like Run, but the spans are shifted so that the first span starts at baseStartTime.
+// This is for delivering the spans as they end, as real instrumentations do.
+func (s *Simulator[T]) RunStartingAt(blueprint blueprint.Blueprint, baseStartTime time.Time) (T, error) {
+	return s.run(blueprint, baseStartTime, func(rootSpans []*span.TreeNode) time.Duration {
+		if len(rootSpans) == 0 {
+			return 0
+		}
+		earliestStartTime := rootSpans[0].StartTime()
+		for _, rootSpan := range rootSpans {
+			earliestStartTime = s.findEarliestStartTime(rootSpan, earliestStartTime)
+		}
+		return baseStartTime.Sub(earliestStartTime)
+	})
+}
+
+// run executes the simulation, shifting the timestamps of the spans by the duration that adjust returns
+func (s *Simulator[T]) run(blueprint blueprint.Blueprint, baseEndTime time.Time, adjust func(rootSpans []*span.TreeNode) time.Duration) (T, error) {
 	var zero T
 	traceRootTaskNodes, err := blueprint.Interpret()
 	if err != nil {
@@ -32,8 +68,13 @@ func (s *Simulator[T]) Run(blueprint blueprint.Blueprint, baseEndTime time.Time)
 	rootSpans := make([]*span.TreeNode, 0, len(traceRootTaskNodes))
 	externalIDToSpan := make(map[task.ExternalID]*span.TreeNode)
 	for _, taskTree := range traceRootTaskNodes {
-		traceID := generateTraceID()
-		rootSpan, err := span.FromTaskTree(taskTree, traceID, baseEndTime, generateSpanID)
+		traceID := s.generateTraceID()
+		// trace-scoped variables are resolved once per trace and shared by all the spans in the trace
+		variables, err := attribute.ResolveAll(blueprint.Variables(), nil)
+		if err != nil {
+			return zero, fmt.Errorf("failed to resolve trace variables: %w", err)
+		}
+		rootSpan, err := span.FromTaskTree(taskTree, traceID, baseEndTime, s.generateSpanID, attribute.WithScope(attribute.ScopeTrace, variables))
 		if err != nil {
 			return zero, fmt.Errorf("failed to construct span tree: %w", err)
 		}
@@ -47,6 +88,19 @@ func (s *Simulator[T]) Run(blueprint blueprint.Blueprint, baseEndTime time.Time)
 		rootSpans = append(rootSpans, rootSpan)
 	}
 
+	// Remove links to the spans dropped by timeouts
+	truncated := make(map[task.ExternalID]struct{})
+	for _, rootSpan := range rootSpans {
+		for _, externalID := range rootSpan.TruncatedExternalIDs() {
+			truncated[externalID] = struct{}{}
+		}
+	}
+	if len(truncated) > 0 {
+		for _, rootSpan := range rootSpans {
+			rootSpan.RemoveLinksTo(truncated)
+		}
+	}
+
 	// Link spans to their parents based on ExternalID
 	// This must be done after all spans are created since the linked spans may not be created yet
 	for _, rootSpan := range rootSpans {
@@ -56,14 +110,20 @@ func (s *Simulator[T]) Run(blueprint blueprint.Blueprint, baseEndTime time.Time)
 		}
 	}
 
-	// Shift timestamps to ensure all spans end before the current time
-	latestEndTime := baseEndTime
+	// Shift timestamps to align the spans to the base time
+	adjustmentDuration := adjust(rootSpans)
 	for _, rootSpan := range rootSpans {
-		latestEndTime = s.findLatestEndTime(rootSpan, latestEndTime)
+		rootSpan.ShiftTimestamps(adjustmentDuration)
 	}
-	adjustmentDuration := baseEndTime.Sub(latestEndTime)
+
+	// Skew the clocks after the shift, so that the skews are not taken into account when aligning the spans to the base time
 	for _, rootSpan := range rootSpans {
-		rootSpan.ShiftTimestamps(adjustmentDuration)
+		rootSpan.SkewClocks()
+	}
+
+	// Degrade the data quality of the spans as the last step before the adapter, so that the flaws do not affect the simulation
+	for _, rootSpan := range rootSpans {
+		rootSpan.DegradeDataQuality(s.source.Float64, s.generateSpanID)
 	}
 
 	// Convert spans to the desired format using the adapter
@@ -85,14 +145,24 @@ func (s *Simulator[T]) findLatestEndTime(node *span.TreeNode, latestEndTime time
 	return latestEndTime
 }
 
-func generateTraceID() span.TraceID {
+func (s *Simulator[T]) findEarliestStartTime(node *span.TreeNode, earliestStartTime time.Time) time.Time {
+	if node.StartTime().Before(earliestStartTime) {
+		earliestStartTime = node.StartTime()
+	}
+	for _, child := range node.Children() {
+		earliestStartTime = s.findEarliestStartTime(child, earliestStartTime)
+	}
+	return earliestStartTime
+}
+
+func (s *Simulator[T]) generateTraceID() span.TraceID {
 	var id [16]byte
-	_, _ = rand.Read(id[:])
+	s.source.Read(id[:])
 	return span.NewTraceID(id)
 }
 
-func generateSpanID() span.ID {
+func (s *Simulator[T]) generateSpanID() span.ID {
 	var id [8]byte
-	_, _ = rand.Read(id[:])
+	s.source.Read(id[:])
 	return span.NewSpanID(id)
 }
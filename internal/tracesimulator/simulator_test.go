@@ -4,9 +4,11 @@ import (
 	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/adapter"
 	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/blueprint/service"
 	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/blueprint/service/model"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/attribute"
 	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/span"
 	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
 	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task/taskduration"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/random"
 	"github.com/stretchr/testify/assert"
 	mathRand "math/rand"
 	"testing"
@@ -36,8 +38,8 @@ func TestSimulator_Run(t *testing.T) {
 	blueprint := service.NewServiceBlueprint([]model.Service{
 		{
 			Name: "service-a",
-			Resource: map[string]string{
-				"env": "test",
+			Resource: map[string]attribute.Expression{
+				"env": attribute.String("test"),
 			},
 			Tasks: []model.Task{
 				{
@@ -50,20 +52,20 @@ func TestSimulator_Run(t *testing.T) {
 						task.NewEvent(
 							"event-root-task-a-1",
 							NewAbsoluteDurationDelay(0),
-							map[string]string{
-								"attribute-key-event-root-task-a-1": "attribute-value-event-root-task-a-1",
+							map[string]attribute.Expression{
+								"attribute-key-event-root-task-a-1": attribute.String("attribute-value-event-root-task-a-1"),
 							},
 						),
 						task.NewEvent(
 							"event-root-task-a-2",
 							NewAbsoluteDurationDelay(100*time.Millisecond),
-							map[string]string{
-								"attribute-key-event-root-task-a-2": "attribute-value-event-root-task-a-2",
+							map[string]attribute.Expression{
+								"attribute-key-event-root-task-a-2": attribute.String("attribute-value-event-root-task-a-2"),
 							},
 						),
 					},
-					Attributes: map[string]string{
-						"key1": "value1",
+					Attributes: map[string]attribute.Expression{
+						"key1": attribute.String("value1"),
 					},
 					Children: []model.Task{
 						{
@@ -76,8 +78,8 @@ func TestSimulator_Run(t *testing.T) {
 								task.NewEvent(
 									"event-child-task-a1-1",
 									NewAbsoluteDurationDelay(0),
-									map[string]string{
-										"attribute-key-event-child-task-a1-1": "attribute-value-event-child-task-a1-1",
+									map[string]attribute.Expression{
+										"attribute-key-event-child-task-a1-1": attribute.String("attribute-value-event-child-task-a1-1"),
 									},
 								),
 							},
@@ -92,8 +94,8 @@ func TestSimulator_Run(t *testing.T) {
 								task.NewEvent(
 									"event-child-task-a2-1",
 									NewAbsoluteDurationDelay(0),
-									map[string]string{
-										"attribute-key-event-child-task-a2-1": "attribute-value-event-child-task-a2-1",
+									map[string]attribute.Expression{
+										"attribute-key-event-child-task-a2-1": attribute.String("attribute-value-event-child-task-a2-1"),
 									},
 								),
 							},
@@ -115,8 +117,8 @@ func TestSimulator_Run(t *testing.T) {
 						task.NewEvent(
 							"event-root-task-b-1",
 							NewAbsoluteDurationDelay(0),
-							map[string]string{
-								"attribute-key-event-root-task-b-1": "attribute-value-event-root-task-b-1",
+							map[string]attribute.Expression{
+								"attribute-key-event-root-task-b-1": attribute.String("attribute-value-event-root-task-b-1"),
 							},
 						),
 					},
@@ -131,8 +133,8 @@ func TestSimulator_Run(t *testing.T) {
 								task.NewEvent(
 									"event-child-task-b1-1",
 									NewAbsoluteDurationDelay(0),
-									map[string]string{
-										"attribute-key-event-child-task-b1-1": "attribute-value-event-child-task-b1-1",
+									map[string]attribute.Expression{
+										"attribute-key-event-child-task-b1-1": attribute.String("attribute-value-event-child-task-b1-1"),
 									},
 								),
 							},
@@ -158,8 +160,8 @@ func TestSimulator_Run(t *testing.T) {
 						task.NewEvent(
 							"event-root-task-c-1",
 							NewAbsoluteDurationDelay(0),
-							map[string]string{
-								"attribute-key-event-root-task-c-1": "attribute-value-event-root-task-c-1",
+							map[string]attribute.Expression{
+								"attribute-key-event-root-task-c-1": attribute.String("attribute-value-event-root-task-c-1"),
 							},
 						),
 					},
@@ -181,8 +183,8 @@ func TestSimulator_Run(t *testing.T) {
 						task.NewEvent(
 							"event-root-task-d-1",
 							NewAbsoluteDurationDelay(0),
-							map[string]string{
-								"attribute-key-event-root-task-d-1": "attribute-value-event-root-task-d-1",
+							map[string]attribute.Expression{
+								"attribute-key-event-root-task-d-1": attribute.String("attribute-value-event-root-task-d-1"),
 							},
 						),
 					},
@@ -198,9 +200,9 @@ func TestSimulator_Run(t *testing.T) {
 				},
 			},
 		},
-	})
+	}, nil, nil)
 
-	sim := New[[]*span.TreeNode](&simulator.NoOpAdapter{})
+	sim := New[[]*span.TreeNode](&simulator.NoOpAdapter{}, random.NewSource(42))
 	traces, err := sim.Run(&blueprint, now)
 	assert.NoError(t, err)
 
@@ -214,8 +216,8 @@ func TestSimulator_Run(t *testing.T) {
 		assert.Equal(t, "root-task-a", rootA.Name())
 		assert.Equal(t, "service-a", rootAResource.Name())
 		assert.Equal(t, true, rootA.IsResourceEntryPoint())
-		assert.Equal(t, map[string]string{"env": "test"}, rootAResource.Attributes())
-		assert.Equal(t, map[string]string{"key1": "value1"}, rootA.Attributes())
+		assert.Equal(t, map[string]attribute.Expression{"env": attribute.String("test")}, rootAResource.Attributes())
+		assert.Equal(t, map[string]attribute.Value{"key1": attribute.String("value1")}, rootA.Attributes())
 		assert.Equal(t, span.KindServer, rootA.Kind())
 		assert.Equal(t, rootTaskAExternalID, rootA.ExternalID())
 
@@ -402,9 +404,9 @@ func TestSimulator_Run(t *testing.T) {
 					},
 				},
 			},
-		})
+		}, nil, nil)
 
-		sim := New[[]*span.TreeNode](&simulator.NoOpAdapter{})
+		sim := New[[]*span.TreeNode](&simulator.NoOpAdapter{}, random.NewSource(42))
 		_, err := sim.Run(&missingExternalIDBlueprint, time.Now())
 		assert.Errorf(t, err, "failed to link spans: linked span with external ID {%s} not found", missingExternalID)
 	})
@@ -433,8 +435,8 @@ func TestSimulator_Run(t *testing.T) {
 						},
 					},
 				},
-			})
-		sim := New[[]*span.TreeNode](&simulator.NoOpAdapter{})
+			}, nil, nil)
+		sim := New[[]*span.TreeNode](&simulator.NoOpAdapter{}, random.NewSource(42))
 		_, err := sim.Run(&duplicateExternalIDBlueprint, time.Now())
 		assert.Errorf(t, err, "failed to convert task tree to span:, duplicate external ID {%s}", duplicateExternalID)
 	})
@@ -466,15 +468,169 @@ func TestSimulator_Run(t *testing.T) {
 						},
 					},
 				},
-			})
+			}, nil, nil)
 
-		sim := New[[]*span.TreeNode](&simulator.NoOpAdapter{})
+		sim := New[[]*span.TreeNode](&simulator.NoOpAdapter{}, random.NewSource(42))
 		_, err := sim.Run(&duplicateExternalIDBlueprint, time.Now())
 		assert.Errorf(t, err, "failed to interpret blueprint: duplicate ExternalID detected: {%s}", duplicateExternalID)
 	})
 
+	t.Run("resolve trace variables once per trace", func(t *testing.T) {
+		rootExternalID, _ := task.NewExternalID("root")
+		userID, _ := attribute.ParseTemplate("${trace.user_id}")
+		route, _ := attribute.ParseTemplate("/users/${trace.user_id}")
+		variablesBlueprint := service.NewServiceBlueprint([]model.Service{
+			{
+				Name:     "frontend",
+				Resource: map[string]attribute.Expression{"enduser.id": userID},
+				Tasks: []model.Task{
+					{
+						Name:       "root",
+						ExternalID: rootExternalID,
+						Delay:      NewAbsoluteDurationDelay(0),
+						Duration:   NewAbsoluteDurationDuration(500 * time.Millisecond),
+						Kind:       "server",
+						Attributes: map[string]attribute.Expression{"enduser.id": userID},
+					},
+					{
+						Name:       "another-root",
+						Delay:      NewAbsoluteDurationDelay(0),
+						Duration:   NewAbsoluteDurationDuration(500 * time.Millisecond),
+						Kind:       "server",
+						Attributes: map[string]attribute.Expression{"enduser.id": userID},
+					},
+				},
+			},
+			{
+				Name: "backend",
+				Tasks: []model.Task{
+					{
+						Name:       "child",
+						Delay:      NewAbsoluteDurationDelay(0),
+						Duration:   NewAbsoluteDurationDuration(100 * time.Millisecond),
+						Kind:       "server",
+						ChildOf:    rootExternalID,
+						Attributes: map[string]attribute.Expression{"http.route": route},
+					},
+				},
+			},
+		}, map[string]attribute.Expression{"user_id": attribute.NewSequence(1, 1)}, nil)
+
+		sim := New[[]*span.TreeNode](&simulator.NoOpAdapter{}, random.NewSource(42))
+		traces, err := sim.Run(&variablesBlueprint, time.Now())
+		assert.NoError(t, err)
+		assert.Len(t, traces, 2)
+
+		root := traces[0]
+		assert.Equal(t, map[string]attribute.Value{"enduser.id": attribute.Int(1)}, root.ResourceAttributes())
+		assert.Equal(t, map[string]attribute.Value{"enduser.id": attribute.Int(1)}, root.Attributes())
+		assert.Equal(t, map[string]attribute.Value{"http.route": attribute.String("/users/1")}, root.Children()[0].Attributes())
+		assert.Equal(t, map[string]attribute.Value{"enduser.id": attribute.Int(2)}, traces[1].Attributes())
+	})
+
+	t.Run("skip links to spans dropped by timeouts", func(t *testing.T) {
+		publishExternalID, _ := task.NewExternalID("publish")
+		timeout := task.NewTimeout(100*time.Millisecond, task.TimeoutTruncate)
+		timeoutBlueprint := service.NewServiceBlueprint([]model.Service{
+			{
+				Name: "service-a",
+				Tasks: []model.Task{
+					{
+						Name:     "request",
+						Delay:    NewAbsoluteDurationDelay(0),
+						Duration: NewAbsoluteDurationDuration(500 * time.Millisecond),
+						Kind:     "server",
+						Timeout:  &timeout,
+						Children: []model.Task{
+							{
+								Name:       "publish",
+								ExternalID: publishExternalID,
+								Delay:      NewAbsoluteDurationDelay(200 * time.Millisecond),
+								Duration:   NewAbsoluteDurationDuration(100 * time.Millisecond),
+								Kind:       "producer",
+							},
+						},
+					},
+					{
+						Name:     "consume",
+						Delay:    NewAbsoluteDurationDelay(0),
+						Duration: NewAbsoluteDurationDuration(100 * time.Millisecond),
+						Kind:     "consumer",
+						LinkedTo: []*task.ExternalID{publishExternalID},
+					},
+				},
+			},
+		}, nil, nil)
+
+		sim := New[[]*span.TreeNode](&simulator.NoOpAdapter{}, random.NewSource(42))
+		traces, err := sim.Run(&timeoutBlueprint, time.Now())
+		assert.NoError(t, err)
+		assert.Len(t, traces, 2)
+		assert.Empty(t, traces[0].Children())
+		assert.Equal(t, span.StatusError(span.DeadlineExceededMessage), traces[0].Status())
+		assert.Empty(t, traces[1].LinkedTo())
+	})
+
+	t.Run("skew the clocks of the services after aligning the spans to the base time", func(t *testing.T) {
+		rootExternalID, _ := task.NewExternalID("root")
+		clockSkew, err := task.NewClockSkew(200*time.Millisecond, 200*time.Millisecond, nil)
+		assert.NoError(t, err)
+		skewBlueprint := service.NewServiceBlueprint([]model.Service{
+			{
+				Name: "frontend",
+				Tasks: []model.Task{
+					{
+						Name:       "root",
+						ExternalID: rootExternalID,
+						Delay:      NewAbsoluteDurationDelay(0),
+						Duration:   NewAbsoluteDurationDuration(time.Second),
+						Kind:       "server",
+					},
+				},
+			},
+			// the skewed service ends last, so it decides how far the trace is shifted
+			{
+				Name:      "backend",
+				ClockSkew: *clockSkew,
+				Tasks: []model.Task{
+					{
+						Name:     "child",
+						Delay:    NewAbsoluteDurationDelay(500 * time.Millisecond),
+						Duration: NewAbsoluteDurationDuration(time.Second),
+						Kind:     "server",
+						ChildOf:  rootExternalID,
+					},
+				},
+			},
+		}, nil, nil)
+
+		sim := New[[]*span.TreeNode](&simulator.NoOpAdapter{}, random.NewSource(42))
+		traces, err := sim.Run(&skewBlueprint, now)
+		assert.NoError(t, err)
+		assert.Len(t, traces, 1)
+		root := traces[0]
+		child := root.Children()[0]
+		// the unskewed spans end at the base time as if there were no skew
+		assert.Equal(t, now.Add(-1500*time.Millisecond), root.StartTime())
+		assert.Equal(t, now.Add(-500*time.Millisecond), root.EndTime())
+		// the skewed spans are off by exactly the offset against the unskewed ones
+		assert.Equal(t, 700*time.Millisecond, child.StartTime().Sub(root.StartTime()))
+		assert.Equal(t, now.Add(200*time.Millisecond), child.EndTime())
+	})
+
+	t.Run("shift spans to start at the base time", func(t *testing.T) {
+		sim := New[[]*span.TreeNode](&simulator.NoOpAdapter{}, random.NewSource(42))
+		traces, err := sim.RunStartingAt(&blueprint, now)
+		assert.NoError(t, err)
+		earliestStartTime := traces[0].StartTime()
+		for _, trace := range traces {
+			earliestStartTime = sim.findEarliestStartTime(trace, earliestStartTime)
+		}
+		assert.Equal(t, now, earliestStartTime)
+	})
+
 	t.Run("transform span trees to a different format using the adapter", func(t *testing.T) {
-		sim := New[[]string](&MockAdapter{})
+		sim := New[[]string](&MockAdapter{}, random.NewSource(42))
 		transformed, err := sim.Run(&blueprint, time.Now())
 		assert.NoError(t, err)
 		assert.Len(t, transformed, 3)
@@ -2,13 +2,19 @@ package tracesimulationreceiver
 
 import (
 	"context"
+	"github.com/k4ji/tracesimulationreceiver/internal/clock"
+	"github.com/k4ji/tracesimulationreceiver/internal/delivery"
+	"github.com/k4ji/tracesimulationreceiver/internal/ratecontrol"
 	simulator "github.com/k4ji/tracesimulationreceiver/internal/tracesimulator"
 	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/blueprint"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/scenario"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/receiver"
 	"go.uber.org/zap"
+	"slices"
+	"sync"
 	"time"
 )
 
@@ -19,53 +25,205 @@ type traceSimReceiver struct {
 	logger        *zap.Logger
 	nextConsumer  consumer.Traces
 	simulator     *simulator.Simulator[[]ptrace.Traces]
+	clock         clock.Clock
 	interval      time.Duration
 	endTimeOffset time.Duration
 	blueprint     blueprint.Blueprint
+	// rateController decides when traces arrive. If nil, traces are generated every interval.
+	rateController *ratecontrol.Controller
+	// timeline applies scenarios to the blueprint while the receiver runs. If nil, no scenario is applied.
+	timeline *scenario.Timeline
+	// delivery decides when the generated spans are handed to the next consumer.
+	delivery deliverySettings
+	// backfill generates the traces of a past time range first. If nil, traces are generated from the time the receiver starts.
+	backfill *backfill
+	// startedAt is the time from which traces are generated, which the scenarios and rate profiles are relative to.
+	startedAt time.Time
+	// wg tracks the goroutines that generate and deliver traces, which Shutdown waits for.
+	wg sync.WaitGroup
+}
+
+// backfill is a range of past time whose traces are generated as fast as the next consumer accepts them.
+type backfill struct {
+	start time.Time
+	// end is the end of the range. If nil, the range ends at the time the receiver starts.
+	end *time.Time
+	// live keeps generating traces in real time once the backfill catches up, instead of stopping.
+	live bool
+}
+
+// deliverySettings decides when the generated spans are handed to the next consumer.
+type deliverySettings struct {
+	// realtime starts each trace at its arrival time and delivers each span when it ends, instead of each trace at once.
+	realtime bool
+	// batchInterval is the interval at which the spans ending in the realtime mode are delivered together.
+	batchInterval time.Duration
+	// shaper splits and delays the payloads, so that they arrive out of order. If nil, the payloads are delivered as they are.
+	shaper *delivery.Shaper
+}
+
+// schedule decides the times elapsed since the start at which traces arrive.
+type schedule interface {
+	// Next returns the time elapsed since the start at which the arrival following the arrival at previous occurs.
+	Next(previous time.Duration) time.Duration
+}
+
+// fixedInterval is a schedule where traces arrive every interval.
+type fixedInterval time.Duration
+
+func (i fixedInterval) Next(previous time.Duration) time.Duration {
+	return previous + time.Duration(i)
+}
+
+// arrivals are the arrivals of traces of a blueprint on a schedule.
+type arrivals struct {
+	blueprint blueprint.Blueprint
+	schedule  schedule
+	// next is the time elapsed since the start at which the next trace arrives.
+	next time.Duration
+	// scheduler delivers the traces in the order of their delivery times.
+	scheduler *delivery.Scheduler
 }
 
 func (r *traceSimReceiver) Start(ctx context.Context, _ component.Host) error {
 	ctx, r.cancel = context.WithCancel(ctx)
+	now := r.clock.Now()
+	r.startedAt = now
+	var end *time.Time
+	if r.backfill != nil {
+		r.startedAt = r.backfill.start
+		if !r.backfill.live {
+			// the backfill never waits for the time to come, even if the end has not passed since the configuration was validated
+			end = &now
+			if r.backfill.end != nil && r.backfill.end.Before(now) {
+				end = r.backfill.end
+			}
+		}
+	}
 
-	go func() {
-		ticker := time.NewTicker(r.interval)
-		defer ticker.Stop()
+	// flows with their own rate are generated apart from the rest of the blueprint
+	var schedules []*arrivals
+	for _, flow := range r.blueprint.DedicatedFlows() {
+		schedules = append(schedules, &arrivals{blueprint: flow.Blueprint, schedule: flow.Rate, next: flow.Rate.Next(0)})
+	}
+	if r.rateController != nil {
+		schedules = append(schedules, &arrivals{blueprint: r.blueprint, schedule: r.rateController, next: r.rateController.Next(0)})
+	} else {
+		schedules = append(schedules, &arrivals{blueprint: r.blueprint, schedule: fixedInterval(r.interval)})
+	}
 
-		if err := r.emitTracesOnce(ctx); err != nil {
-			return
+	deliver := func(td ptrace.Traces) { r.consume(ctx, td) }
+	for _, a := range schedules {
+		a.scheduler = delivery.NewScheduler(r.clock, deliver)
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			a.scheduler.Run(ctx)
+		}()
+	}
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.emitTracesOnSchedules(ctx, schedules, end)
+		if end != nil && ctx.Err() == nil {
+			r.logger.Info("Backfill completed, no more traces are generated", zap.Time("end", *end))
 		}
+	}()
 
-		for {
+	return nil
+}
+
+// emitTracesOnSchedules generates traces at the times decided by the schedules, until the end if any or the context is done.
+// The arrivals of all the schedules are generated one at a time in the order of their times, the earlier schedule first at the same time,
+// so that the randomness is drawn in the same order and the same seed yields the same traces.
+// Each trace ends at its own arrival time, and the arrivals that are already due are caught up as fast as the next consumer accepts them.
+// A schedule stops once generating its traces fails.
+func (r *traceSimReceiver) emitTracesOnSchedules(ctx context.Context, schedules []*arrivals, end *time.Time) {
+	for len(schedules) > 0 {
+		earliest := 0
+		for i, a := range schedules {
+			if a.next < schedules[earliest].next {
+				earliest = i
+			}
+		}
+		a := schedules[earliest]
+		at := r.startedAt.Add(a.next)
+		if end != nil && at.After(*end) {
+			// the following arrivals of the schedule are even later
+			schedules = slices.Delete(schedules, earliest, earliest+1)
+			continue
+		}
+		if wait := at.Sub(r.clock.Now()); wait > 0 {
 			select {
-			case <-ticker.C:
-				_ = r.emitTracesOnce(ctx)
+			case <-r.clock.After(wait):
 			case <-ctx.Done():
 				return
 			}
 		}
-	}()
-
-	return nil
+		if ctx.Err() != nil {
+			return
+		}
+		if err := r.emitTraces(a, at); err != nil {
+			// a blueprint that fails fails for the following arrivals too, which would only flood the logs
+			r.logger.Error("No more traces are generated for the flow since it failed", zap.Time("at", at))
+			schedules = slices.Delete(schedules, earliest, earliest+1)
+			continue
+		}
+		a.next = a.schedule.Next(a.next)
+	}
 }
 
-func (r *traceSimReceiver) emitTracesOnce(ctx context.Context) error {
-	traces, err := r.simulator.Run(r.blueprint, time.Now().Add(r.endTimeOffset))
+func (r *traceSimReceiver) emitTraces(a *arrivals, now time.Time) error {
+	bp := a.blueprint
+	if r.timeline != nil {
+		bp = r.timeline.Apply(bp, now.Sub(r.startedAt), now)
+	}
+	baseTime := now.Add(r.endTimeOffset)
+	var traces []ptrace.Traces
+	var err error
+	if r.delivery.realtime {
+		traces, err = r.simulator.RunStartingAt(bp, baseTime)
+	} else {
+		traces, err = r.simulator.Run(bp, baseTime)
+	}
 	if err != nil {
 		r.logger.Error("Error generating traces", zap.Error(err))
 		return err
 	}
 	for _, trace := range traces {
-		err = r.nextConsumer.ConsumeTraces(ctx, trace)
-		if err != nil {
-			r.logger.Error("Error sending traces", zap.Error(err))
+		// the data quality settings can drop all the spans of a trace, which leaves nothing to deliver
+		if trace.SpanCount() == 0 {
+			continue
+		}
+		batches := []delivery.Batch{{At: now, Traces: trace}}
+		if r.delivery.realtime {
+			batches = delivery.SplitByEndTime(trace, baseTime, r.delivery.batchInterval)
+		}
+		for _, batch := range batches {
+			payloads := []delivery.Batch{batch}
+			if r.delivery.shaper != nil {
+				payloads = r.delivery.shaper.Shape(batch)
+			}
+			for _, payload := range payloads {
+				if payload.Traces.SpanCount() > 0 {
+					a.scheduler.Schedule(payload)
+				}
+			}
 		}
 	}
 	return nil
 }
 
+func (r *traceSimReceiver) consume(ctx context.Context, td ptrace.Traces) {
+	if err := r.nextConsumer.ConsumeTraces(ctx, td); err != nil {
+		r.logger.Error("Error sending traces", zap.Error(err))
+	}
+}
+
 func (r *traceSimReceiver) Shutdown(_ context.Context) error {
 	if r.cancel != nil {
 		r.cancel()
 	}
+	r.wg.Wait()
 	return nil
 }
@@ -0,0 +1,467 @@
+package tracesimulationreceiver
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/k4ji/tracesimulationreceiver/internal/clock"
+	"github.com/k4ji/tracesimulationreceiver/internal/config"
+	"github.com/k4ji/tracesimulationreceiver/internal/config/blueprint/service"
+	"github.com/k4ji/tracesimulationreceiver/internal/config/global"
+	"github.com/k4ji/tracesimulationreceiver/internal/config/scenario"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/blueprint"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/attribute"
+	"github.com/k4ji/tracesimulationreceiver/internal/tracesimulator/model/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func newTestConfig() *config.Config {
+	cfg := createDefaultConfig().(*config.Config)
+	value, mode := "10ms", "absolute"
+	zero := "0"
+	cfg.Blueprint.ServiceBlueprint.Services = []service.Service{
+		{
+			Name: "service",
+			SpanDefinitions: []service.SpanDefinition{
+				{
+					Name:     "request",
+					Kind:     "server",
+					Delay:    &service.Delay{Value: &zero, Mode: &mode},
+					Duration: &service.Duration{Value: &value, Mode: &mode},
+				},
+			},
+		},
+	}
+	return cfg
+}
+
+func TestReceiverEmitsTracesAtRate(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.Global.Rate = &global.Rate{TracesPerSecond: 100, Arrival: "constant"}
+	c := clock.NewFake(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	sink := new(consumertest.TracesSink)
+
+	rcvr, err := NewFactory().CreateTraces(context.Background(), receivertest.NewNopSettings(typ), cfg, sink)
+	require.NoError(t, err)
+	rcvr.(*traceSimReceiver).clock = c
+	require.NoError(t, rcvr.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, rcvr.Shutdown(context.Background())) }()
+	require.Eventually(t, func() bool { return c.Waiters() == 1 }, time.Second, time.Millisecond)
+	c.Advance(305 * time.Millisecond)
+	require.Eventually(t, func() bool { return sink.SpanCount() == 30 && c.Waiters() == 1 }, time.Second, time.Millisecond)
+
+	// each trace is generated separately and ends at its own arrival time
+	traces := sink.AllTraces()
+	endTimes := make(map[pcommon.Timestamp]struct{}, len(traces))
+	for _, td := range traces {
+		assert.Equal(t, 1, td.SpanCount())
+		endTimes[td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).EndTimestamp()] = struct{}{}
+	}
+	assert.Len(t, endTimes, len(traces))
+}
+
+func TestReceiverEmitsDedicatedFlowsAtTheirOwnRate(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.Global.Interval = time.Hour
+	spans := &cfg.Blueprint.ServiceBlueprint.Services[0].SpanDefinitions
+	healthCheck := (*spans)[0]
+	healthCheck.Name = "health_check"
+	healthCheck.Rate = &global.Rate{TracesPerSecond: 100}
+	*spans = append(*spans, healthCheck)
+	c := clock.NewFake(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	sink := new(consumertest.TracesSink)
+
+	rcvr, err := NewFactory().CreateTraces(context.Background(), receivertest.NewNopSettings(typ), cfg, sink)
+	require.NoError(t, err)
+	rcvr.(*traceSimReceiver).clock = c
+	require.NoError(t, rcvr.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, rcvr.Shutdown(context.Background())) }()
+	require.Eventually(t, func() bool { return c.Waiters() == 1 }, time.Second, time.Millisecond)
+	c.Advance(305 * time.Millisecond)
+	require.Eventually(t, func() bool { return sink.SpanCount() == 31 && c.Waiters() == 1 }, time.Second, time.Millisecond)
+
+	names := make(map[string]int)
+	for _, td := range sink.AllTraces() {
+		names[td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Name()]++
+	}
+	// the rest of the blueprint is generated once per interval
+	assert.Equal(t, 1, names["request"])
+	assert.Equal(t, 30, names["health_check"])
+}
+
+func TestReceiverWithSeedIsReproducible(t *testing.T) {
+	newConfig := func(seed int64) *config.Config {
+		cfg := newTestConfig()
+		cfg.Global.Seed = &seed
+		normal := "normal"
+		request := &cfg.Blueprint.ServiceBlueprint.Services[0].SpanDefinitions[0]
+		request.Duration = &service.Duration{
+			Mode:          &normal,
+			Distributions: service.Distributions{Normal: &service.NormalDistribution{Mean: 100 * time.Millisecond, StdDev: 20 * time.Millisecond}},
+		}
+		request.Attributes = service.Attributes{
+			"user.id":    map[string]any{"generator": "uuid"},
+			"item.count": map[string]any{"generator": "random_int", "min": 1, "max": 100},
+		}
+		request.ConditionalEffects = []service.ConditionalEffect{
+			{
+				Condition: service.Condition{Kind: "probabilistic", Probabilistic: &service.Probabilistic{Threshold: 0.5}},
+				Effects:   []service.Effect{{Kind: "mark_as_failed", MarkAsFailed: service.MarkAsFailed{Message: "failed"}}},
+			},
+		}
+		return cfg
+	}
+	generate := func(cfg *config.Config) [][]byte {
+		rcvr, err := NewFactory().CreateTraces(context.Background(), receivertest.NewNopSettings(typ), cfg, new(consumertest.TracesSink))
+		require.NoError(t, err)
+		r := rcvr.(*traceSimReceiver)
+		now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		var payloads [][]byte
+		for i := 0; i < 10; i++ {
+			traces, err := r.simulator.Run(r.blueprint, now.Add(time.Duration(i)*time.Second))
+			require.NoError(t, err)
+			for _, td := range traces {
+				payload, err := (&ptrace.ProtoMarshaler{}).MarshalTraces(td)
+				require.NoError(t, err)
+				payloads = append(payloads, payload)
+			}
+		}
+		return payloads
+	}
+
+	t.Run("same seed yields identical traces", func(t *testing.T) {
+		assert.Equal(t, generate(newConfig(42)), generate(newConfig(42)))
+	})
+
+	t.Run("different seeds yield different traces", func(t *testing.T) {
+		assert.NotEqual(t, generate(newConfig(42)), generate(newConfig(43)))
+	})
+}
+
+func TestReceiverWithSeedDeliversIdenticalTraces(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	newConfig := func() *config.Config {
+		cfg := newTestConfig()
+		seed := int64(42)
+		cfg.Global.Seed = &seed
+		cfg.Global.Interval = time.Hour
+		// the payloads are delayed by less than the time between the end of the backfill and now, so all of them are due
+		end := "2024-12-31T23:59:55Z"
+		cfg.Global.Backfill = &global.Backfill{StartTime: "2024-12-31T23:59:45Z", EndTime: &end}
+		cfg.Global.Delivery = &global.Delivery{
+			Delay:           &global.DeliveryDelay{Min: 0, Max: time.Second},
+			SplitByResource: true,
+			ShuffleParts:    2,
+		}
+		from := time.Duration(0)
+		cfg.Scenarios = []scenario.Scenario{
+			{Name: "outage", From: &from, Services: []scenario.ServiceOverlay{{Name: "service", ErrorRate: 0.5}}},
+		}
+		spans := &cfg.Blueprint.ServiceBlueprint.Services[0].SpanDefinitions
+		request := (*spans)[0]
+		for _, name := range []string{"checkout", "search"} {
+			flow := request
+			flow.Name = name
+			flow.Rate = &global.Rate{TracesPerSecond: 100, Arrival: "poisson"}
+			flow.Children = []service.SpanDefinition{{Name: "query", Kind: "client", Delay: request.Delay, Duration: request.Duration}}
+			*spans = append(*spans, flow)
+		}
+		return cfg
+	}
+	generate := func() [][]byte {
+		core, logs := observer.New(zap.InfoLevel)
+		settings := receivertest.NewNopSettings(typ)
+		settings.Logger = zap.New(core)
+		sink := new(consumertest.TracesSink)
+		rcvr, err := NewFactory().CreateTraces(context.Background(), settings, newConfig(), sink)
+		require.NoError(t, err)
+		rcvr.(*traceSimReceiver).clock = clock.NewFake(now)
+		require.NoError(t, rcvr.Start(context.Background(), componenttest.NewNopHost()))
+		defer func() { require.NoError(t, rcvr.Shutdown(context.Background())) }()
+
+		require.Eventually(t, func() bool {
+			return logs.FilterMessage("Backfill completed, no more traces are generated").Len() == 1
+		}, 5*time.Second, time.Millisecond)
+		var payloads [][]byte
+		for _, td := range sink.AllTraces() {
+			payload, err := (&ptrace.ProtoMarshaler{}).MarshalTraces(td)
+			require.NoError(t, err)
+			payloads = append(payloads, payload)
+		}
+		return payloads
+	}
+
+	first := generate()
+	// both flows arrive about 1000 times during the backfill
+	assert.Greater(t, len(first), 1000)
+	assert.Equal(t, first, generate())
+}
+
+func TestReceiverBackfill(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	start := func(t *testing.T, cfg *config.Config, c clock.Clock, sink *consumertest.TracesSink) {
+		rcvr, err := NewFactory().CreateTraces(context.Background(), receivertest.NewNopSettings(typ), cfg, sink)
+		require.NoError(t, err)
+		rcvr.(*traceSimReceiver).clock = c
+		require.NoError(t, rcvr.Start(context.Background(), componenttest.NewNopHost()))
+		t.Cleanup(func() { require.NoError(t, rcvr.Shutdown(context.Background())) })
+	}
+	endTimes := func(sink *consumertest.TracesSink) []time.Time {
+		var times []time.Time
+		for _, td := range sink.AllTraces() {
+			times = append(times, td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).EndTimestamp().AsTime())
+		}
+		return times
+	}
+
+	t.Run("generates the traces of the range and stops", func(t *testing.T) {
+		cfg := newTestConfig()
+		cfg.Global.Interval = time.Minute
+		end := "2024-12-31T23:10:00Z"
+		cfg.Global.Backfill = &global.Backfill{StartTime: "2024-12-31T23:00:00Z", EndTime: &end}
+		c := clock.NewFake(now)
+		sink := new(consumertest.TracesSink)
+		start(t, cfg, c, sink)
+
+		require.Eventually(t, func() bool { return sink.SpanCount() == 11 }, time.Second, time.Millisecond)
+		times := endTimes(sink)
+		for i, endTime := range times {
+			assert.Equal(t, now.Add(-time.Hour).Add(time.Duration(i)*time.Minute), endTime)
+		}
+		// nothing is waited for once the backfill completes
+		c.Advance(time.Hour)
+		assert.Equal(t, 0, c.Waiters())
+		assert.Equal(t, 11, sink.SpanCount())
+	})
+
+	t.Run("stops at the current time if the end is later", func(t *testing.T) {
+		cfg := newTestConfig()
+		cfg.Global.Interval = time.Minute
+		end := "2025-01-01T00:10:00Z"
+		cfg.Global.Backfill = &global.Backfill{StartTime: "2024-12-31T23:55:00Z", EndTime: &end}
+		c := clock.NewFake(now)
+		sink := new(consumertest.TracesSink)
+		start(t, cfg, c, sink)
+
+		require.Eventually(t, func() bool { return sink.SpanCount() == 6 }, time.Second, time.Millisecond)
+		assert.Equal(t, now, endTimes(sink)[5])
+		c.Advance(time.Hour)
+		assert.Equal(t, 0, c.Waiters())
+		assert.Equal(t, 6, sink.SpanCount())
+	})
+
+	t.Run("catches up with the current time and runs live", func(t *testing.T) {
+		cfg := newTestConfig()
+		cfg.Global.Rate = &global.Rate{TracesPerSecond: 1}
+		cfg.Global.Backfill = &global.Backfill{StartTime: "2024-12-31T23:59:50Z", Then: global.BackfillThenLive}
+		c := clock.NewFake(now)
+		sink := new(consumertest.TracesSink)
+		start(t, cfg, c, sink)
+
+		require.Eventually(t, func() bool { return sink.SpanCount() == 10 && c.Waiters() == 1 }, time.Second, time.Millisecond)
+		c.Advance(time.Second)
+		require.Eventually(t, func() bool { return sink.SpanCount() == 11 }, time.Second, time.Millisecond)
+		assert.Equal(t, now.Add(time.Second), endTimes(sink)[10])
+	})
+}
+
+func TestReceiverDeliversSpansInRealtime(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	cfg := newTestConfig()
+	cfg.Global.Interval = time.Hour
+	cfg.Global.Delivery = &global.Delivery{Mode: global.DeliveryModeRealtime}
+	requestValue, value, mode := "100ms", "30ms", "absolute"
+	request := &cfg.Blueprint.ServiceBlueprint.Services[0].SpanDefinitions[0]
+	request.Duration = &service.Duration{Value: &requestValue, Mode: &mode}
+	request.Children = []service.SpanDefinition{
+		{
+			Name:     "query",
+			Kind:     "client",
+			Delay:    request.Delay,
+			Duration: &service.Duration{Value: &value, Mode: &mode},
+		},
+	}
+	c := clock.NewFake(now)
+	sink := new(consumertest.TracesSink)
+
+	rcvr, err := NewFactory().CreateTraces(context.Background(), receivertest.NewNopSettings(typ), cfg, sink)
+	require.NoError(t, err)
+	rcvr.(*traceSimReceiver).clock = c
+	require.NoError(t, rcvr.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, rcvr.Shutdown(context.Background())) }()
+
+	// the trace starts now, and its spans are delivered as they end, while the next trace is waited for
+	require.Eventually(t, func() bool { return c.Waiters() == 2 }, time.Second, time.Millisecond)
+	assert.Equal(t, 0, sink.SpanCount())
+
+	c.Advance(30 * time.Millisecond)
+	require.Eventually(t, func() bool { return sink.SpanCount() == 1 }, time.Second, time.Millisecond)
+	query := sink.AllTraces()[0].ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+	assert.Equal(t, "query", query.Name())
+	assert.Equal(t, now, query.StartTimestamp().AsTime())
+
+	c.Advance(70 * time.Millisecond)
+	require.Eventually(t, func() bool { return sink.SpanCount() == 2 }, time.Second, time.Millisecond)
+	assert.Equal(t, "request", sink.AllTraces()[1].ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Name())
+}
+
+func TestReceiverShapesDelivery(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	cfg := newTestConfig()
+	cfg.Global.Interval = time.Hour
+	cfg.Global.Delivery = &global.Delivery{
+		Delay:           &global.DeliveryDelay{Min: time.Second, Max: time.Second},
+		SplitByResource: true,
+	}
+	ref := "request"
+	request := &cfg.Blueprint.ServiceBlueprint.Services[0].SpanDefinitions[0]
+	request.Ref = &ref
+	cfg.Blueprint.ServiceBlueprint.Services = append(cfg.Blueprint.ServiceBlueprint.Services, service.Service{
+		Name: "backend",
+		SpanDefinitions: []service.SpanDefinition{
+			{
+				Name:     "query",
+				Kind:     "client",
+				Parent:   &ref,
+				Delay:    request.Delay,
+				Duration: request.Duration,
+			},
+		},
+	})
+	c := clock.NewFake(now)
+	sink := new(consumertest.TracesSink)
+
+	rcvr, err := NewFactory().CreateTraces(context.Background(), receivertest.NewNopSettings(typ), cfg, sink)
+	require.NoError(t, err)
+	rcvr.(*traceSimReceiver).clock = c
+	require.NoError(t, rcvr.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, rcvr.Shutdown(context.Background())) }()
+
+	// the spans of each service are delivered separately after the delay, while the next trace is waited for
+	require.Eventually(t, func() bool { return c.Waiters() == 2 }, time.Second, time.Millisecond)
+	assert.Equal(t, 0, sink.SpanCount())
+
+	c.Advance(time.Second)
+	require.Eventually(t, func() bool { return len(sink.AllTraces()) == 2 }, time.Second, time.Millisecond)
+	var services []string
+	for _, td := range sink.AllTraces() {
+		assert.Equal(t, 1, td.ResourceSpans().Len())
+		assert.Equal(t, 1, td.SpanCount())
+		name, _ := td.ResourceSpans().At(0).Resource().Attributes().Get("service.name")
+		services = append(services, name.Str())
+	}
+	// the payloads delivered at the same time keep the order they are split in
+	assert.Equal(t, []string{"service", "backend"}, services)
+}
+
+func TestReceiverShutdownWaitsForDeliveries(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	cfg := newTestConfig()
+	cfg.Global.Interval = time.Hour
+	cfg.Global.Delivery = &global.Delivery{Delay: &global.DeliveryDelay{Min: time.Second, Max: time.Second}}
+	c := clock.NewFake(now)
+	var delivering, delivered atomic.Bool
+	next, err := consumer.NewTraces(func(ctx context.Context, _ ptrace.Traces) error {
+		delivering.Store(true)
+		// the delivery only completes once Shutdown has been called
+		<-ctx.Done()
+		delivered.Store(true)
+		return nil
+	})
+	require.NoError(t, err)
+
+	rcvr, err := NewFactory().CreateTraces(context.Background(), receivertest.NewNopSettings(typ), cfg, next)
+	require.NoError(t, err)
+	rcvr.(*traceSimReceiver).clock = c
+	require.NoError(t, rcvr.Start(context.Background(), componenttest.NewNopHost()))
+	require.Eventually(t, func() bool { return c.Waiters() == 2 }, time.Second, time.Millisecond)
+	c.Advance(time.Second)
+	require.Eventually(t, delivering.Load, time.Second, time.Millisecond)
+
+	// the delivery in progress completes before Shutdown returns
+	require.NoError(t, rcvr.Shutdown(context.Background()))
+	assert.True(t, delivered.Load())
+}
+
+// failingBlueprint is a blueprint that always fails to be interpreted.
+type failingBlueprint struct{}
+
+func (failingBlueprint) Interpret() ([]*task.TreeNode, error) {
+	return nil, errors.New("broken blueprint")
+}
+
+func (failingBlueprint) Variables() map[string]attribute.Expression {
+	return nil
+}
+
+func (failingBlueprint) DedicatedFlows() []blueprint.DedicatedFlow {
+	return nil
+}
+
+func TestReceiverStopsFailingSchedule(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.Global.Rate = &global.Rate{TracesPerSecond: 100}
+	c := clock.NewFake(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	core, logs := observer.New(zap.InfoLevel)
+	settings := receivertest.NewNopSettings(typ)
+	settings.Logger = zap.New(core)
+
+	rcvr, err := NewFactory().CreateTraces(context.Background(), settings, cfg, new(consumertest.TracesSink))
+	require.NoError(t, err)
+	r := rcvr.(*traceSimReceiver)
+	r.clock = c
+	r.blueprint = failingBlueprint{}
+	require.NoError(t, rcvr.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, rcvr.Shutdown(context.Background())) }()
+
+	require.Eventually(t, func() bool { return c.Waiters() == 1 }, time.Second, time.Millisecond)
+	// 100 arrivals are due, but the first failure stops the schedule, so nothing is waited for anymore
+	c.Advance(time.Second)
+	require.Eventually(t, func() bool { return c.Waiters() == 0 && logs.Len() == 2 }, time.Second, time.Millisecond)
+	assert.Equal(t, 1, logs.FilterMessage("Error generating traces").Len())
+	assert.Equal(t, 1, logs.FilterMessage("No more traces are generated for the flow since it failed").Len())
+	assert.Equal(t, 2, logs.Len())
+}
+
+func TestReceiverSkipsTracesWithoutSpans(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		delivery *global.Delivery
+	}{
+		{name: "without shaper"},
+		{name: "with shaper", delivery: &global.Delivery{Delay: &global.DeliveryDelay{Min: time.Second, Max: time.Second}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := newTestConfig()
+			cfg.Global.Interval = time.Hour
+			cfg.Global.Delivery = tc.delivery
+			// all the spans of the service are lost
+			cfg.Blueprint.ServiceBlueprint.Services[0].DataQuality = &service.ServiceDataQuality{LossProbability: 1}
+			c := clock.NewFake(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+			sink := new(consumertest.TracesSink)
+
+			rcvr, err := NewFactory().CreateTraces(context.Background(), receivertest.NewNopSettings(typ), cfg, sink)
+			require.NoError(t, err)
+			rcvr.(*traceSimReceiver).clock = c
+			require.NoError(t, rcvr.Start(context.Background(), componenttest.NewNopHost()))
+			defer func() { require.NoError(t, rcvr.Shutdown(context.Background())) }()
+
+			// only the next trace is waited for, since nothing is scheduled to be delivered
+			require.Eventually(t, func() bool { return c.Waiters() == 1 }, time.Second, time.Millisecond)
+			c.Advance(time.Second)
+			assert.Empty(t, sink.AllTraces())
+		})
+	}
+}